@@ -1,22 +1,51 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"maps"
+	"os"
+	"palbaseiq/pkg/loader"
 	"palbaseiq/pkg/optimizer"
+	"palbaseiq/pkg/pathing"
 	"palbaseiq/pkg/types"
+	"slices"
+	"strings"
 )
 
 func main() {
+	itemsPath := flag.String("items", "", "CSV/TSV item manifest (id,type,width,height,depth,rotation,priority)")
+	structuresPath := flag.String("structures", "", "JSON structure table replacing the built-in structure definitions")
+	showTraffic := flag.Bool("traffic", false, "print a heatmap of Palbox-to-item path traffic")
+	techLevel := flag.Int("tech", 0, "player technology level; later structures are swapped for earlier ones (0 = everything unlocked)")
+	showPlan := flag.Bool("plan", false, "print the build order with cumulative build work and materials")
+	flag.Parse()
+
 	fmt.Println("PalBaseIQ - Palworld Base Optimization System")
 	fmt.Println("=============================================")
 
+	// Replace the built-in structure table before any item is created
+	if *structuresPath != "" {
+		if err := loadStructures(*structuresPath); err != nil {
+			log.Fatalf("Loading structure definitions failed: %v", err)
+		}
+	}
+
 	// Create a base with dimensions based on your layout
 	// Assuming a 20x16x20 base (width x height x depth)
 	base := types.NewBase(20, 16, 20)
 
-	// Define items to place in the base
+	// Define items to place in the base, from a manifest if one was given
 	items := createBaseItems()
+	if *itemsPath != "" {
+		var err error
+		items, err = loadItems(*itemsPath)
+		if err != nil {
+			log.Fatalf("Loading items failed: %v", err)
+		}
+	}
 
 	// Create the placement optimizer
 	opt := optimizer.NewPlacementOptimizer(base)
@@ -27,6 +56,7 @@ func main() {
 	config.PathfindingWeight = 0.4
 	config.EfficiencyWeight = 0.3
 	config.CompactnessWeight = 0.3
+	config.MaxTechLevel = *techLevel
 
 	fmt.Println("Starting base optimization...")
 	fmt.Printf("Base dimensions: %dx%dx%d\n", base.Width, base.Height, base.Depth)
@@ -34,10 +64,11 @@ func main() {
 	fmt.Printf("Optimization iterations: %d\n", config.MaxIterations)
 
 	// Run optimization
-	optimizedBase, score, err := opt.OptimizePlacement(items, config)
+	result, err := opt.OptimizePlacement(items, config)
 	if err != nil {
 		log.Fatalf("Optimization failed: %v", err)
 	}
+	optimizedBase, score := result.Base, result.Score
 
 	// Display results
 	fmt.Println("\nOptimization Results:")
@@ -48,6 +79,47 @@ func main() {
 	fmt.Printf("Compactness Score: %.2f\n", score.CompactnessScore)
 	fmt.Printf("Occupancy: %.1f%%\n", optimizedBase.GetOccupancyPercentage())
 
+	// Warn about items that did not fit
+	if len(result.Unplaced) > 0 {
+		fmt.Printf("\nWarning: %d item(s) could not be placed:\n", len(result.Unplaced))
+		for _, item := range result.Unplaced {
+			fmt.Printf("  %s (%s)\n", item.ID, item.Type)
+		}
+	}
+
+	// Report lower-tier stand-ins for structures not unlocked yet
+	if len(result.Substituted) > 0 {
+		fmt.Printf("\n%d item(s) replaced for tech level %d:\n", len(result.Substituted), config.MaxTechLevel)
+		for _, item := range result.Substituted {
+			fmt.Printf("  %s built as %s\n", item.ID, item.Type)
+		}
+	}
+
+	// Report items left out to stay within the build work budget or the
+	// tech level
+	if len(result.Dropped) > 0 {
+		fmt.Printf("\n%d item(s) dropped to fit the build work budget or tech level:\n", len(result.Dropped))
+		for _, item := range result.Dropped {
+			fmt.Printf("  %s (%s)\n", item.ID, item.Type)
+		}
+	}
+
+	// Warn when the beds would go hungry
+	if len(result.Undersupplied) > 0 {
+		fmt.Println("\nWarning: too little food support for the Pal beds:")
+		for _, shortfall := range result.Undersupplied {
+			fmt.Printf("  %s: %d of %d recommended\n", shortfall.Structure, shortfall.Have, shortfall.Need)
+		}
+	}
+
+	// Warn about structures placed without what they depend on
+	if len(result.MissingDependencies) > 0 {
+		fmt.Println("\nWarning: structures placed without their dependencies:")
+		for _, missing := range result.MissingDependencies {
+			fmt.Printf("  %s needs a %s\n", missing.Item.ID, missing.Needs)
+		}
+	}
+
 	// Display item placements
 	fmt.Println("\nOptimized Item Placements:")
 	fmt.Println("==========================")
@@ -56,195 +128,126 @@ func main() {
 	}
 
 	// Analyze pathfinding
-	analyzePathfinding(optimizedBase, opt)
+	analyzePathfinding(optimizedBase)
+
+	if *showTraffic {
+		printTrafficHeatmap(optimizedBase)
+	}
+
+	if *showPlan {
+		printBuildPlan(optimizedBase)
+	}
 
 	fmt.Println("\nOptimization complete!")
 }
 
-// createBaseItems creates a list of items to place in the base
+// createBaseItems creates a list of items to place in the base. Bounds and
+// priorities come from the structure definitions.
 func createBaseItems() []*types.Item {
 	items := []*types.Item{
 		// Palbox (highest priority - must be placed first)
-		{
-			ID:       "palbox_1",
-			Type:     types.ItemTypePalbox,
-			Bounds:   types.BoundingBox{Width: 2, Height: 2, Depth: 2},
-			Priority: 100,
-		},
-
-		// Pal Beds (32 beds as shown in your layout)
-		{
-			ID:       "pal_bed_1",
-			Type:     types.ItemTypePalBed,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 90,
-		},
-		{
-			ID:       "pal_bed_2",
-			Type:     types.ItemTypePalBed,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 90,
-		},
-		{
-			ID:       "pal_bed_3",
-			Type:     types.ItemTypePalBed,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 90,
-		},
-		{
-			ID:       "pal_bed_4",
-			Type:     types.ItemTypePalBed,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 90,
-		},
-		{
-			ID:       "pal_bed_5",
-			Type:     types.ItemTypePalBed,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 90,
-		},
-		{
-			ID:       "pal_bed_6",
-			Type:     types.ItemTypePalBed,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 90,
-		},
+		types.NewItem("palbox_1", types.StructureNamePalbox),
 
 		// Food Box
-		{
-			ID:       "food_box_1",
-			Type:     types.ItemTypeFoodBox,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 80,
-		},
-
-		// Food Plots (4 plots as shown in your layout)
-		{
-			ID:       "food_plot_1",
-			Type:     types.ItemTypeFoodPlot,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 75,
-		},
-		{
-			ID:       "food_plot_2",
-			Type:     types.ItemTypeFoodPlot,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 75,
-		},
-		{
-			ID:       "food_plot_3",
-			Type:     types.ItemTypeFoodPlot,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 75,
-		},
-		{
-			ID:       "food_plot_4",
-			Type:     types.ItemTypeFoodPlot,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 75,
-		},
-
-		// Power Generator
-		{
-			ID:       "power_generator_1",
-			Type:     types.ItemTypePowerGenerator,
-			Bounds:   types.BoundingBox{Width: 1, Height: 2, Depth: 1},
-			Priority: 85,
-		},
-
-		// Accumulator
-		{
-			ID:       "accumulator_1",
-			Type:     types.ItemTypeAccumulator,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 80,
-		},
+		types.NewItem("food_box_1", types.StructureNameFoodBox),
+
+		// Power Generator and Accumulator
+		types.NewItem("power_generator_1", types.StructureNamePowerGenerator),
+		types.NewItem("accumulator_1", types.StructureNameAccumulator),
 
 		// Additional items for a more complete base
-		{
-			ID:       "workbench_1",
-			Type:     types.ItemTypeWorkbench,
-			Bounds:   types.BoundingBox{Width: 2, Height: 1, Depth: 1},
-			Priority: 70,
-		},
-		{
-			ID:       "storage_1",
-			Type:     types.ItemTypeStorage,
-			Bounds:   types.BoundingBox{Width: 1, Height: 2, Depth: 1},
-			Priority: 65,
-		},
-		{
-			ID:       "furnace_1",
-			Type:     types.ItemTypeFurnace,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 60,
-		},
-		{
-			ID:       "cooking_pot_1",
-			Type:     types.ItemTypeCookingPot,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 70,
-		},
+		types.NewItem("workbench_1", types.StructureNameWorkbench),
+		types.NewItem("storage_1", types.StructureNameStorage),
+		types.NewItem("furnace_1", types.StructureNameFurnace),
+		types.NewItem("cooking_pot_1", types.StructureNameCookingPot),
+	}
+
+	// Food Plots (4 plots as shown in your layout)
+	for i := 1; i <= 4; i++ {
+		items = append(items, types.NewItem(fmt.Sprintf("food_plot_%d", i), types.StructureNameFoodPlot))
 	}
 
-	// Add more Pal Beds to reach 32 total
-	for i := 7; i <= 32; i++ {
-		items = append(items, &types.Item{
-			ID:       fmt.Sprintf("pal_bed_%d", i),
-			Type:     types.ItemTypePalBed,
-			Bounds:   types.BoundingBox{Width: 1, Height: 1, Depth: 1},
-			Priority: 90,
-		})
+	// Pal Beds (32 beds as shown in your layout)
+	for i := 1; i <= 32; i++ {
+		items = append(items, types.NewItem(fmt.Sprintf("pal_bed_%d", i), types.StructureNamePalBed))
 	}
 
 	return items
 }
 
+// loadItems reads the item manifest at path
+func loadItems(path string) ([]*types.Item, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return loader.LoadItemsCSV(file)
+}
+
+// loadStructures replaces the structure definitions with the table in the
+// JSON file at path
+func loadStructures(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	defs, err := types.LoadStructureDefinitions(file)
+	if err != nil {
+		return err
+	}
+	types.SetStructureDefinitions(defs)
+	return nil
+}
+
 // analyzePathfinding analyzes the pathfinding efficiency of the optimized base
-func analyzePathfinding(base *types.Base, optimizer *optimizer.PlacementOptimizer) {
+func analyzePathfinding(base *types.Base) {
 	fmt.Println("\nPathfinding Analysis:")
 	fmt.Println("=====================")
 
 	// Find the Palbox
-	var palbox *types.Item
-	for _, item := range base.Items {
-		if item.Type == types.ItemTypePalbox {
-			palbox = item
-			break
-		}
-	}
-
-	if palbox == nil {
+	palboxes := base.ItemsByName(types.StructureNamePalbox)
+	if len(palboxes) == 0 {
 		fmt.Println("No Palbox found!")
 		return
 	}
+	palbox := palboxes[0]
 
 	fmt.Printf("Palbox location: %s\n", palbox.Position)
 
+	graph := pathing.NewGraph(base)
+
 	// Analyze paths to key items
-	keyItems := []types.ItemType{
-		types.ItemTypeFoodBox,
-		types.ItemTypePowerGenerator,
-		types.ItemTypeWorkbench,
-		types.ItemTypeStorage,
+	keyItems := []types.StructureName{
+		types.StructureNameFoodBox,
+		types.StructureNamePowerGenerator,
+		types.StructureNameWorkbench,
+		types.StructureNameStorage,
 	}
 
 	totalPathCost := 0.0
 	reachableItems := 0
 
-	for _, itemType := range keyItems {
-		for _, item := range base.Items {
-			if item.Type == itemType {
-				path, err := optimizer.Graph.FindPath(palbox.Position, item.Position)
-				if err == nil {
-					fmt.Printf("Path to %s: %.2f cost (%d steps)\n", itemType, path.Cost, len(path.Nodes))
-					totalPathCost += path.Cost
-					reachableItems++
-				} else {
-					fmt.Printf("Path to %s: UNREACHABLE\n", itemType)
-				}
-				break // Only check first item of each type
-			}
+	for _, name := range keyItems {
+		items := base.ItemsByName(name)
+		if len(items) == 0 {
+			continue
+		}
+
+		// Only check first item of each type
+		path, err := findItemPath(graph, palbox, items[0])
+		switch {
+		case err == nil:
+			fmt.Printf("Path to %s: %.2f cost (%d steps)\n", name, path.Cost, len(path.Nodes))
+			totalPathCost += path.Cost
+			reachableItems++
+		case errors.Is(err, pathing.ErrNoPath):
+			fmt.Printf("Path to %s: UNREACHABLE (walled off)\n", name)
+		default:
+			fmt.Printf("Path to %s: UNREACHABLE (%v)\n", name, err)
 		}
 	}
 
@@ -255,56 +258,92 @@ func analyzePathfinding(base *types.Base, optimizer *optimizer.PlacementOptimize
 	}
 }
 
-// visualizeBase creates a simple text visualization of the base
-func visualizeBase(base *types.Base) {
-	fmt.Println("\nBase Visualization (Top-down view at Y=0):")
-	fmt.Println("=========================================")
+// findItemPath finds the cheapest path between two items. Item cells are
+// occupied, so when FindPath rejects an endpoint the path is searched
+// between the free cells next to each item instead.
+func findItemPath(graph *pathing.Graph, from, to *types.Item) (*pathing.Path, error) {
+	path, err := graph.FindPath(from.Position, to.Position)
+	if !errors.Is(err, pathing.ErrStartOccupied) && !errors.Is(err, pathing.ErrEndOccupied) {
+		return path, err
+	}
 
-	// Create a 2D representation of the base at ground level
-	grid := make([][]string, base.Width)
-	for x := range grid {
-		grid[x] = make([]string, base.Depth)
-		for z := range grid[x] {
-			grid[x][z] = "."
-		}
+	starts := graph.Base.AdjacentFreePositions(from)
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("%w: no free cell beside %s", pathing.ErrNoPath, from.ID)
+	}
+	targets := graph.Base.AdjacentFreePositions(to)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%w: no free cell beside %s", pathing.ErrNoPath, to.ID)
 	}
 
-	// Mark occupied positions
-	for _, item := range base.Items {
-		for _, pos := range item.GetOccupiedPositions() {
-			if pos.Y == 0 && pos.X >= 0 && pos.X < base.Width && pos.Z >= 0 && pos.Z < base.Depth {
-				// Use different symbols for different item types
-				symbol := "X"
-				switch item.Type {
-				case types.ItemTypePalbox:
-					symbol = "P"
-				case types.ItemTypePalBed:
-					symbol = "B"
-				case types.ItemTypeFoodBox:
-					symbol = "F"
-				case types.ItemTypeFoodPlot:
-					symbol = "G"
-				case types.ItemTypePowerGenerator:
-					symbol = "E"
-				case types.ItemTypeAccumulator:
-					symbol = "A"
-				case types.ItemTypeWorkbench:
-					symbol = "W"
-				case types.ItemTypeStorage:
-					symbol = "S"
-				}
-				grid[pos.X][pos.Z] = symbol
-			}
+	// Walk from whichever free cell beside from is cheapest
+	paths := graph.ShortestPathsFromAny(starts, targets)
+	var best *pathing.Path
+	for _, target := range targets {
+		if candidate, ok := paths[pathing.GetNodeKey(target)]; ok && (best == nil || candidate.Cost < best.Cost) {
+			best = candidate
 		}
 	}
+	if best == nil {
+		return nil, fmt.Errorf("%w between %s and %s", pathing.ErrNoPath, from.ID, to.ID)
+	}
+	return best, nil
+}
 
-	// Print the grid
-	for z := 0; z < base.Depth; z++ {
-		for x := 0; x < base.Width; x++ {
-			fmt.Print(grid[x][z], " ")
+// printTrafficHeatmap shows how often Palbox-to-item paths cross each cell
+// at ground level
+func printTrafficHeatmap(base *types.Base) {
+	fmt.Println("\nPath Traffic (Top-down view at Y=0):")
+	fmt.Println("====================================")
+
+	graph := pathing.NewGraph(base)
+	fmt.Print(graph.RenderTraffic(graph.PalboxTrafficHeatmap(), 0))
+
+	fmt.Println("\nLegend:")
+	fmt.Println("1-9 = Relative traffic")
+	fmt.Println("# = Occupied")
+	fmt.Println(". = No traffic")
+}
+
+// printBuildPlan prints the base's build order with each step's build work
+// and the running total
+func printBuildPlan(base *types.Base) {
+	fmt.Println("\nBuild Plan:")
+	fmt.Println("===========")
+	plan := base.BuildPlan()
+	for i, step := range plan {
+		if step.Warning != "" {
+			fmt.Printf("%2d. %s (%s): %s\n", i+1, step.Item.ID, step.Item.Type, step.Warning)
+			continue
 		}
-		fmt.Println()
+		fmt.Printf("%2d. %s (%s): +%d work, %d total\n", i+1, step.Item.ID, step.Item.Type, step.Work, step.TotalWork)
+		if len(step.Materials) > 0 {
+			fmt.Printf("    materials: %s\n", formatMaterials(step.Materials))
+		}
+	}
+
+	// Material costs come only from a loaded structure table
+	if len(plan) > 0 && len(plan[len(plan)-1].TotalMaterials) > 0 {
+		fmt.Printf("Total materials: %s\n", formatMaterials(plan[len(plan)-1].TotalMaterials))
+	}
+}
+
+// formatMaterials lists material amounts sorted by material name
+func formatMaterials(materials map[string]int) string {
+	names := slices.Sorted(maps.Keys(materials))
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%d %s", materials[name], name)
 	}
+	return strings.Join(parts, ", ")
+}
+
+// visualizeBase creates a simple text visualization of the base
+func visualizeBase(base *types.Base) {
+	fmt.Println("\nBase Visualization (Top-down view at Y=0):")
+	fmt.Println("=========================================")
+
+	fmt.Print(base.RenderLayer(0))
 
 	fmt.Println("\nLegend:")
 	fmt.Println("P = Palbox")
@@ -315,5 +354,9 @@ func visualizeBase(base *types.Base) {
 	fmt.Println("A = Accumulator")
 	fmt.Println("W = Workbench")
 	fmt.Println("S = Storage")
+	fmt.Println("X = Other structure")
+	fmt.Println("> v < ^ = Orientation of a multi-cell item (0, 90, 180, 270 degrees)")
+	fmt.Println("+ = Reserved walkway")
+	fmt.Println("# = Unbuildable")
 	fmt.Println(". = Empty space")
 }
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"palbaseiq/pkg/pathing"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestFindItemPath(t *testing.T) {
+	type placement struct {
+		id   string
+		name types.StructureName
+		pos  types.Position
+	}
+	barrels := func(positions ...types.Position) []placement {
+		var placements []placement
+		for _, pos := range positions {
+			placements = append(placements, placement{pathing.GetNodeKey(pos), types.StructureNameWoodenBarrel, pos})
+		}
+		return placements
+	}
+
+	tests := []struct {
+		name    string
+		extra   []placement
+		wantErr bool
+	}{
+		{"open ground", nil, false},
+		{"around a wall", barrels(types.Position{X: 3, Z: 1}, types.Position{X: 3, Z: 2}, types.Position{X: 3, Z: 3}), false},
+		{"walled off", barrels(types.Position{X: 3}, types.Position{X: 3, Z: 1}, types.Position{X: 3, Z: 2}, types.Position{X: 3, Z: 3}, types.Position{X: 3, Z: 4}), true},
+		{"nowhere to stand", barrels(types.Position{X: 5, Z: 1}, types.Position{X: 6, Z: 2}, types.Position{X: 5, Z: 3}, types.Position{X: 4, Z: 2}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(7, 1, 5)
+			placements := append([]placement{
+				{"source", types.StructureNameWoodenBarrel, types.Position{X: 0, Z: 2}},
+				{"barrel", types.StructureNameWoodenBarrel, types.Position{X: 5, Z: 2}},
+			}, tt.extra...)
+			for _, p := range placements {
+				item := types.NewItem(p.id, p.name)
+				item.Position = p.pos
+				if err := base.PlaceItem(item); err != nil {
+					t.Fatalf("placing %s: %v", p.id, err)
+				}
+			}
+			from, to := base.Items["source"], base.Items["barrel"]
+
+			path, err := findItemPath(pathing.NewGraph(base), from, to)
+			if tt.wantErr {
+				if !errors.Is(err, pathing.ErrNoPath) {
+					t.Errorf("error = %v, want ErrNoPath", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("findItemPath: %v", err)
+			}
+
+			first, last := path.Nodes[0], path.Nodes[len(path.Nodes)-1]
+			if first.Distance(from.Position) != 1 || last.Distance(to.Position) != 1 {
+				t.Errorf("path runs from %s to %s, want it to start beside %s and end beside %s",
+					first, last, from.Position, to.Position)
+			}
+			for _, pos := range path.Nodes {
+				if !base.IsTraversable(pos) {
+					t.Errorf("path crosses occupied %s", pos)
+				}
+			}
+		})
+	}
+}
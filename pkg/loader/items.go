@@ -0,0 +1,126 @@
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"palbaseiq/pkg/types"
+	"strconv"
+	"strings"
+)
+
+// itemColumns are the manifest columns in the order they must appear
+var itemColumns = []string{"id", "type", "width", "height", "depth", "rotation", "priority"}
+
+// LoadItemsCSV reads an item manifest with the columns id, type, width,
+// height, depth, rotation and priority. Fields may be separated by commas or
+// tabs (detected from the first line), and an optional header row naming the
+// columns is skipped. Each type must resolve to an entry of
+// StructureDefinitions. Errors report the manifest line they occurred on.
+func LoadItemsCSV(r io.Reader) ([]*types.Item, error) {
+	buffered := bufio.NewReader(r)
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = len(itemColumns)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	if firstLine, _ := buffered.Peek(buffered.Size()); isTabSeparated(firstLine) {
+		reader.Comma = '\t'
+	}
+
+	var items []*types.Item
+	seen := make(map[string]int)
+	first := true
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if first {
+			first = false
+			if isHeader(record) {
+				continue
+			}
+		}
+
+		item, err := parseItemRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if previous, duplicate := seen[item.ID]; duplicate {
+			return nil, fmt.Errorf("line %d: duplicate item id %q (first defined on line %d)", line, item.ID, previous)
+		}
+		seen[item.ID] = line
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// isTabSeparated reports whether the first line of the input uses tabs
+func isTabSeparated(data []byte) bool {
+	if end := bytes.IndexByte(data, '\n'); end >= 0 {
+		data = data[:end]
+	}
+	return bytes.IndexByte(data, '\t') >= 0
+}
+
+// isHeader reports whether the record names the manifest columns
+func isHeader(record []string) bool {
+	for i, column := range itemColumns {
+		if !strings.EqualFold(strings.TrimSpace(record[i]), column) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseItemRecord converts a manifest record into an item
+func parseItemRecord(record []string) (*types.Item, error) {
+	id := strings.TrimSpace(record[0])
+	if id == "" {
+		return nil, fmt.Errorf("missing item id")
+	}
+
+	itemType := types.ItemType(strings.TrimSpace(record[1]))
+	if _, err := itemType.StructureName(); err != nil {
+		return nil, err
+	}
+
+	var values [5]int
+	for i := range values {
+		column := itemColumns[i+2]
+		value, err := strconv.Atoi(strings.TrimSpace(record[i+2]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", column, record[i+2])
+		}
+		values[i] = value
+	}
+
+	bounds := types.BoundingBox{Width: values[0], Height: values[1], Depth: values[2]}
+	if bounds.Width <= 0 || bounds.Height <= 0 || bounds.Depth <= 0 {
+		return nil, fmt.Errorf("item %s has non-positive bounds %dx%dx%d", id, bounds.Width, bounds.Height, bounds.Depth)
+	}
+
+	rotation := values[3]
+	if rotation%90 != 0 || rotation < 0 || rotation >= 360 {
+		return nil, fmt.Errorf("item %s has invalid rotation %d", id, rotation)
+	}
+
+	return &types.Item{
+		ID:       id,
+		Type:     itemType,
+		Bounds:   bounds,
+		Rotation: rotation,
+		Priority: values[4],
+	}, nil
+}
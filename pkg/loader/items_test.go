@@ -0,0 +1,62 @@
+package loader
+
+import (
+	"palbaseiq/pkg/types"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadItemsCSV(t *testing.T) {
+	want := []*types.Item{
+		{ID: "pb", Type: "palbox", Bounds: types.BoundingBox{Width: 2, Height: 2, Depth: 2}, Priority: 100},
+		{ID: "wb", Type: "workbench", Bounds: types.BoundingBox{Width: 2, Height: 1, Depth: 1}, Rotation: 90, Priority: 70},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"comma separated", "pb,palbox,2,2,2,0,100\nwb,workbench,2,1,1,90,70\n"},
+		{"tab separated", "pb\tpalbox\t2\t2\t2\t0\t100\nwb\tworkbench\t2\t1\t1\t90\t70\n"},
+		{"header", "id,type,width,height,depth,rotation,priority\npb,palbox,2,2,2,0,100\nwb,workbench,2,1,1,90,70\n"},
+		{"comments and spaces", "# base items\npb, palbox, 2, 2, 2, 0, 100\nwb, workbench, 2, 1, 1, 90, 70\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := LoadItemsCSV(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("LoadItemsCSV: %v", err)
+			}
+			if !reflect.DeepEqual(items, want) {
+				t.Errorf("LoadItemsCSV() = %v, want %v", items, want)
+			}
+		})
+	}
+}
+
+func TestLoadItemsCSVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unknown type", "pb,palbox,2,2,2,0,100\nx,spaceship,1,1,1,0,1\n", "line 2"},
+		{"missing id", " ,palbox,2,2,2,0,100\n", "missing item id"},
+		{"bad number", "pb,palbox,two,2,2,0,100\n", "invalid width"},
+		{"zero bounds", "pb,palbox,0,2,2,0,100\n", "non-positive bounds"},
+		{"bad rotation", "pb,palbox,2,2,2,45,100\n", "invalid rotation"},
+		{"duplicate id", "pb,palbox,2,2,2,0,100\npb,palbox,2,2,2,0,100\n", "first defined on line 1"},
+		{"wrong column count", "pb,palbox,2,2,2,0\n", "wrong number of fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadItemsCSV(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadItemsCSV() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
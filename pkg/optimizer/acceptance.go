@@ -0,0 +1,85 @@
+package optimizer
+
+import (
+	"math"
+	"math/rand"
+)
+
+// IterationContext describes the state of the annealing run in which an
+// AcceptanceStrategy is asked to decide
+type IterationContext struct {
+	Iteration     int
+	MaxIterations int
+
+	// Temperature is the current temperature and InitialTemperature the
+	// one the schedule started from (and returns to on reheats)
+	Temperature        float64
+	InitialTemperature float64
+
+	// InitialScore is the score of the starting layout and BestScore the
+	// best score seen so far
+	InitialScore float64
+	BestScore    float64
+
+	// Rand is the optimizer's seeded random source
+	Rand *rand.Rand
+}
+
+// AcceptanceStrategy decides whether annealing moves from a layout scoring
+// current to a candidate scoring candidate. Higher scores are better.
+type AcceptanceStrategy interface {
+	Accept(current, candidate float64, ctx IterationContext) bool
+}
+
+// MetropolisAcceptance is the classic simulated annealing criterion: it
+// always accepts improvements and accepts a worse candidate with
+// probability exp(delta/temperature)
+type MetropolisAcceptance struct{}
+
+// Accept implements AcceptanceStrategy
+func (MetropolisAcceptance) Accept(current, candidate float64, ctx IterationContext) bool {
+	if candidate > current {
+		return true
+	}
+
+	// Calculate acceptance probability
+	delta := candidate - current
+	probability := math.Exp(delta / ctx.Temperature)
+
+	return ctx.Rand.Float64() < probability
+}
+
+// ThresholdAcceptance deterministically accepts any candidate that is worse
+// by less than a threshold. The threshold starts at Threshold and shrinks
+// with the temperature schedule, so a Threshold of zero is plain hill
+// climbing that only accepts strict improvements.
+type ThresholdAcceptance struct {
+	Threshold float64
+}
+
+// Accept implements AcceptanceStrategy
+func (t ThresholdAcceptance) Accept(current, candidate float64, ctx IterationContext) bool {
+	threshold := t.Threshold
+	if ctx.InitialTemperature > 0 {
+		threshold *= ctx.Temperature / ctx.InitialTemperature
+	}
+	return candidate-current > -threshold
+}
+
+// GreatDelugeAcceptance accepts improvements and any candidate scoring at
+// least the current water level. The level starts Tolerance below the
+// initial score and rises by RainSpeed every iteration, steadily narrowing
+// what is acceptable.
+type GreatDelugeAcceptance struct {
+	Tolerance float64
+	RainSpeed float64
+}
+
+// Accept implements AcceptanceStrategy
+func (g GreatDelugeAcceptance) Accept(current, candidate float64, ctx IterationContext) bool {
+	if candidate > current {
+		return true
+	}
+	level := ctx.InitialScore - g.Tolerance + g.RainSpeed*float64(ctx.Iteration)
+	return candidate >= level
+}
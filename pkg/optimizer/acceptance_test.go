@@ -0,0 +1,62 @@
+package optimizer
+
+import (
+	"math/rand"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestAcceptanceStrategies(t *testing.T) {
+	hot := IterationContext{Iteration: 10, Temperature: 50, InitialTemperature: 100, InitialScore: 10}
+	cold := IterationContext{Iteration: 90, Temperature: 1e-9, InitialTemperature: 100, InitialScore: 10}
+
+	tests := []struct {
+		name               string
+		strategy           AcceptanceStrategy
+		current, candidate float64
+		ctx                IterationContext
+		want               bool
+	}{
+		{"hill climbing accepts an improvement", ThresholdAcceptance{}, 10, 10.5, hot, true},
+		{"hill climbing rejects a tie", ThresholdAcceptance{}, 10, 10, hot, false},
+		{"hill climbing rejects a worse layout", ThresholdAcceptance{}, 10, 9.99, hot, false},
+		{"threshold accepts a small loss", ThresholdAcceptance{Threshold: 4}, 10, 9, hot, true},
+		{"threshold shrinks with the temperature", ThresholdAcceptance{Threshold: 4}, 10, 7, hot, false},
+		{"cold threshold is hill climbing", ThresholdAcceptance{Threshold: 4}, 10, 9.99, cold, false},
+		{"deluge accepts an improvement", GreatDelugeAcceptance{Tolerance: 1}, 5, 6, cold, true},
+		{"deluge accepts above the level", GreatDelugeAcceptance{Tolerance: 2, RainSpeed: 0.02}, 10, 9, hot, true},
+		{"deluge rejects below the risen level", GreatDelugeAcceptance{Tolerance: 2, RainSpeed: 0.02}, 10, 9, cold, false},
+		{"metropolis accepts an improvement", MetropolisAcceptance{}, 10, 11, cold, true},
+		{"cold metropolis rejects a loss", MetropolisAcceptance{}, 10, 9, cold, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.ctx.Rand = rand.New(rand.NewSource(1))
+			if got := tt.strategy.Accept(tt.current, tt.candidate, tt.ctx); got != tt.want {
+				t.Errorf("Accept(%v, %v) = %v, want %v", tt.current, tt.candidate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZeroThresholdIsHillClimbing(t *testing.T) {
+	config := testConfig()
+	config.AcceptanceStrategy = ThresholdAcceptance{}
+	config.RecordScoreHistory = true
+
+	result, err := NewPlacementOptimizer(types.NewBase(8, 3, 8)).OptimizePlacement(testItems(), config)
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+
+	// Only improvements are accepted, so the current layout never gets worse
+	for i := 1; i < len(result.CurrentScores); i++ {
+		if result.CurrentScores[i] < result.CurrentScores[i-1] {
+			t.Fatalf("iteration %d moved from %v to the worse %v", i, result.CurrentScores[i-1], result.CurrentScores[i])
+		}
+	}
+	if len(result.CurrentScores) == 0 {
+		t.Error("no score history recorded")
+	}
+}
@@ -0,0 +1,35 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// evaluateAlignment rewards items lined up in clean rows and columns. For
+// each movable item it takes the fraction of the other movable items that
+// share its X or Z coordinate, and returns the mean over all items in [0,1].
+func (po *PlacementOptimizer) evaluateAlignment(base *types.Base) float64 {
+	var items []*types.Item
+	for _, item := range base.SortedItems() {
+		if !item.IsFixed() {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) < 2 {
+		return 0.0
+	}
+
+	total := 0.0
+	for _, item := range items {
+		aligned := 0
+		for _, other := range items {
+			if other.ID == item.ID {
+				continue
+			}
+			if other.Position.X == item.Position.X || other.Position.Z == item.Position.Z {
+				aligned++
+			}
+		}
+		total += float64(aligned) / float64(len(items)-1)
+	}
+
+	return total / float64(len(items))
+}
@@ -0,0 +1,42 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestEvaluateAlignment(t *testing.T) {
+	layout := func(t *testing.T, positions ...types.Position) *types.Base {
+		base := types.NewBase(6, 2, 6)
+		for i, pos := range positions {
+			place(t, base, string(rune('a'+i)), types.StructureNameStorage, pos)
+		}
+		return base
+	}
+
+	// Both layouts span the same 4x4 box, so they are equally compact
+	aligned := layout(t, types.Position{X: 0, Z: 0}, types.Position{X: 3, Z: 0}, types.Position{X: 0, Z: 3}, types.Position{X: 3, Z: 3})
+	jittered := layout(t, types.Position{X: 0, Z: 0}, types.Position{X: 3, Z: 1}, types.Position{X: 1, Z: 3}, types.Position{X: 2, Z: 2})
+
+	po := NewPlacementOptimizer(aligned)
+	if a, j := po.evaluateCompactness(aligned), po.evaluateCompactness(jittered); a != j {
+		t.Fatalf("compactness differs: aligned %v, jittered %v", a, j)
+	}
+
+	tests := []struct {
+		name string
+		base *types.Base
+		want float64
+	}{
+		{"aligned", aligned, 2.0 / 3.0},
+		{"jittered", jittered, 0},
+		{"single item", layout(t, types.Position{X: 1, Z: 1}), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := po.evaluateAlignment(tt.base); got != tt.want {
+				t.Errorf("evaluateAlignment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// buildWork returns the build work needed for an item. Fixed features and
+// items without a structure definition cost nothing.
+func buildWork(item *types.Item) int {
+	if item.IsFixed() {
+		return 0
+	}
+	def, err := item.Type.Definition()
+	if err != nil {
+		return 0
+	}
+	return def.BuildWork
+}
+
+// selectWithinBudget picks the subset of items whose total build work fits
+// in budget while maximizing total priority, solving a 0/1 knapsack with
+// priority as value and build work as weight. Items that cost nothing are
+// always selected. Both returned slices keep the input order. A budget of
+// zero or less selects everything.
+func selectWithinBudget(items []*types.Item, budget int) (selected, dropped []*types.Item) {
+	if budget <= 0 {
+		return items, nil
+	}
+
+	totalWork := 0
+	for _, item := range items {
+		totalWork += buildWork(item)
+	}
+	if totalWork <= budget {
+		return items, nil
+	}
+
+	// best[w] is the highest value reachable with work w; take[i][w]
+	// records whether item i was taken to reach it
+	best := make([]int, budget+1)
+	take := make([][]bool, len(items))
+	for i, item := range items {
+		take[i] = make([]bool, budget+1)
+		work := buildWork(item)
+		if work == 0 {
+			continue
+		}
+		value := max(item.Priority, 0)
+		for w := budget; w >= work; w-- {
+			if candidate := best[w-work] + value; candidate > best[w] {
+				best[w] = candidate
+				take[i][w] = true
+			}
+		}
+	}
+
+	// Walk back from the full budget to recover the chosen items
+	chosen := make([]bool, len(items))
+	w := budget
+	for i := len(items) - 1; i >= 0; i-- {
+		work := buildWork(items[i])
+		if work == 0 {
+			chosen[i] = true
+			continue
+		}
+		if take[i][w] {
+			chosen[i] = true
+			w -= work
+		}
+	}
+
+	for i, item := range items {
+		if chosen[i] {
+			selected = append(selected, item)
+		} else {
+			dropped = append(dropped, item)
+		}
+	}
+
+	return selected, dropped
+}
@@ -0,0 +1,89 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"slices"
+	"testing"
+)
+
+func TestSelectWithinBudget(t *testing.T) {
+	items := func() []*types.Item {
+		rock := types.NewItem("rock", types.StructureNameWoodenBarrel)
+		rock.Environmental = true
+		return []*types.Item{
+			types.NewItem("palbox", types.StructureNamePalbox),       // 100 work
+			types.NewItem("furnace", types.StructureNameFurnace),     // 400 work
+			types.NewItem("bed1", types.StructureNamePalBed),         // 50 work
+			types.NewItem("bed2", types.StructureNamePalBed),         // 50 work
+			types.NewItem("workbench", types.StructureNameWorkbench), // 50 work
+			rock,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		budget      int
+		wantDropped []string
+	}{
+		{"no budget", 0, nil},
+		{"budget covers everything", 650, nil},
+		{"low budget drops the furnace", 300, []string{"furnace"}},
+		{"tight budget keeps the highest priorities", 200, []string{"furnace", "workbench"}},
+		{"budget below every item keeps only free items", 10, []string{"palbox", "furnace", "bed1", "bed2", "workbench"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := items()
+			selected, dropped := selectWithinBudget(all, tt.budget)
+
+			var gotDropped []string
+			for _, item := range dropped {
+				gotDropped = append(gotDropped, item.ID)
+			}
+			if !slices.Equal(gotDropped, tt.wantDropped) {
+				t.Errorf("dropped %v, want %v", gotDropped, tt.wantDropped)
+			}
+			if len(selected)+len(dropped) != len(all) {
+				t.Errorf("selected %d and dropped %d of %d items", len(selected), len(dropped), len(all))
+			}
+
+			if tt.budget > 0 {
+				work := 0
+				for _, item := range selected {
+					work += buildWork(item)
+				}
+				if work > tt.budget {
+					t.Errorf("selected %d build work, over the %d budget", work, tt.budget)
+				}
+			}
+		})
+	}
+}
+
+func TestOptimizePlacementBuildWorkBudget(t *testing.T) {
+	config := testConfig()
+	config.MaxBuildWork = 300
+	items := []*types.Item{
+		types.NewItem("palbox", types.StructureNamePalbox),
+		types.NewItem("furnace", types.StructureNameFurnace),
+		types.NewItem("bed1", types.StructureNamePalBed),
+		types.NewItem("bed2", types.StructureNamePalBed),
+	}
+
+	result, err := NewPlacementOptimizer(types.NewBase(8, 3, 8)).OptimizePlacement(items, config)
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+
+	if len(result.Dropped) != 1 || result.Dropped[0].ID != "furnace" {
+		t.Errorf("Dropped = %v, want only the furnace", result.Dropped)
+	}
+	for _, id := range []string{"palbox", "bed1", "bed2"} {
+		if _, ok := result.Base.Items[id]; !ok {
+			t.Errorf("%s was not placed", id)
+		}
+	}
+	if _, ok := result.Base.Items["furnace"]; ok {
+		t.Error("furnace was placed despite the budget")
+	}
+}
@@ -0,0 +1,53 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/pathing"
+	"palbaseiq/pkg/types"
+)
+
+// evaluateCommute rewards workstations that Pals can reach quickly from
+// their beds, in [0,1]. Every Production structure scores 1/cost, where
+// cost is the walk from a free cell beside the nearest Pal bed to the cell
+// the workstation is used from (see accessPositions) plus the step onto
+// it, so a workstation whose access cell touches a bed scores 1.
+// Unreachable workstations score 0, and the result is the mean over all
+// workstations. A base without beds or workstations scores 0.
+func (po *PlacementOptimizer) evaluateCommute(base *types.Base) float64 {
+	beds := base.ItemsByName(types.StructureNamePalBed)
+	workstations := base.ItemsByCategory(types.StructureCategoryProduction)
+	if len(beds) == 0 || len(workstations) == 0 {
+		return 0.0
+	}
+
+	var sources []types.Position
+	for _, bed := range beds {
+		sources = append(sources, base.AdjacentFreePositions(bed)...)
+	}
+
+	access := make([][]types.Position, len(workstations))
+	var targets []types.Position
+	for i, workstation := range workstations {
+		access[i] = accessPositions(base, workstation)
+		targets = append(targets, access[i]...)
+	}
+
+	// Search the evaluated layout with the optimizer's cost settings
+	graph := *po.Graph
+	graph.Base = base
+	distances := graph.NearestSourceDistance(targets, sources)
+
+	total := 0.0
+	for i := range workstations {
+		best, found := 0.0, false
+		for _, pos := range access[i] {
+			if cost, ok := distances[pathing.GetNodeKey(pos)]; ok && (!found || cost < best) {
+				best, found = cost, true
+			}
+		}
+		if found {
+			total += 1.0 / (1.0 + best)
+		}
+	}
+
+	return total / float64(len(workstations))
+}
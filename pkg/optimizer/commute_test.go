@@ -0,0 +1,68 @@
+package optimizer
+
+import (
+	"math"
+	"palbaseiq/pkg/pathing"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+// TestNearestBedDistance checks that a workbench between two beds is
+// reported at the distance of the nearer bed. The workbench is used from
+// its +X face, so the bed on that side is nearer.
+func TestNearestBedDistance(t *testing.T) {
+	base := types.NewBase(16, 2, 3)
+	near := place(t, base, "near", types.StructureNamePalBed, types.Position{X: 12, Z: 1})
+	far := place(t, base, "far", types.StructureNamePalBed, types.Position{X: 0, Z: 1})
+	bench := place(t, base, "bench", types.StructureNameWorkbench, types.Position{X: 6, Z: 1})
+	targets := accessPositions(base, bench)
+	graph := pathing.NewGraph(base)
+
+	nearest := func(beds ...*types.Item) float64 {
+		var sources []types.Position
+		for _, bed := range beds {
+			sources = append(sources, base.AdjacentFreePositions(bed)...)
+		}
+		best := math.Inf(1)
+		for _, cost := range graph.NearestSourceDistance(targets, sources) {
+			best = math.Min(best, cost)
+		}
+		return best
+	}
+
+	both, nearOnly, farOnly := nearest(near, far), nearest(near), nearest(far)
+	if both != nearOnly {
+		t.Errorf("distance from both beds = %v, want the near bed's %v", both, nearOnly)
+	}
+	if farOnly <= nearOnly {
+		t.Errorf("far bed distance %v is not above the near bed's %v", farOnly, nearOnly)
+	}
+}
+
+func TestEvaluateCommute(t *testing.T) {
+	tests := []struct {
+		name   string
+		bed    *types.Position
+		wantLo float64
+		wantHi float64
+	}{
+		{"no beds", nil, 0, 0},
+		{"bed beside the bench front", &types.Position{X: 4, Z: 1}, 1, 1},
+		{"bed behind the bench", &types.Position{X: 0, Z: 1}, 0.01, 0.99},
+		{"bed across the base", &types.Position{X: 11, Z: 1}, 0.01, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(12, 2, 3)
+			place(t, base, "bench", types.StructureNameWorkbench, types.Position{X: 1, Z: 1})
+			if tt.bed != nil {
+				place(t, base, "bed", types.StructureNamePalBed, *tt.bed)
+			}
+			po := NewPlacementOptimizer(base)
+			po.configure(testConfig())
+			if got := po.evaluateCommute(base); got < tt.wantLo || got > tt.wantHi {
+				t.Errorf("evaluateCommute() = %v, want within [%v, %v]", got, tt.wantLo, tt.wantHi)
+			}
+		})
+	}
+}
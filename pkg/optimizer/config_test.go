@@ -0,0 +1,138 @@
+package optimizer
+
+import (
+	"maps"
+	"math"
+	"palbaseiq/pkg/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *OptimizationConfig)
+		wantErr string
+	}{
+		{"default", func(*OptimizationConfig) {}, ""},
+		{"zero cooling rate", func(c *OptimizationConfig) { c.CoolingRate = 0 }, "CoolingRate 0 must be between 0 and 1, exclusive"},
+		{"cooling rate of one", func(c *OptimizationConfig) { c.CoolingRate = 1 }, "CoolingRate 1 must be between 0 and 1, exclusive"},
+		{"cooling rate above one", func(c *OptimizationConfig) { c.CoolingRate = 1.5 }, "CoolingRate 1.5 must be between 0 and 1, exclusive"},
+		{"zero iterations", func(c *OptimizationConfig) { c.MaxIterations = 0 }, "MaxIterations 0 must be positive"},
+		{"negative iterations", func(c *OptimizationConfig) { c.MaxIterations = -5 }, "MaxIterations -5 must be positive"},
+		{"negative duration", func(c *OptimizationConfig) { c.MaxDuration = -time.Second }, "MaxDuration -1s must not be negative"},
+		{"min temperature equal", func(c *OptimizationConfig) { c.MinTemperature = c.Temperature }, "MinTemperature 1000 must be below Temperature 1000"},
+		{"min temperature above", func(c *OptimizationConfig) { c.MinTemperature = 2000 }, "MinTemperature 2000 must be below Temperature 1000"},
+		{"negative weight", func(c *OptimizationConfig) { c.EfficiencyWeight = -0.1 }, "EfficiencyWeight -0.1 must not be negative"},
+		{"negative weight beside a large one", func(c *OptimizationConfig) { c.PathfindingWeight, c.EfficiencyWeight = 4, -1 }, "EfficiencyWeight -1 must not be negative"},
+		{"negative optional weight", func(c *OptimizationConfig) { c.LightWeight = -2 }, "LightWeight -2 must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Temperature = 1000
+			tt.modify(config)
+
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want it to mention %q", err, tt.wantErr)
+			}
+
+			// OptimizePlacement and Anneal refuse to start, quoting the
+			// values as set rather than normalized
+			if _, err := NewPlacementOptimizer(types.NewBase(4, 2, 4)).OptimizePlacement(testItems(), config); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("OptimizePlacement() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			base := types.NewBase(4, 2, 4)
+			place(t, base, "barrel", types.StructureNameWoodenBarrel, types.Position{X: 1, Z: 1})
+			if result, err := NewPlacementOptimizer(base).Anneal(base, nil, config); err == nil || result != nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Anneal() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigValidateReportsEveryProblem(t *testing.T) {
+	config := DefaultConfig()
+	config.CoolingRate = 2
+	config.MaxIterations = 0
+	config.PathfindingWeight = -1
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil")
+	}
+	for _, want := range []string{"CoolingRate", "MaxIterations", "PathfindingWeight"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, missing %s", err, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights [3]float64
+		want    [3]float64
+	}{
+		{"fractions", [3]float64{0.5, 0.25, 0.25}, [3]float64{0.5, 0.25, 0.25}},
+		{"whole numbers", [3]float64{2, 1, 1}, [3]float64{0.5, 0.25, 0.25}},
+		{"percentages", [3]float64{50, 25, 25}, [3]float64{0.5, 0.25, 0.25}},
+		{"one term", [3]float64{0, 7, 0}, [3]float64{0, 1, 0}},
+		{"all zero", [3]float64{0, 0, 0}, [3]float64{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &OptimizationConfig{
+				PathfindingWeight: tt.weights[0],
+				EfficiencyWeight:  tt.weights[1],
+				CompactnessWeight: tt.weights[2],
+			}
+			config.Normalize()
+			got := [3]float64{config.PathfindingWeight, config.EfficiencyWeight, config.CompactnessWeight}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-12 {
+					t.Errorf("normalized weights = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestProportionalWeightsGiveIdenticalLayouts(t *testing.T) {
+	layout := func(pathfinding, efficiency, compactness float64) map[string]types.Position {
+		config := testConfig()
+		for _, weight := range config.scoringWeights() {
+			*weight.value = 0
+		}
+		config.PathfindingWeight = pathfinding
+		config.EfficiencyWeight = efficiency
+		config.CompactnessWeight = compactness
+
+		result, err := NewPlacementOptimizer(types.NewBase(12, 6, 12)).OptimizePlacement(testItems(), config)
+		if err != nil {
+			t.Fatalf("OptimizePlacement: %v", err)
+		}
+		positions := make(map[string]types.Position)
+		for id, item := range result.Base.Items {
+			positions[id] = item.Position
+		}
+		return positions
+	}
+
+	want := layout(0.4, 0.3, 0.3)
+	for _, weights := range [][3]float64{{4, 3, 3}, {40, 30, 30}} {
+		if got := layout(weights[0], weights[1], weights[2]); !maps.Equal(got, want) {
+			t.Errorf("weights %v placed %v, weights (0.4, 0.3, 0.3) placed %v", weights, got, want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package optimizer
+
+import (
+	"container/heap"
+	"math"
+	"palbaseiq/pkg/pathing"
+	"palbaseiq/pkg/types"
+)
+
+// PlacementConstraint restricts or discourages placements. Violation is
+// called with the item at a candidate position and returns 0 when the
+// placement satisfies the constraint, +Inf when it is forbidden outright,
+// and a finite positive penalty when it is merely discouraged. Penalties are
+// subtracted from the position's score during placement.
+type PlacementConstraint interface {
+	Violation(base *types.Base, item *types.Item) float64
+}
+
+// MinSpacingConstraint keeps items of a category at least MinDistance
+// (Euclidean, between item positions) away from other items of the same
+// category. It is a hard constraint.
+type MinSpacingConstraint struct {
+	Category    types.StructureCategory
+	MinDistance float64
+}
+
+// Violation implements PlacementConstraint
+func (c MinSpacingConstraint) Violation(base *types.Base, item *types.Item) float64 {
+	if !hasCategory(item, c.Category) {
+		return 0.0
+	}
+
+	for _, other := range base.Items {
+		if other.ID == item.ID || !hasCategory(other, c.Category) {
+			continue
+		}
+		if item.Position.Distance(other.Position) < c.MinDistance {
+			return math.Inf(1)
+		}
+	}
+
+	return 0.0
+}
+
+// PreferNearConstraint softly pulls items of a category towards items of
+// type Near, for example food plots towards water. Each cell of distance
+// beyond MaxDistance to the closest Near item costs PenaltyPerCell. It never
+// forbids a placement, and does nothing while no Near item is placed.
+type PreferNearConstraint struct {
+	Category       types.StructureCategory
+	Near           types.ItemType
+	MaxDistance    float64
+	PenaltyPerCell float64
+}
+
+// Violation implements PlacementConstraint
+func (c PreferNearConstraint) Violation(base *types.Base, item *types.Item) float64 {
+	if !hasCategory(item, c.Category) {
+		return 0.0
+	}
+
+	nearest := math.Inf(1)
+	for _, other := range base.Items {
+		if other.ID != item.ID && other.Type == c.Near {
+			nearest = math.Min(nearest, item.Position.Distance(other.Position))
+		}
+	}
+
+	if math.IsInf(nearest, 1) || nearest <= c.MaxDistance {
+		return 0.0
+	}
+	return (nearest - c.MaxDistance) * c.PenaltyPerCell
+}
+
+// AccessConstraint keeps the access faces of items (see
+// Base.AccessFaceCells) usable. It forbids placing an item none of whose
+// access face cells is traversable and, when the base has a Palbox, can be
+// walked to from one, and placing an item over the last open face cell of
+// one already placed.
+type AccessConstraint struct {
+	// Graph supplies the movement plane and cost settings the walk to a
+	// Palbox is checked with, and caches the distance fields it uses; its
+	// Base is ignored. Nil checks with a default graph and no caching. The
+	// optimizer binds an unset Graph to its own.
+	Graph *pathing.Graph
+}
+
+// Violation implements PlacementConstraint
+func (c AccessConstraint) Violation(base *types.Base, item *types.Item) float64 {
+	if item.RequiredAccessSide() != types.SideNone && !c.accessFaceReachable(base, item) {
+		return math.Inf(1)
+	}
+
+	occupied := make(map[types.Position]bool)
+	item.ForEachOccupied(func(pos types.Position) {
+		occupied[pos] = true
+	})
+	for _, other := range base.SortedItems() {
+		if other.ID == item.ID || other.RequiredAccessSide() == types.SideNone {
+			continue
+		}
+
+		covered, open := false, false
+		for _, cell := range base.AccessFaceCells(other) {
+			if occupied[cell] {
+				covered = true
+			} else if base.IsTraversable(cell) {
+				open = true
+			}
+		}
+		if covered && !open {
+			return math.Inf(1)
+		}
+	}
+
+	return 0.0
+}
+
+// accessFaceReachable reports whether a Pal can stand on a cell of the
+// item's access face: one that is traversable and, unless the item is a
+// Palbox or the base has none, lies in the walkable space around a Palbox
+func (c AccessConstraint) accessFaceReachable(base *types.Base, item *types.Item) bool {
+	var open []types.Position
+	for _, cell := range base.AccessFaceCells(item) {
+		if base.IsTraversable(cell) {
+			open = append(open, cell)
+		}
+	}
+	if len(open) == 0 {
+		return false
+	}
+
+	palboxes := base.ItemsByName(types.StructureNamePalbox)
+	if len(palboxes) == 0 || hasName(item, types.StructureNamePalbox) {
+		return true
+	}
+
+	// A copy pointed at the evaluated base shares the cache, so every
+	// candidate of an item reuses one search per Palbox
+	graph := pathing.NewGraph(base)
+	if c.Graph != nil {
+		copied := *c.Graph
+		copied.Base = base
+		graph = &copied
+	}
+	own := make(map[types.Position]bool)
+	item.ForEachOccupied(func(pos types.Position) {
+		own[pos] = true
+	})
+	for _, palbox := range palboxes {
+		if walksTo(graph, graph.DistanceField(palbox.Position), open, own) {
+			return true
+		}
+	}
+	return false
+}
+
+// walksTo reports whether a Pal starting on any of starts reaches a cell at
+// distance 0 in field, one beside the Palbox the field was computed from,
+// without crossing the blocked cells of an item not yet placed, stepping
+// only as the graph's MovementPlane allows. The field ignores those cells,
+// so it never overestimates and guides a best-first search straight to the
+// Palbox unless the item walls the start off, in which case only the cut-off
+// pocket is explored.
+func walksTo(graph *pathing.Graph, field map[string]float64, starts []types.Position, blocked map[types.Position]bool) bool {
+	frontier := &fieldQueue{}
+	seen := make(map[types.Position]bool)
+	push := func(pos types.Position) {
+		if seen[pos] || blocked[pos] || !graph.Base.IsTraversable(pos) {
+			return
+		}
+		seen[pos] = true
+		if distance, ok := field[pathing.GetNodeKey(pos)]; ok {
+			heap.Push(frontier, fieldCell{pos: pos, distance: distance})
+		}
+	}
+	for _, start := range starts {
+		push(start)
+	}
+
+	for frontier.Len() > 0 {
+		current := heap.Pop(frontier).(fieldCell)
+		if current.distance == 0 {
+			return true
+		}
+		for _, next := range graph.GetNeighbors(current.pos) {
+			push(next)
+		}
+	}
+	return false
+}
+
+// fieldCell is a cell queued by walksTo with its distance-field value
+type fieldCell struct {
+	pos      types.Position
+	distance float64
+}
+
+// fieldQueue is a min-heap of cells by distance
+type fieldQueue []fieldCell
+
+func (q fieldQueue) Len() int           { return len(q) }
+func (q fieldQueue) Less(i, j int) bool { return q[i].distance < q[j].distance }
+func (q fieldQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *fieldQueue) Push(x any)        { *q = append(*q, x.(fieldCell)) }
+func (q *fieldQueue) Pop() any {
+	old := *q
+	cell := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return cell
+}
+
+// SupportConstraint forbids floating placements: an item above ground level
+// must stand on Foundation structures (see Base.HasSupportBelow)
+type SupportConstraint struct{}
+
+// Violation implements PlacementConstraint
+func (SupportConstraint) Violation(base *types.Base, item *types.Item) float64 {
+	if !base.HasSupportBelow(item) {
+		return math.Inf(1)
+	}
+	return 0.0
+}
+
+// ExposureConstraint forbids placing an item where its bottom layer is not
+// exposed as it requires (see Item.RequiredExposure and Base.IsIndoors),
+// such as crops under a roof
+type ExposureConstraint struct{}
+
+// Violation implements PlacementConstraint
+func (ExposureConstraint) Violation(base *types.Base, item *types.Item) float64 {
+	exposure := item.RequiredExposure()
+	if exposure == types.ExposureAny {
+		return 0.0
+	}
+
+	for _, pos := range item.GetOccupiedPositions() {
+		if pos.Y == item.Position.Y && base.IsIndoors(pos) != (exposure == types.ExposureIndoors) {
+			return math.Inf(1)
+		}
+	}
+	return 0.0
+}
+
+// hasName reports whether the item is the named structure
+func hasName(item *types.Item, name types.StructureName) bool {
+	own, err := item.Type.StructureName()
+	return err == nil && own == name
+}
+
+// hasCategory reports whether the item's structure belongs to the category
+func hasCategory(item *types.Item, category types.StructureCategory) bool {
+	def, err := item.Type.Definition()
+	return err == nil && def.Category == category
+}
+
+// constraintViolation sums the violations of every configured constraint
+// for the item at its current position. +Inf means the position is
+// forbidden.
+func (po *PlacementOptimizer) constraintViolation(base *types.Base, item *types.Item) float64 {
+	total := 0.0
+	for _, constraint := range po.Config.Constraints {
+		total += constraint.Violation(base, item)
+		if math.IsInf(total, 1) {
+			break
+		}
+	}
+	return total
+}
+
+// constraintsAllow reports whether no configured constraint forbids the
+// item at its current position
+func (po *PlacementOptimizer) constraintsAllow(base *types.Base, item *types.Item) bool {
+	return !math.IsInf(po.constraintViolation(base, item), 1)
+}
@@ -0,0 +1,308 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"palbaseiq/pkg/pathing"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestMinSpacingConstraint(t *testing.T) {
+	constraint := MinSpacingConstraint{Category: types.StructureCategoryFood, MinDistance: 3}
+	base := types.NewBase(8, 1, 8)
+	place(t, base, "plot", types.StructureNameFoodPlot, types.Position{X: 0, Z: 0})
+	place(t, base, "barrel", types.StructureNameWoodenBarrel, types.Position{X: 6, Z: 6})
+
+	tests := []struct {
+		name string
+		item types.StructureName
+		pos  types.Position
+		want float64
+	}{
+		{"too close", types.StructureNameFoodPlot, types.Position{X: 2, Z: 2}, math.Inf(1)},
+		{"exactly the minimum", types.StructureNameFoodPlot, types.Position{X: 3, Z: 0}, 0},
+		{"far enough", types.StructureNameFoodPlot, types.Position{X: 4, Z: 4}, 0},
+		{"other category", types.StructureNameWoodenBarrel, types.Position{X: 1, Z: 0}, 0},
+		{"next to another category", types.StructureNameFoodPlot, types.Position{X: 6, Z: 5}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := types.NewItem("candidate", tt.item)
+			item.Position = tt.pos
+			if got := constraint.Violation(base, item); got != tt.want {
+				t.Errorf("Violation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMinSpacingConstraintSpreadsFoodPlots(t *testing.T) {
+	const minDistance = 3.0
+	config := testConfig()
+	config.Constraints = append(config.Constraints, MinSpacingConstraint{
+		Category:    types.StructureCategoryFood,
+		MinDistance: minDistance,
+	})
+
+	var items []*types.Item
+	for i := 0; i < 5; i++ {
+		items = append(items, types.NewItem(fmt.Sprintf("plot%d", i), types.StructureNameFoodPlot))
+	}
+
+	result, err := NewPlacementOptimizer(types.NewBase(10, 2, 10)).OptimizePlacement(items, config)
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+	checkLayout(t, result.Base, items)
+
+	for i, a := range items {
+		for _, b := range items[i+1:] {
+			first, second := result.Base.Items[a.ID], result.Base.Items[b.ID]
+			if first == nil || second == nil {
+				continue
+			}
+			if d := first.Position.Distance(second.Position); d < minDistance {
+				t.Errorf("%s at %s and %s at %s are %.2f apart, want at least %v",
+					first.ID, first.Position, second.ID, second.Position, d, minDistance)
+			}
+		}
+	}
+}
+
+// constraintFunc adapts a function to PlacementConstraint
+type constraintFunc func(item *types.Item) float64
+
+// Violation implements PlacementConstraint
+func (f constraintFunc) Violation(_ *types.Base, item *types.Item) float64 {
+	return f(item)
+}
+
+func TestHardAndSoftConstraints(t *testing.T) {
+	target := types.Position{X: 1, Z: 6}
+	forbidWest := constraintFunc(func(item *types.Item) float64 {
+		if item.Position.X < 4 {
+			return math.Inf(1)
+		}
+		return 0
+	})
+	pullToTarget := constraintFunc(func(item *types.Item) float64 {
+		return 1000 * item.Position.Distance(target)
+	})
+	penalizeAll := constraintFunc(func(*types.Item) float64 { return 1e6 })
+
+	tests := []struct {
+		name        string
+		constraints []PlacementConstraint
+		check       func(pos types.Position) bool
+	}{
+		{"hard constraint forbids", []PlacementConstraint{forbidWest},
+			func(pos types.Position) bool { return pos.X >= 4 }},
+		{"soft constraint steers", []PlacementConstraint{pullToTarget},
+			func(pos types.Position) bool { return pos == target }},
+		{"uniform soft penalty forbids nothing", []PlacementConstraint{penalizeAll},
+			func(types.Position) bool { return true }},
+		{"soft constraint steers within the hard limit", []PlacementConstraint{forbidWest, pullToTarget},
+			func(pos types.Position) bool { return pos == types.Position{X: 4, Z: 6} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(8, 1, 8)
+			config := testConfig()
+			config.Constraints = tt.constraints
+			po := NewPlacementOptimizer(base)
+			po.configure(config)
+
+			pos := po.findBestPosition(base, types.NewItem("barrel", types.StructureNameWoodenBarrel))
+			if pos == nil {
+				t.Fatal("no position found")
+			}
+			if !tt.check(*pos) {
+				t.Errorf("barrel placed at %s", pos)
+			}
+		})
+	}
+
+	// Forbidding every position leaves nowhere to go
+	base := types.NewBase(8, 1, 8)
+	config := testConfig()
+	config.Constraints = []PlacementConstraint{constraintFunc(func(*types.Item) float64 { return math.Inf(1) })}
+	po := NewPlacementOptimizer(base)
+	po.configure(config)
+	if pos := po.findBestPosition(base, types.NewItem("barrel", types.StructureNameWoodenBarrel)); pos != nil {
+		t.Errorf("barrel placed at %s although every position is forbidden", pos)
+	}
+}
+
+func TestAccessConstraint(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, base *types.Base)
+		pos      types.Position
+		rotation int
+		want     float64
+	}{
+		{"front open", nil, types.Position{X: 3, Z: 2}, 0, 0},
+		{"front against the base edge", nil, types.Position{X: 0, Z: 2}, 180, math.Inf(1)},
+		{"front against a wall", func(t *testing.T, base *types.Base) {
+			place(t, base, "wall", types.StructureNameOuterWall, types.Position{X: 5, Z: 2})
+		}, types.Position{X: 3, Z: 2}, 0, math.Inf(1)},
+		{"front facing away from the wall", func(t *testing.T, base *types.Base) {
+			place(t, base, "wall", types.StructureNameOuterWall, types.Position{X: 5, Z: 2})
+		}, types.Position{X: 3, Z: 2}, 180, 0},
+		{"front in a pocket the Palbox cannot reach", func(t *testing.T, base *types.Base) {
+			for i, pos := range []types.Position{{X: 7, Z: 2}, {X: 6, Z: 1}, {X: 6, Z: 3}} {
+				place(t, base, fmt.Sprintf("wall%d", i), types.StructureNameOuterWall, pos)
+			}
+			place(t, base, "roof", types.StructureNameWoodenBarrel, types.Position{X: 6, Y: 1, Z: 2})
+		}, types.Position{X: 4, Z: 2}, 0, math.Inf(1)},
+		{"front in a pocket open above", func(t *testing.T, base *types.Base) {
+			for i, pos := range []types.Position{{X: 7, Z: 2}, {X: 6, Z: 1}, {X: 6, Z: 3}} {
+				place(t, base, fmt.Sprintf("wall%d", i), types.StructureNameOuterWall, pos)
+			}
+		}, types.Position{X: 4, Z: 2}, 0, 0},
+		{"covering another item's last open front", func(t *testing.T, base *types.Base) {
+			bench := types.NewItem("other", types.StructureNameWorkbench)
+			bench.Position = types.Position{X: 4, Z: 4}
+			if err := base.PlaceItem(bench); err != nil {
+				t.Fatalf("placing other: %v", err)
+			}
+		}, types.Position{X: 6, Z: 3}, 270, math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(8, 2, 5)
+			place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+			if tt.setup != nil {
+				tt.setup(t, base)
+			}
+
+			bench := types.NewItem("bench", types.StructureNameWorkbench)
+			bench.Position, bench.Rotation = tt.pos, tt.rotation
+			if !base.CanPlaceItem(bench) {
+				t.Fatalf("bench does not fit at %s", tt.pos)
+			}
+			if got := (AccessConstraint{}).Violation(base, bench); got != tt.want {
+				t.Errorf("Violation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessConstraintMovementPlane(t *testing.T) {
+	// The bench's front opens into a pocket walled in on the ground but open
+	// above, so it can only be reached by climbing
+	base := types.NewBase(8, 2, 5)
+	place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+	for i, pos := range []types.Position{{X: 7, Z: 2}, {X: 6, Z: 1}, {X: 6, Z: 3}} {
+		place(t, base, fmt.Sprintf("wall%d", i), types.StructureNameOuterWall, pos)
+	}
+	bench := types.NewItem("bench", types.StructureNameWorkbench)
+	bench.Position = types.Position{X: 4, Z: 2}
+
+	tests := []struct {
+		name  string
+		plane pathing.MovementPlane
+		want  float64
+	}{
+		{"climbing allowed", pathing.Full3D, 0},
+		{"flat movement", pathing.XZPlane, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := pathing.NewGraph(base)
+			graph.MovementPlane = tt.plane
+			if got := (AccessConstraint{Graph: graph}).Violation(base, bench); got != tt.want {
+				t.Errorf("Violation() = %v, want %v", got, tt.want)
+			}
+
+			// The optimizer binds an unset constraint to its own graph
+			po := NewPlacementOptimizer(base)
+			po.Graph.MovementPlane = tt.plane
+			config := testConfig()
+			config.Constraints = []PlacementConstraint{AccessConstraint{}}
+			bound := po.configure(config).Constraints[0]
+			if got := bound.Violation(base, bench); got != tt.want {
+				t.Errorf("bound Violation() = %v, want %v", got, tt.want)
+			}
+			if config.Constraints[0].(AccessConstraint).Graph != nil {
+				t.Error("configure bound the caller's constraint")
+			}
+		})
+	}
+}
+
+func TestSupportConstraint(t *testing.T) {
+	base := types.NewBase(6, 4, 6)
+	place(t, base, "floor", types.StructureNameGlassFence, types.Position{X: 2, Z: 2})
+
+	tests := []struct {
+		name string
+		pos  types.Position
+		want float64
+	}{
+		{"on the ground", types.Position{X: 0, Z: 0}, 0},
+		{"on a foundation", types.Position{X: 2, Y: 1, Z: 2}, 0},
+		{"floating", types.Position{X: 0, Y: 2, Z: 0}, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bed := types.NewItem("bed", types.StructureNamePalBed)
+			bed.Position = tt.pos
+			if got := (SupportConstraint{}).Violation(base, bed); got != tt.want {
+				t.Errorf("Violation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The optimizer never leaves a bed floating
+	result, err := NewPlacementOptimizer(types.NewBase(6, 4, 6)).OptimizePlacement(testItems(), testConfig())
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+	for _, item := range result.Base.Items {
+		if !result.Base.HasSupportBelow(item) {
+			t.Errorf("%s placed floating at %s", item.ID, item.Position)
+		}
+	}
+}
+
+func TestExposureConstraint(t *testing.T) {
+	// A roofed 3x3 room from (2,0,2) to (4,0,4)
+	base := types.NewBase(7, 3, 7)
+	for x := 1; x <= 5; x++ {
+		for z := 1; z <= 5; z++ {
+			if x > 1 && x < 5 && z > 1 && z < 5 {
+				place(t, base, fmt.Sprintf("roof_%d_%d", x, z), types.StructureNameGlassSlantedRoof, types.Position{X: x, Y: 2, Z: z})
+			} else {
+				place(t, base, fmt.Sprintf("wall_%d_%d", x, z), types.StructureNameOuterWall, types.Position{X: x, Z: z})
+			}
+		}
+	}
+	indoors, outdoors := types.Position{X: 3, Z: 3}, types.Position{X: 0, Z: 0}
+
+	tests := []struct {
+		name     string
+		exposure types.Exposure
+		item     types.StructureName
+		pos      types.Position
+		want     float64
+	}{
+		{"plot outdoors", types.ExposureAny, types.StructureNameFoodPlot, outdoors, 0},
+		{"plot indoors", types.ExposureAny, types.StructureNameFoodPlot, indoors, math.Inf(1)},
+		{"indoor station indoors", types.ExposureIndoors, types.StructureNameWoodenBarrel, indoors, 0},
+		{"indoor station outdoors", types.ExposureIndoors, types.StructureNameWoodenBarrel, outdoors, math.Inf(1)},
+		{"anywhere", types.ExposureAny, types.StructureNameWoodenBarrel, indoors, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := types.NewItem("item", tt.item)
+			item.Position, item.Exposure = tt.pos, tt.exposure
+			if got := (ExposureConstraint{}).Violation(base, item); got != tt.want {
+				t.Errorf("Violation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/pathing"
+	"palbaseiq/pkg/types"
+	"sort"
+)
+
+// ReserveCorridors marks walkways from the Palbox to each target as
+// reserved in base, so that later placement keeps them clear, and returns
+// the reserved cells sorted by position. Each walkway is the cheapest path
+// from a free cell beside the Palbox to the target, or to a free cell
+// beside the item occupying the target. Targets that cannot be reached are
+// skipped, and nothing is reserved if the base has no Palbox with a free
+// cell beside it.
+func (po *PlacementOptimizer) ReserveCorridors(base *types.Base, targets []types.Position) []types.Position {
+	palboxes := base.ItemsByName(types.StructureNamePalbox)
+	if len(palboxes) == 0 {
+		return nil
+	}
+
+	starts := base.AdjacentFreePositions(palboxes[0])
+	if len(starts) == 0 {
+		return nil
+	}
+
+	// Search the given layout with the optimizer's cost settings
+	graph := *po.Graph
+	graph.Base = base
+
+	corridor := make(map[types.Position]bool)
+	for _, target := range targets {
+		ends := []types.Position{target}
+		if !base.IsTraversable(target) {
+			ends = nil
+			if item := base.GetItemAtPosition(target); item != nil {
+				ends = base.AdjacentFreePositions(item)
+			}
+		}
+
+		// Walk from whichever free cell beside the Palbox is cheapest
+		paths := graph.ShortestPathsFromAny(starts, ends)
+		var best []types.Position
+		bestCost := 0.0
+		for _, end := range ends {
+			if path, ok := paths[pathing.GetNodeKey(end)]; ok && (best == nil || path.Cost < bestCost) {
+				best, bestCost = path.Nodes, path.Cost
+			}
+		}
+		for _, pos := range best {
+			corridor[pos] = true
+		}
+	}
+
+	cells := make([]types.Position, 0, len(corridor))
+	for pos := range corridor {
+		base.Reserve(pos)
+		cells = append(cells, pos)
+	}
+	sort.Slice(cells, func(i, j int) bool { return cells[i].Less(cells[j]) })
+
+	return cells
+}
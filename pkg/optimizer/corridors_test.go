@@ -0,0 +1,51 @@
+package optimizer
+
+import (
+	"fmt"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestReserveCorridors(t *testing.T) {
+	base := types.NewBase(8, 2, 8)
+	place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+	bench := place(t, base, "bench", types.StructureNameWorkbench, types.Position{X: 6, Z: 7})
+
+	po := NewPlacementOptimizer(base)
+	po.configure(testConfig())
+	corridor := po.ReserveCorridors(base, []types.Position{bench.Position})
+	if len(corridor) == 0 {
+		t.Fatal("no corridor reserved to the workbench")
+	}
+	for _, pos := range corridor {
+		if !base.IsReserved(pos) {
+			t.Errorf("corridor cell %s is not reserved", pos)
+		}
+	}
+
+	// Fill the base as far as it goes; the corridor stays clear
+	var items []*types.Item
+	for i := 0; i < 60; i++ {
+		items = append(items, types.NewItem(fmt.Sprintf("barrel%d", i), types.StructureNameWoodenBarrel))
+	}
+	placed := po.GreedyPlace(base, items)
+	if len(placed.Items) <= 2 {
+		t.Fatal("greedy placement placed nothing")
+	}
+	for _, pos := range corridor {
+		if placed.IsPositionOccupied(pos) {
+			t.Errorf("corridor cell %s was built on by %s", pos, placed.GetItemAtPosition(pos).ID)
+		}
+	}
+}
+
+func TestReserveCorridorsWithoutPalbox(t *testing.T) {
+	base := types.NewBase(8, 2, 8)
+	bench := place(t, base, "bench", types.StructureNameWorkbench, types.Position{X: 6, Z: 7})
+
+	po := NewPlacementOptimizer(base)
+	po.configure(testConfig())
+	if corridor := po.ReserveCorridors(base, []types.Position{bench.Position}); corridor != nil {
+		t.Errorf("ReserveCorridors() = %v without a Palbox, want nil", corridor)
+	}
+}
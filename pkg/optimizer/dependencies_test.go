@@ -0,0 +1,61 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"slices"
+	"testing"
+)
+
+func TestAccumulatorPlacedAfterGenerator(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  [2]string // accumulator, generator
+	}{
+		{"accumulator sorts first", [2]string{"a_accumulator", "z_generator"}},
+		{"generator sorts first", [2]string{"z_accumulator", "a_generator"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accumulator := types.NewItem(tt.ids[0], types.StructureNameAccumulator)
+			generator := types.NewItem(tt.ids[1], types.StructureNamePowerGenerator)
+			accumulator.Priority, generator.Priority = 50, 50
+
+			// Greedy placement scores an item's candidates before it moves on
+			// to the next, so the first item constraints see is placed first
+			var seen []string
+			config := testConfig()
+			config.Constraints = []PlacementConstraint{constraintFunc(func(item *types.Item) float64 {
+				if !slices.Contains(seen, item.ID) {
+					seen = append(seen, item.ID)
+				}
+				return 0
+			})}
+
+			items := []*types.Item{accumulator, generator}
+			result, err := NewPlacementOptimizer(types.NewBase(6, 3, 6)).OptimizePlacement(items, config)
+			if err != nil {
+				t.Fatalf("OptimizePlacement: %v", err)
+			}
+			checkLayout(t, result.Base, items)
+			if len(seen) != 2 || seen[0] != generator.ID {
+				t.Errorf("placement order %v, want %s first", seen, generator.ID)
+			}
+			if len(result.MissingDependencies) != 0 {
+				t.Errorf("MissingDependencies = %v, want none", result.MissingDependencies)
+			}
+		})
+	}
+
+	// Without a generator the accumulator is still placed, with a warning
+	accumulator := types.NewItem("accumulator", types.StructureNameAccumulator)
+	result, err := NewPlacementOptimizer(types.NewBase(6, 3, 6)).OptimizePlacement([]*types.Item{accumulator}, testConfig())
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+	if result.Base.Items[accumulator.ID] == nil {
+		t.Error("accumulator left unplaced")
+	}
+	if len(result.MissingDependencies) != 1 || result.MissingDependencies[0].Needs != types.StructureNamePowerGenerator {
+		t.Errorf("MissingDependencies = %v, want the power generator", result.MissingDependencies)
+	}
+}
@@ -0,0 +1,119 @@
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"palbaseiq/pkg/types"
+)
+
+// Errors returned by FindBestPlacement. They are wrapped with the item and
+// the reason, so callers should test for them with errors.Is.
+var (
+	ErrNoValidPosition = errors.New("no valid position")
+
+	// ErrItemTooLarge and ErrConstraintBlocked narrow down why there is no
+	// valid position. Both wrap ErrNoValidPosition.
+	ErrItemTooLarge      = fmt.Errorf("%w: item too large", ErrNoValidPosition)
+	ErrConstraintBlocked = fmt.Errorf("%w: constraint blocks all positions", ErrNoValidPosition)
+)
+
+// FindBestPlacement returns the best position and rotation for the item in
+// base. It runs the greedy placer's search at each of the four rotations,
+// starting with the item's own, and keeps the highest scoring; ties keep
+// the earlier rotation. The item itself is not modified. When there is no
+// valid position at any rotation the error says why at the item's own
+// rotation: the item is larger than the base (ErrItemTooLarge), a single
+// configured constraint forbids every position the cells would allow
+// (ErrConstraintBlocked), or the free cells, anchor points, footprint limit,
+// template or constraints together leave nothing (ErrNoValidPosition).
+//
+// The search at each rotation is the one greedy placement runs; the failure
+// reason is only worked out once it has found nothing.
+func (po *PlacementOptimizer) FindBestPlacement(base *types.Base, item *types.Item) (types.Position, int, error) {
+	var best *types.Position
+	bestRotation, bestScore := item.Rotation, math.Inf(-1)
+	for turn := 0; turn < 4; turn++ {
+		rotated := *item
+		rotated.Rotation = (item.Rotation + turn*90) % 360
+		pos := po.findBestPosition(base, &rotated)
+		if pos == nil {
+			continue
+		}
+
+		rotated.Position = *pos
+		score := po.evaluateItemPosition(base, &rotated) - po.constraintViolation(base, &rotated)
+		if best == nil || score > bestScore {
+			best, bestRotation, bestScore = pos, rotated.Rotation, score
+		}
+	}
+
+	if best == nil {
+		return types.Position{}, item.Rotation, po.placementFailure(base, item)
+	}
+	return *best, bestRotation, nil
+}
+
+// placementFailure explains why the item has no valid position in base,
+// applying the checks of validPositions one at a time
+func (po *PlacementOptimizer) placementFailure(base *types.Base, item *types.Item) error {
+	if err := base.CheckFits(item); err != nil {
+		return fmt.Errorf("%w: %v", ErrItemTooLarge, err)
+	}
+
+	var free []types.Position
+	consider := func(pos types.Position) bool {
+		if base.CanPlaceAt(item, pos, item.Rotation) {
+			free = append(free, pos)
+		}
+		return true
+	}
+	if len(item.CandidatePositions) > 0 {
+		for _, pos := range item.CandidatePositions {
+			consider(pos)
+		}
+	} else {
+		base.EachFreePosition(consider)
+	}
+	if len(free) == 0 {
+		if len(item.CandidatePositions) > 0 {
+			return fmt.Errorf("item %s: %w: all %d anchor points are occupied or out of bounds",
+				item.ID, ErrNoValidPosition, len(item.CandidatePositions))
+		}
+		return fmt.Errorf("item %s: %w: no free space fits its footprint", item.ID, ErrNoValidPosition)
+	}
+
+	footprint := newFootprintLimit(base, po.Config.MaxFootprint)
+	var allowed []*types.Item
+	for _, pos := range free {
+		testItem := *item
+		testItem.Position = pos
+		if footprint.allows(&testItem) && po.templateAllows(&testItem) {
+			allowed = append(allowed, &testItem)
+		}
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("item %s: %w: the footprint limit or template rules out all %d free positions",
+			item.ID, ErrNoValidPosition, len(free))
+	}
+
+	for _, constraint := range po.Config.Constraints {
+		if blocksAll(base, constraint, allowed) {
+			return fmt.Errorf("item %s: %w: %T forbids all %d candidates",
+				item.ID, ErrConstraintBlocked, constraint, len(allowed))
+		}
+	}
+	return fmt.Errorf("item %s: %w: the constraints together block all %d positions",
+		item.ID, ErrNoValidPosition, len(allowed))
+}
+
+// blocksAll reports whether the constraint forbids every one of the
+// candidates, each a copy of the item at a position
+func blocksAll(base *types.Base, constraint PlacementConstraint, candidates []*types.Item) bool {
+	for _, candidate := range candidates {
+		if !math.IsInf(constraint.Violation(base, candidate), 1) {
+			return false
+		}
+	}
+	return true
+}
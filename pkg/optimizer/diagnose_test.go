@@ -0,0 +1,150 @@
+package optimizer
+
+import (
+	"errors"
+	"math"
+	"palbaseiq/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestFindBestPlacementFailures(t *testing.T) {
+	blockAll := constraintFunc(func(*types.Item) float64 { return math.Inf(1) })
+	blockWest := constraintFunc(func(item *types.Item) float64 {
+		if item.Position.X < 2 {
+			return math.Inf(1)
+		}
+		return 0
+	})
+	blockEast := constraintFunc(func(item *types.Item) float64 {
+		if item.Position.X >= 2 {
+			return math.Inf(1)
+		}
+		return 0
+	})
+
+	tests := []struct {
+		name      string
+		setup     func(t *testing.T, base *types.Base, item *types.Item, config *OptimizationConfig)
+		structure types.StructureName
+		wantErr   error
+		notErr    error
+		wantText  string
+	}{
+		{
+			"item too large",
+			nil, types.StructureNamePalbox,
+			ErrItemTooLarge, nil, "item too large",
+		},
+		{
+			"no free space",
+			func(t *testing.T, base *types.Base, _ *types.Item, _ *OptimizationConfig) {
+				for x := range 4 {
+					for z := range 4 {
+						place(t, base, string(rune('a'+x*4+z)), types.StructureNameWoodenBarrel, types.Position{X: x, Z: z})
+					}
+				}
+			},
+			types.StructureNameWoodenBarrel,
+			ErrNoValidPosition, ErrConstraintBlocked, "no free space",
+		},
+		{
+			"anchor points occupied",
+			func(t *testing.T, base *types.Base, item *types.Item, _ *OptimizationConfig) {
+				place(t, base, "taken", types.StructureNameWoodenBarrel, types.Position{X: 1, Z: 1})
+				item.CandidatePositions = []types.Position{{X: 1, Z: 1}, {X: 9, Z: 9}}
+			},
+			types.StructureNameWoodenBarrel,
+			ErrNoValidPosition, ErrConstraintBlocked, "all 2 anchor points",
+		},
+		{
+			"footprint limit",
+			func(t *testing.T, base *types.Base, _ *types.Item, config *OptimizationConfig) {
+				place(t, base, "first", types.StructureNameWoodenBarrel, types.Position{X: 0, Z: 0})
+				config.MaxFootprint = types.BoundingBox{Width: 1, Depth: 1}
+			},
+			types.StructureNameWoodenBarrel,
+			ErrNoValidPosition, ErrConstraintBlocked, "footprint limit",
+		},
+		{
+			"one constraint blocks everything",
+			func(_ *testing.T, _ *types.Base, _ *types.Item, config *OptimizationConfig) {
+				config.Constraints = append(config.Constraints, blockAll)
+			},
+			types.StructureNameWoodenBarrel,
+			ErrConstraintBlocked, nil, "optimizer.constraintFunc forbids all",
+		},
+		{
+			"constraints block together",
+			func(_ *testing.T, _ *types.Base, _ *types.Item, config *OptimizationConfig) {
+				config.Constraints = append(config.Constraints, blockWest, blockEast)
+			},
+			types.StructureNameWoodenBarrel,
+			ErrNoValidPosition, ErrConstraintBlocked, "constraints together",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(4, 1, 4)
+			item := types.NewItem("item", tt.structure)
+			config := testConfig()
+			if tt.setup != nil {
+				tt.setup(t, base, item, config)
+			}
+			po := NewPlacementOptimizer(base)
+			po.configure(config)
+
+			_, _, err := po.FindBestPlacement(base, item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.notErr != nil && errors.Is(err, tt.notErr) {
+				t.Errorf("error = %v, which should not be %v", err, tt.notErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("error = %v, want one mentioning %q", err, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestFindBestPlacementRotation(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, depth  int
+		rotation      int
+		wantRotations []int
+	}{
+		{"fits as it is", 4, 1, 0, []int{0, 180}},
+		{"fits only turned", 1, 4, 0, []int{90, 270}},
+		{"starts turned", 1, 4, 270, []int{90, 270}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(tt.width, 1, tt.depth)
+			bench := types.NewItem("bench", types.StructureNameWorkbench)
+			bench.Rotation = tt.rotation
+			po := NewPlacementOptimizer(base)
+			po.configure(testConfig())
+
+			pos, rotation, err := po.FindBestPlacement(base, bench)
+			if err != nil {
+				t.Fatalf("FindBestPlacement: %v", err)
+			}
+			if bench.Rotation != tt.rotation || bench.Position != (types.Position{}) {
+				t.Errorf("FindBestPlacement changed the item to %s rotated %d", bench.Position, bench.Rotation)
+			}
+			found := false
+			for _, want := range tt.wantRotations {
+				found = found || rotation == want
+			}
+			if !found {
+				t.Errorf("rotation = %d, want one of %v", rotation, tt.wantRotations)
+			}
+			bench.Position, bench.Rotation = pos, rotation
+			if !base.CanPlaceItem(bench) {
+				t.Errorf("bench does not fit at %s rotated %d", pos, rotation)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestMaxDuration(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxDuration   time.Duration
+		maxIterations int
+		wantAll       bool // whether every iteration should run
+	}{
+		{"tiny limit stops early", time.Nanosecond, 1_000_000, false},
+		{"iterations run out first", time.Hour, 20, true},
+		{"no limit", 0, 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.MaxDuration = tt.maxDuration
+			config.MaxIterations = tt.maxIterations
+			config.MinTemperature = 0
+			config.RecordScoreHistory = true
+
+			items := testItems()
+			start := time.Now()
+			result, err := NewPlacementOptimizer(types.NewBase(10, 2, 10)).OptimizePlacement(items, config)
+			if err != nil {
+				t.Fatalf("OptimizePlacement: %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("run took %s", elapsed)
+			}
+
+			checkLayout(t, result.Base, items)
+			if len(result.Unplaced) != 0 {
+				t.Errorf("%d items unplaced", len(result.Unplaced))
+			}
+			if result.Score == nil {
+				t.Fatal("no score")
+			}
+
+			ran := len(result.BestScores)
+			if tt.wantAll && ran != tt.maxIterations {
+				t.Errorf("ran %d iterations, want %d", ran, tt.maxIterations)
+			}
+			if !tt.wantAll && ran >= tt.maxIterations {
+				t.Errorf("ran all %d iterations despite the time limit", ran)
+			}
+		})
+	}
+}
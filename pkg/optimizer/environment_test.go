@@ -0,0 +1,85 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+// feature returns an environmental item of the type at pos
+func feature(id string, itemType types.ItemType, pos types.Position, bounds types.BoundingBox) *types.Item {
+	return &types.Item{ID: id, Type: itemType, Position: pos, Bounds: bounds}
+}
+
+func TestEnvironmentalItemsStayPut(t *testing.T) {
+	flagged := types.NewItem("boulder", types.StructureNameWoodenBarrel)
+	flagged.Position, flagged.Environmental = types.Position{X: 2, Z: 5}, true
+
+	tests := []struct {
+		name string
+		item *types.Item
+	}{
+		{"tree", feature("tree", types.ItemTypeTree, types.Position{X: 4, Z: 4}, types.BoundingBox{Width: 1, Height: 2, Depth: 1})},
+		{"rock", feature("rock", types.ItemTypeRock, types.Position{X: 0, Z: 3}, types.BoundingBox{Width: 2, Height: 1, Depth: 2})},
+		{"water", feature("water", types.ItemTypeWater, types.Position{X: 3, Z: 0}, types.BoundingBox{Width: 3, Height: 1, Depth: 2})},
+		{"flagged structure", flagged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.item.IsFixed() {
+				t.Fatalf("%s is not fixed", tt.item.ID)
+			}
+			want := tt.item.Position
+			items := append(testItems(), tt.item)
+
+			result, err := NewPlacementOptimizer(types.NewBase(8, 2, 8)).OptimizePlacement(items, testConfig())
+			if err != nil {
+				t.Fatalf("OptimizePlacement: %v", err)
+			}
+			checkLayout(t, result.Base, items)
+			if got := result.Base.Items[tt.item.ID]; got == nil || got.Position != want {
+				t.Fatalf("%s moved from %s to %v", tt.item.ID, want, got)
+			}
+
+			// Annealing the result again leaves it where it is
+			po := NewPlacementOptimizer(result.Base)
+			annealed, err := po.Anneal(result.Base, items, testConfig())
+			if err != nil {
+				t.Fatalf("Anneal: %v", err)
+			}
+			if got := annealed.Base.Items[tt.item.ID]; got == nil || got.Position != want {
+				t.Errorf("annealing moved %s from %s to %v", tt.item.ID, want, got)
+			}
+
+			// No movable item can be put on its cells
+			for _, cell := range tt.item.GetOccupiedPositions() {
+				barrel := types.NewItem("barrel", types.StructureNameWoodenBarrel)
+				barrel.Position = cell
+				if result.Base.CanPlaceItem(barrel) {
+					t.Errorf("a barrel fits at %s, inside %s", cell, tt.item.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestItemTypeIsEnvironmental(t *testing.T) {
+	tests := []struct {
+		itemType types.ItemType
+		want     bool
+	}{
+		{types.ItemTypeTree, true},
+		{types.ItemTypeRock, true},
+		{types.ItemTypeWater, true},
+		{types.ItemTypeWorkbench, false},
+		{types.ItemTypePalbox, false},
+		{types.ItemType("bush"), false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.itemType), func(t *testing.T) {
+			if got := tt.itemType.IsEnvironmental(); got != tt.want {
+				t.Errorf("IsEnvironmental() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
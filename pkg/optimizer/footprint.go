@@ -0,0 +1,26 @@
+package optimizer
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+)
+
+// FootprintAspectScore measures how close the X/Z footprint of the movable
+// items (see Base.FootprintBounds) comes to the target width:depth ratio,
+// in [0,1]. A footprint matching the ratio scores 1, and one that is k
+// times too wide or too deep scores 1/k. A target ratio of zero or less
+// asks for a square. A base without movable items scores 0.
+func FootprintAspectScore(base *types.Base, targetRatio float64) float64 {
+	if targetRatio <= 0 {
+		targetRatio = 1.0
+	}
+
+	low, high := base.FootprintBounds()
+	width, depth := float64(high.X-low.X+1), float64(high.Z-low.Z+1)
+	if width <= 0 || depth <= 0 {
+		return 0.0
+	}
+
+	ratio := width / depth
+	return math.Min(ratio/targetRatio, targetRatio/ratio)
+}
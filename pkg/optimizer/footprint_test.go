@@ -0,0 +1,51 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestFootprintAspectScore(t *testing.T) {
+	block := func(t *testing.T, width, depth int) *types.Base {
+		base := types.NewBase(12, 1, 12)
+		for x := 0; x < width; x++ {
+			for z := 0; z < depth; z++ {
+				place(t, base, fmt.Sprintf("barrel_%d_%d", x, z), types.StructureNameWoodenBarrel, types.Position{X: x + 1, Z: z + 1})
+			}
+		}
+		return base
+	}
+	strip, cluster := block(t, 10, 2), block(t, 5, 4)
+
+	tests := []struct {
+		name   string
+		base   *types.Base
+		target float64
+		want   float64
+	}{
+		{"10x2 strip", strip, 1, 0.2},
+		{"5x4 cluster", cluster, 1, 0.8},
+		{"strip matching a 5:1 target", strip, 5, 1},
+		{"cluster against a 5:1 target", cluster, 5, 0.25},
+		{"unset target asks for a square", cluster, 0, 0.8},
+		{"single cell", block(t, 1, 1), 1, 1},
+		{"empty base", types.NewBase(4, 1, 4), 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FootprintAspectScore(tt.base, tt.target); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("FootprintAspectScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if FootprintAspectScore(strip, 1) >= FootprintAspectScore(cluster, 1) {
+		t.Error("the 10x2 strip scores no worse than the 5x4 cluster")
+	}
+	if low, high := strip.FootprintBounds(); low != (types.Position{X: 1, Z: 1}) || high != (types.Position{X: 10, Z: 2}) {
+		t.Errorf("strip FootprintBounds() = %s, %s", low, high)
+	}
+}
@@ -0,0 +1,97 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/pathing"
+	"palbaseiq/pkg/types"
+)
+
+// groupMates returns the IDs of the items that share a group with itemID
+func (po *PlacementOptimizer) groupMates(itemID string) []string {
+	var mates []string
+	for _, group := range po.Config.Groups {
+		inGroup := false
+		for _, id := range group {
+			if id == itemID {
+				inGroup = true
+				break
+			}
+		}
+		if !inGroup {
+			continue
+		}
+		for _, id := range group {
+			if id != itemID {
+				mates = append(mates, id)
+			}
+		}
+	}
+	return mates
+}
+
+// evaluateProximityToGroupMates rewards positions close to the already
+// placed members of the item's groups
+func (po *PlacementOptimizer) evaluateProximityToGroupMates(base *types.Base, item *types.Item) float64 {
+	score := 0.0
+	for _, id := range po.groupMates(item.ID) {
+		if mate, placed := base.Items[id]; placed {
+			distance := item.Position.Distance(mate.Position)
+			score += 10.0 / (1.0 + distance)
+		}
+	}
+	return score
+}
+
+// evaluateGroups returns how tightly the configured groups are clustered, in
+// [0,1]. Each pair of placed group members contributes 1/(1+d), where d is
+// the cost of the cheapest walk between free cells beside them, and the
+// result is the mean over all pairs. Pairs no walk connects contribute 0,
+// and spread-out groups score close to 0.
+func (po *PlacementOptimizer) evaluateGroups(base *types.Base, groups [][]string) float64 {
+	// Search the evaluated layout with the optimizer's cost settings
+	graph := *po.Graph
+	graph.Base = base
+
+	total := 0.0
+	pairs := 0
+	for _, group := range groups {
+		for i := 0; i < len(group); i++ {
+			first, ok := base.Items[group[i]]
+			if !ok {
+				continue
+			}
+
+			// One search from the first member reaches all later ones
+			var mates [][]types.Position
+			var targets []types.Position
+			for j := i + 1; j < len(group); j++ {
+				if second, ok := base.Items[group[j]]; ok {
+					beside := base.AdjacentFreePositions(second)
+					mates = append(mates, beside)
+					targets = append(targets, beside...)
+				}
+			}
+			if len(mates) == 0 {
+				continue
+			}
+			distances := graph.NearestSourceDistance(targets, base.AdjacentFreePositions(first))
+
+			for _, beside := range mates {
+				pairs++
+				best, found := 0.0, false
+				for _, pos := range beside {
+					if cost, ok := distances[pathing.GetNodeKey(pos)]; ok && (!found || cost < best) {
+						best, found = cost, true
+					}
+				}
+				if found {
+					total += 1.0 / (1.0 + best)
+				}
+			}
+		}
+	}
+
+	if pairs == 0 {
+		return 0.0
+	}
+	return total / float64(pairs)
+}
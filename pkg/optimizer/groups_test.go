@@ -0,0 +1,42 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestEvaluateGroups(t *testing.T) {
+	tests := []struct {
+		name   string
+		second *types.Position
+		wall   bool
+		check  func(score float64) bool
+		want   string
+	}{
+		{"sharing a free cell", &types.Position{X: 2, Z: 1}, false, func(s float64) bool { return s == 1 }, "1"},
+		{"spread out", &types.Position{X: 8, Z: 1}, false, func(s float64) bool { return s > 0 && s < 0.2 }, "in (0, 0.2)"},
+		{"walled apart", &types.Position{X: 8, Z: 1}, true, func(s float64) bool { return s == 0 }, "0"},
+		{"member missing", nil, false, func(s float64) bool { return s == 0 }, "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(9, 1, 3)
+			place(t, base, "furnace", types.StructureNameFurnace, types.Position{X: 0, Z: 1})
+			if tt.second != nil {
+				place(t, base, "storage", types.StructureNameWoodenBarrel, *tt.second)
+			}
+			if tt.wall {
+				for z := 0; z < 3; z++ {
+					place(t, base, string(rune('a'+z)), types.StructureNameWoodenBarrel, types.Position{X: 4, Z: z})
+				}
+			}
+
+			po := NewPlacementOptimizer(base)
+			score := po.evaluateGroups(base, [][]string{{"furnace", "storage"}})
+			if !tt.check(score) {
+				t.Errorf("evaluateGroups() = %v, want %s", score, tt.want)
+			}
+		})
+	}
+}
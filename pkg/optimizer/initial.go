@@ -0,0 +1,149 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// InitialStrategy builds the starting layout that annealing refines.
+// Initialize places as many of the items into the base as it can; items it
+// cannot fit are left out and reported as unplaced.
+type InitialStrategy interface {
+	Initialize(po *PlacementOptimizer, base *types.Base, items []*types.Item)
+}
+
+// GreedyStrategy places each item, in priority order, at the position that
+// scores best given the items already placed
+type GreedyStrategy struct{}
+
+// Initialize implements InitialStrategy
+func (GreedyStrategy) Initialize(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	po.placeItemsGreedy(base, items)
+}
+
+// RandomStrategy places each item at a uniformly random valid position,
+// giving annealing a less biased starting point than the greedy layout
+type RandomStrategy struct{}
+
+// Initialize implements InitialStrategy
+func (RandomStrategy) Initialize(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	po.placeFixedItems(base, items)
+
+	for _, item := range items {
+		if item.IsFixed() {
+			continue
+		}
+
+		valid := po.validPositions(base, item)
+		if len(valid) == 0 {
+			continue
+		}
+		item.Position = valid[po.rng.Intn(len(valid))]
+		base.PlaceItem(item)
+	}
+}
+
+// ShelfStrategy packs items in priority order into rows, filling the lowest
+// level first, then each Z row from low to high X
+type ShelfStrategy struct{}
+
+// Initialize implements InitialStrategy
+func (ShelfStrategy) Initialize(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	po.placeFixedItems(base, items)
+
+	for _, item := range items {
+		if item.IsFixed() {
+			continue
+		}
+
+		valid := po.validPositions(base, item)
+		if len(valid) == 0 {
+			continue
+		}
+
+		first := valid[0]
+		for _, pos := range valid[1:] {
+			if shelfBefore(pos, first) {
+				first = pos
+			}
+		}
+		item.Position = first
+		base.PlaceItem(item)
+	}
+}
+
+// MirroredStrategy builds a mirror-symmetric starting layout. Items are
+// placed greedily on the low side of Axis, and each is paired with the next
+// unplaced item of the same type, bounds and rotation, which goes to the
+// mirrored position. Items left without a partner or a free mirrored spot
+// are placed greedily anywhere afterwards.
+type MirroredStrategy struct {
+	Axis types.Axis
+}
+
+// Initialize implements InitialStrategy
+func (s MirroredStrategy) Initialize(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	po.placeFixedItems(base, items)
+
+	var leftover []*types.Item
+	paired := make(map[string]bool)
+	for i, item := range items {
+		if item.IsFixed() || paired[item.ID] {
+			continue
+		}
+
+		var lowHalf []types.Position
+		for _, pos := range po.validPositions(base, item) {
+			if s.inLowHalf(base, item, pos) {
+				lowHalf = append(lowHalf, pos)
+			}
+		}
+
+		position := po.bestPositionAmong(base, item, lowHalf)
+		if position == nil {
+			leftover = append(leftover, item)
+			continue
+		}
+		item.Position = *position
+		base.PlaceItem(item)
+
+		for _, partner := range items[i+1:] {
+			if partner.IsFixed() || paired[partner.ID] || partner.Type != item.Type ||
+				partner.Bounds != item.Bounds || partner.Rotation != item.Rotation {
+				continue
+			}
+
+			paired[partner.ID] = true
+			partner.Position = base.MirroredPosition(item, s.Axis)
+			if !po.allowsAt(base, partner) || base.PlaceItem(partner) != nil {
+				leftover = append(leftover, partner)
+			}
+			break
+		}
+	}
+
+	for _, item := range leftover {
+		if position := po.findBestPosition(base, item); position != nil {
+			item.Position = *position
+			base.PlaceItem(item)
+		}
+	}
+}
+
+// inLowHalf reports whether the item at pos lies entirely on the low side
+// of the strategy's axis
+func (s MirroredStrategy) inLowHalf(base *types.Base, item *types.Item, pos types.Position) bool {
+	bounds := item.EffectiveBounds()
+	if s.Axis == types.AxisZ {
+		return pos.Z+bounds.Depth <= base.Depth/2
+	}
+	return pos.X+bounds.Width <= base.Width/2
+}
+
+// shelfBefore orders positions by level, then row, then column
+func shelfBefore(a, b types.Position) bool {
+	if a.Y != b.Y {
+		return a.Y < b.Y
+	}
+	if a.Z != b.Z {
+		return a.Z < b.Z
+	}
+	return a.X < b.X
+}
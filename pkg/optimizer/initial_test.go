@@ -0,0 +1,45 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestInitialStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy InitialStrategy
+	}{
+		{"default", nil},
+		{"greedy", GreedyStrategy{}},
+		{"random", RandomStrategy{}},
+		{"shelf", ShelfStrategy{}},
+		{"mirrored", MirroredStrategy{Axis: types.AxisX}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := func() *PlacementResult {
+				config := testConfig()
+				config.InitialStrategy = tt.strategy
+				items := testItems()
+				result, err := NewPlacementOptimizer(types.NewBase(8, 3, 8)).OptimizePlacement(items, config)
+				if err != nil {
+					t.Fatalf("OptimizePlacement: %v", err)
+				}
+				if len(result.Unplaced) != 0 {
+					t.Errorf("%d items left unplaced", len(result.Unplaced))
+				}
+				checkLayout(t, result.Base, items)
+				return result
+			}
+
+			// The seed fixes the outcome of every strategy
+			first, second := run(), run()
+			if first.Score.TotalScore != second.Score.TotalScore {
+				t.Errorf("same seed scored %v then %v", first.Score.TotalScore, second.Score.TotalScore)
+			}
+			t.Logf("total score %.3f", first.Score.TotalScore)
+		})
+	}
+}
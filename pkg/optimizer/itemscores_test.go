@@ -0,0 +1,109 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"palbaseiq/pkg/pathing"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestRecordItemScores(t *testing.T) {
+	base := types.NewBase(12, 2, 6)
+	place(t, base, "west", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+	place(t, base, "east", types.StructureNamePalbox, types.Position{X: 10, Z: 0})
+	place(t, base, "near_west", types.StructureNameWoodenBarrel, types.Position{X: 3, Z: 1})
+	place(t, base, "near_east", types.StructureNameWoodenBarrel, types.Position{X: 8, Z: 4})
+
+	// A barrel shut into the corner at (0,0,5) by walls and a lid
+	place(t, base, "trapped", types.StructureNameWoodenBarrel, types.Position{X: 0, Z: 5})
+	place(t, base, "lid", types.StructureNameWoodenBarrel, types.Position{X: 0, Y: 1, Z: 5})
+	for y := 0; y < 2; y++ {
+		for i, pos := range []types.Position{{X: 1, Y: y, Z: 5}, {X: 0, Y: y, Z: 4}} {
+			place(t, base, fmt.Sprintf("wall%d_%d", i, y), types.StructureNameWoodenBarrel, pos)
+		}
+	}
+	items := base.SortedItems()
+
+	// The cheapest walk from either Palbox to a free cell beside the item,
+	// plus the step onto it
+	graph := pathing.NewGraph(base)
+	fields := map[string]map[string]float64{
+		"west": graph.DistanceField(base.Items["west"].Position),
+		"east": graph.DistanceField(base.Items["east"].Position),
+	}
+	nearest := func(id string) (string, float64) {
+		palbox, cost := "", math.Inf(1)
+		for _, name := range []string{"west", "east"} {
+			for _, pos := range base.AdjacentFreePositions(base.Items[id]) {
+				if c, ok := fields[name][pathing.GetNodeKey(pos)]; ok && c+1 < cost {
+					palbox, cost = name, c+1
+				}
+			}
+		}
+		return palbox, cost
+	}
+
+	po := NewPlacementOptimizer(base)
+	config := testConfig()
+	config.RecordItemScores = true
+	score := po.evaluatePlacement(base, items, po.configure(config))
+
+	tests := []struct {
+		id        string
+		reachable bool
+		palbox    string
+	}{
+		{"west", true, "west"},
+		{"near_west", true, "west"},
+		{"near_east", true, "east"},
+		{"trapped", false, ""},
+		{"lid", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, ok := score.ItemScores[tt.id]
+			if !ok {
+				t.Fatalf("no score for %s", tt.id)
+			}
+			if got.Reachable != tt.reachable || got.Palbox != tt.palbox {
+				t.Errorf("Reachable = %v from %q, want %v from %q", got.Reachable, got.Palbox, tt.reachable, tt.palbox)
+			}
+
+			switch {
+			case tt.id == tt.palbox:
+				if got.PathCost != 0 {
+					t.Errorf("Palbox PathCost = %v, want 0", got.PathCost)
+				}
+			case tt.reachable:
+				if _, want := nearest(tt.id); math.Abs(got.PathCost-want) > 1e-9 {
+					t.Errorf("PathCost = %v, want %v", got.PathCost, want)
+				}
+				if got.Pathfinding <= 0 {
+					t.Errorf("Pathfinding = %v, want a reward", got.Pathfinding)
+				}
+			default:
+				if !math.IsInf(got.PathCost, 1) {
+					t.Errorf("PathCost = %v, want +Inf", got.PathCost)
+				}
+				if got.Pathfinding >= 0 {
+					t.Errorf("Pathfinding = %v, want a penalty", got.Pathfinding)
+				}
+			}
+		})
+	}
+
+	// Contributions add up to the pathfinding term
+	sum := 0.0
+	for _, item := range score.ItemScores {
+		sum += item.Pathfinding
+	}
+	if math.Abs(sum-score.PathfindingScore) > 1e-9 {
+		t.Errorf("item contributions sum to %v, PathfindingScore is %v", sum, score.PathfindingScore)
+	}
+
+	// Without the flag there is no breakdown
+	if score := po.evaluatePlacement(base, items, po.configure(testConfig())); score.ItemScores != nil {
+		t.Errorf("ItemScores = %v without RecordItemScores", score.ItemScores)
+	}
+}
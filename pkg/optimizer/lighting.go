@@ -0,0 +1,23 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// DefaultLightRadius is the distance, in cells, a light source covers when
+// OptimizationConfig.LightRadius is unset
+const DefaultLightRadius = 6.0
+
+// LightCoverageScore returns the fraction of the base's work cells that lie
+// within radius of a light source, in [0,1]. A radius of zero or less uses
+// DefaultLightRadius. A base without work cells scores 0.
+func LightCoverageScore(base *types.Base, radius float64) float64 {
+	if radius <= 0 {
+		radius = DefaultLightRadius
+	}
+
+	work := len(base.WorkCells())
+	if work == 0 {
+		return 0.0
+	}
+
+	return 1.0 - float64(len(base.DarkCells(radius)))/float64(work)
+}
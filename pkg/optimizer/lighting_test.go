@@ -0,0 +1,53 @@
+package optimizer
+
+import (
+	"fmt"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestLightCoverageScore(t *testing.T) {
+	layout := func(t *testing.T, lanterns ...types.Position) *types.Base {
+		base := types.NewBase(9, 1, 9)
+		for i, pos := range []types.Position{{X: 1, Z: 1}, {X: 7, Z: 1}, {X: 1, Z: 7}, {X: 7, Z: 7}} {
+			place(t, base, fmt.Sprintf("barrel%d", i), types.StructureNameWoodenBarrel, pos)
+		}
+		for i, pos := range lanterns {
+			place(t, base, fmt.Sprintf("lantern%d", i), types.StructureNameJapanesePaperLantern, pos)
+		}
+		return base
+	}
+
+	tests := []struct {
+		name     string
+		base     *types.Base
+		radius   float64
+		want     float64
+		wantDark int
+	}{
+		{"no light", layout(t), 4, 0, 16},
+		{"central lantern", layout(t, types.Position{X: 4, Z: 4}), 4, 0.5, 8},
+		{"central lantern, wide radius", layout(t, types.Position{X: 4, Z: 4}), 5, 1, 0},
+		{"corner lantern", layout(t, types.Position{X: 0, Z: 0}), 4, 0.25, 12},
+		{"lantern beside every barrel", layout(t,
+			types.Position{X: 2, Z: 2}, types.Position{X: 6, Z: 2}, types.Position{X: 2, Z: 6}, types.Position{X: 6, Z: 6}), 2.5, 1, 0},
+		{"no work cells", types.NewBase(4, 1, 4), 4, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LightCoverageScore(tt.base, tt.radius); got != tt.want {
+				t.Errorf("LightCoverageScore() = %v, want %v", got, tt.want)
+			}
+			if got := len(tt.base.DarkCells(tt.radius)); got != tt.wantDark {
+				t.Errorf("DarkCells() has %d cells, want %d", got, tt.wantDark)
+			}
+		})
+	}
+
+	// A lantern in the middle lights more work cells than none at all
+	dark, lit := LightCoverageScore(layout(t), 0), LightCoverageScore(layout(t, types.Position{X: 4, Z: 4}), 0)
+	if lit <= dark {
+		t.Errorf("a central lantern scored %v, no better than %v without", lit, dark)
+	}
+}
@@ -0,0 +1,216 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// MoveOperator turns a copy of the current layout into an annealing
+// candidate. Apply changes base, moving only items from the list that are
+// not fixed, and must leave every placed item where validPositions would
+// allow it. An operator that finds no valid move leaves base unchanged.
+type MoveOperator interface {
+	Apply(po *PlacementOptimizer, base *types.Base, items []*types.Item)
+}
+
+// WeightedMove pairs a move operator with how often annealing picks it,
+// relative to the weights of the other operators
+type WeightedMove struct {
+	Operator MoveOperator
+	Weight   float64
+}
+
+// DefaultMoveOperators mixes greedy relocation, which refines, with random
+// relocation, swaps, rotations and cluster shifts, which explore
+func DefaultMoveOperators() []WeightedMove {
+	return []WeightedMove{
+		{Operator: GreedyRelocateMove{}, Weight: 4},
+		{Operator: RandomRelocateMove{}, Weight: 2},
+		{Operator: SwapMove{}, Weight: 2},
+		{Operator: RotateMove{}, Weight: 1},
+		{Operator: ShiftClusterMove{Radius: 3}, Weight: 1},
+	}
+}
+
+// GreedyRelocateMove removes a random item and puts it back at the
+// position that scores best for it, or leaves it out if none is left. It
+// also gives unplaced items another chance to fit.
+type GreedyRelocateMove struct{}
+
+// Apply implements MoveOperator
+func (GreedyRelocateMove) Apply(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	po.perturbPlacement(base, items)
+}
+
+// RandomRelocateMove moves a random placed item to a uniformly random valid
+// position, escaping the pull of the greedy choice
+type RandomRelocateMove struct{}
+
+// Apply implements MoveOperator
+func (RandomRelocateMove) Apply(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	placed := placedMovable(base, items)
+	if len(placed) == 0 {
+		return
+	}
+
+	item := *placed[po.rng.Intn(len(placed))]
+	base.RemoveItem(item.ID)
+	valid := po.validPositions(base, &item)
+	if len(valid) > 0 {
+		item.Position = valid[po.rng.Intn(len(valid))]
+	}
+	base.PlaceItem(&item)
+}
+
+// SwapMove exchanges the positions of two random placed items, which moves
+// both a long way without passing through worse intermediate layouts
+type SwapMove struct{}
+
+// Apply implements MoveOperator
+func (SwapMove) Apply(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	placed := placedMovable(base, items)
+	if len(placed) < 2 {
+		return
+	}
+
+	i := po.rng.Intn(len(placed))
+	j := po.rng.Intn(len(placed) - 1)
+	if j >= i {
+		j++
+	}
+
+	first, second := *placed[i], *placed[j]
+	first.Position, second.Position = placed[j].Position, placed[i].Position
+	po.replaceItems(base, []*types.Item{placed[i], placed[j]}, []*types.Item{&first, &second})
+}
+
+// RotateMove turns a random placed item by 90 degrees about its position
+type RotateMove struct{}
+
+// Apply implements MoveOperator
+func (RotateMove) Apply(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	placed := placedMovable(base, items)
+	if len(placed) == 0 {
+		return
+	}
+
+	original := placed[po.rng.Intn(len(placed))]
+	rotated := *original
+	rotated.Rotation = (original.Rotation + 90) % 360
+	po.replaceItems(base, []*types.Item{original}, []*types.Item{&rotated})
+}
+
+// ShiftClusterMove moves a random placed item and every movable item whose
+// position lies within Radius of it one cell along a random horizontal
+// axis, keeping the cluster's internal arrangement
+type ShiftClusterMove struct {
+	Radius float64
+}
+
+// shiftDirections are the horizontal unit steps a cluster can shift by
+var shiftDirections = []types.Position{
+	{X: 1}, {X: -1}, {Z: 1}, {Z: -1},
+}
+
+// Apply implements MoveOperator
+func (m ShiftClusterMove) Apply(po *PlacementOptimizer, base *types.Base, items []*types.Item) {
+	placed := placedMovable(base, items)
+	if len(placed) == 0 {
+		return
+	}
+
+	movable := make(map[string]bool, len(placed))
+	for _, item := range placed {
+		movable[item.ID] = true
+	}
+
+	center := placed[po.rng.Intn(len(placed))]
+	step := shiftDirections[po.rng.Intn(len(shiftDirections))]
+
+	var originals []*types.Item
+	for _, item := range base.ItemsNear(center.Position, m.Radius) {
+		if movable[item.ID] {
+			originals = append(originals, item)
+		}
+	}
+	if len(originals) == 0 {
+		originals = []*types.Item{center}
+	}
+
+	shifted := make([]*types.Item, len(originals))
+	for i, original := range originals {
+		item := *original
+		item.Position = types.Position{
+			X: item.Position.X + step.X,
+			Y: item.Position.Y,
+			Z: item.Position.Z + step.Z,
+		}
+		shifted[i] = &item
+	}
+	po.replaceItems(base, originals, shifted)
+}
+
+// placedMovable returns the base's own copies of the items that are placed
+// and not fixed, in the order of items
+func placedMovable(base *types.Base, items []*types.Item) []*types.Item {
+	var placed []*types.Item
+	for _, item := range items {
+		if item.IsFixed() {
+			continue
+		}
+		if current, ok := base.Items[item.ID]; ok {
+			placed = append(placed, current)
+		}
+	}
+	return placed
+}
+
+// replaceItems replaces the originals, items placed in base, with moved,
+// copies of them at new poses, if every copy is allowed where it stands
+// given the others. Otherwise the originals are restored.
+func (po *PlacementOptimizer) replaceItems(base *types.Base, originals, moved []*types.Item) {
+	for _, original := range originals {
+		base.RemoveItem(original.ID)
+	}
+
+	for i, item := range moved {
+		if !po.allowsAt(base, item) || base.PlaceItem(item) != nil {
+			for _, placed := range moved[:i] {
+				base.RemoveItem(placed.ID)
+			}
+			for _, original := range originals {
+				base.PlaceItem(original)
+			}
+			return
+		}
+	}
+}
+
+// applyMove changes base with one operator drawn from the configured
+// MoveOperators by weight, or with GreedyRelocateMove when none are
+// configured
+func (po *PlacementOptimizer) applyMove(base *types.Base, items []*types.Item, moves []WeightedMove) {
+	total := 0.0
+	for _, move := range moves {
+		if move.Weight > 0 {
+			total += move.Weight
+		}
+	}
+	if total <= 0 {
+		GreedyRelocateMove{}.Apply(po, base, items)
+		return
+	}
+
+	pick := po.rng.Float64() * total
+	var chosen MoveOperator
+	for _, move := range moves {
+		if move.Weight <= 0 {
+			continue
+		}
+		// Rounding can leave pick just past the last weight, so the last
+		// operator doubles as the fallback
+		chosen = move.Operator
+		if pick < move.Weight {
+			break
+		}
+		pick -= move.Weight
+	}
+	chosen.Apply(po, base, items)
+}
@@ -0,0 +1,99 @@
+package optimizer
+
+import (
+	"fmt"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestSwapMove(t *testing.T) {
+	base := types.NewBase(10, 2, 10)
+	first := place(t, base, "first", types.StructureNamePalBed, types.Position{X: 1, Z: 1})
+	second := place(t, base, "second", types.StructureNamePalBed, types.Position{X: 7, Z: 6})
+	rock := types.NewItem("rock", types.StructureNameWoodenBarrel)
+	rock.Position, rock.Environmental = types.Position{X: 4, Z: 4}, true
+	if err := base.PlaceItem(rock); err != nil {
+		t.Fatalf("placing rock: %v", err)
+	}
+	items := []*types.Item{first, second, rock}
+
+	po := NewPlacementOptimizer(base)
+	po.configure(testConfig())
+	SwapMove{}.Apply(po, base, items)
+
+	if len(base.Items) != 3 {
+		t.Fatalf("base holds %d items after a swap, want 3", len(base.Items))
+	}
+	checkLayout(t, base, items)
+	if got := base.Items["first"].Position; got != (types.Position{X: 7, Z: 6}) {
+		t.Errorf("first bed at %s, want the second's old position", got)
+	}
+	if got := base.Items["second"].Position; got != (types.Position{X: 1, Z: 1}) {
+		t.Errorf("second bed at %s, want the first's old position", got)
+	}
+	if got := base.Items["rock"].Position; got != rock.Position {
+		t.Errorf("fixed rock moved to %s", got)
+	}
+}
+
+func TestMoveOperatorsKeepLayoutValid(t *testing.T) {
+	items := testItems()
+	for i := range 4 {
+		items = append(items, types.NewItem(fmt.Sprintf("barrel_%d", i), types.StructureNameWoodenBarrel))
+	}
+	start, err := NewPlacementOptimizer(types.NewBase(12, 4, 12)).OptimizePlacement(items, testConfig())
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+
+	for _, move := range DefaultMoveOperators() {
+		t.Run(fmt.Sprintf("%T", move.Operator), func(t *testing.T) {
+			base := start.Base.Clone()
+			po := NewPlacementOptimizer(base)
+			po.configure(testConfig())
+			for range 50 {
+				move.Operator.Apply(po, base, items)
+			}
+			if len(base.Items) != len(items) {
+				t.Errorf("base holds %d items, want %d", len(base.Items), len(items))
+			}
+			checkLayout(t, base, items)
+			// Check each item against the others, as it was when placed
+			for _, item := range base.SortedItems() {
+				others := base.Clone()
+				if err := others.RemoveItem(item.ID); err != nil {
+					t.Fatal(err)
+				}
+				if !po.allowsAt(others, item) {
+					t.Errorf("%s left where it is not allowed, at %s", item.ID, item.Position)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyMoveWeights(t *testing.T) {
+	base := types.NewBase(10, 2, 10)
+	first := place(t, base, "first", types.StructureNamePalBed, types.Position{X: 1, Z: 1})
+	second := place(t, base, "second", types.StructureNamePalBed, types.Position{X: 7, Z: 6})
+	items := []*types.Item{first, second}
+
+	// Only the swap has weight, so every move swaps the beds back and forth
+	moves := []WeightedMove{
+		{Operator: RotateMove{}, Weight: 0},
+		{Operator: SwapMove{}, Weight: 1},
+		{Operator: ShiftClusterMove{Radius: 3}, Weight: -2},
+	}
+	po := NewPlacementOptimizer(base)
+	po.configure(testConfig())
+	for i := range 4 {
+		po.applyMove(base, items, moves)
+		want := types.Position{X: 7, Z: 6}
+		if i%2 == 1 {
+			want = types.Position{X: 1, Z: 1}
+		}
+		if got := base.Items["first"].Position; got != want {
+			t.Fatalf("after %d moves the first bed is at %s, want %s", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestNormalizeTerms(t *testing.T) {
+	optimized := func(t *testing.T) *types.Base {
+		result, err := NewPlacementOptimizer(types.NewBase(10, 2, 10)).OptimizePlacement(testItems(), testConfig())
+		if err != nil {
+			t.Fatalf("OptimizePlacement: %v", err)
+		}
+		return result.Base
+	}
+
+	tests := []struct {
+		name   string
+		layout func(t *testing.T) *types.Base
+	}{
+		{"optimized", optimized},
+		{"spread out", func(t *testing.T) *types.Base {
+			base := types.NewBase(12, 2, 12)
+			place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+			place(t, base, "workbench", types.StructureNameWorkbench, types.Position{X: 10, Z: 11})
+			place(t, base, "bed1", types.StructureNamePalBed, types.Position{X: 11, Z: 0})
+			place(t, base, "foodbox", types.StructureNameFoodBox, types.Position{X: 0, Z: 11})
+			return base
+		}},
+		{"item walled in", func(t *testing.T) *types.Base {
+			base := types.NewBase(8, 2, 8)
+			place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+			place(t, base, "bed1", types.StructureNamePalBed, types.Position{X: 6, Z: 6})
+			for i, pos := range []types.Position{{X: 5, Z: 6}, {X: 5, Z: 7}, {X: 6, Z: 5}, {X: 7, Z: 5}, {X: 5, Z: 5}} {
+				for y := 0; y < 2; y++ {
+					pos.Y = y
+					place(t, base, fmt.Sprintf("wall%d_%d", i, y), types.StructureNameWoodenBarrel, pos)
+				}
+			}
+			return base
+		}},
+		{"no Palbox", func(t *testing.T) *types.Base {
+			base := types.NewBase(8, 1, 8)
+			place(t, base, "workbench", types.StructureNameWorkbench, types.Position{X: 1, Z: 1})
+			place(t, base, "bed1", types.StructureNamePalBed, types.Position{X: 4, Z: 4})
+			return base
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := tt.layout(t)
+			var items []*types.Item
+			for _, item := range base.SortedItems() {
+				items = append(items, item)
+			}
+
+			po := NewPlacementOptimizer(base)
+			raw := po.evaluatePlacement(base, items, po.configure(testConfig()))
+			normalizedConfig := testConfig()
+			normalizedConfig.NormalizeTerms = true
+			config := po.configure(normalizedConfig)
+			normalized := po.evaluatePlacement(base, items, config)
+
+			terms := map[string]float64{
+				"pathfinding": normalized.PathfindingScore,
+				"efficiency":  normalized.EfficiencyScore,
+				"compactness": normalized.CompactnessScore,
+			}
+			for name, value := range terms {
+				if value < 0 || value > 1 || math.IsNaN(value) {
+					t.Errorf("normalized %s = %v, want it in [0,1]", name, value)
+				}
+			}
+
+			// Details keep the raw values
+			rawTerms := map[string]float64{
+				"pathfinding": raw.PathfindingScore,
+				"efficiency":  raw.EfficiencyScore,
+				"compactness": raw.CompactnessScore,
+			}
+			for name, want := range rawTerms {
+				if got := normalized.Details[name]; got != want {
+					t.Errorf("Details[%q] = %v, want the raw %v", name, got, want)
+				}
+			}
+
+			want := config.PathfindingWeight*normalized.PathfindingScore +
+				config.EfficiencyWeight*normalized.EfficiencyScore +
+				config.CompactnessWeight*normalized.CompactnessScore
+			if math.Abs(normalized.TotalScore-want) > 1e-9 {
+				t.Errorf("TotalScore = %v, want the weighted normalized terms %v", normalized.TotalScore, want)
+			}
+			if max := po.estimateMaxScore(base, config); normalized.TotalScore > max+1e-9 {
+				t.Errorf("TotalScore %v exceeds the estimated maximum %v", normalized.TotalScore, max)
+			}
+		})
+	}
+}
+
+func TestBoundingBoxFill(t *testing.T) {
+	tests := []struct {
+		name    string
+		barrels []types.Position
+		want    float64
+	}{
+		{"empty", nil, 0},
+		{"single item", []types.Position{{X: 3, Z: 3}}, 1},
+		{"row with a gap", []types.Position{{X: 0}, {X: 2}}, 2.0 / 3},
+		{"diagonal", []types.Position{{X: 0}, {X: 1, Z: 1}}, 2.0 / 4},
+		{"stacked", []types.Position{{X: 1, Z: 1}, {X: 1, Y: 1, Z: 1}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(5, 2, 5)
+			for i, pos := range tt.barrels {
+				place(t, base, fmt.Sprintf("barrel%d", i), types.StructureNameWoodenBarrel, pos)
+			}
+			if got := boundingBoxFill(base); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("boundingBoxFill() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
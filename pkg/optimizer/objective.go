@@ -0,0 +1,47 @@
+package optimizer
+
+// PathfindingObjective chooses how the walks from the Palboxes to the other
+// items combine into the pathfinding score. Every objective scores
+// unreachable items -50 each and reachable ones 100/(2+cost) each, so all
+// share the same bounds; they differ in which cost a reachable item is
+// scored by.
+type PathfindingObjective int
+
+const (
+	// SumInverse scores each item by its own walk, so many close items can
+	// make up for one far away
+	SumInverse PathfindingObjective = iota
+
+	// MaxCost scores every item by the longest walk, so only shortening the
+	// worst-case walk improves the score
+	MaxCost
+
+	// MeanCost scores every item by the mean walk, trading close items
+	// against far ones linearly in cost rather than in inverse cost
+	MeanCost
+)
+
+// String returns the objective's name
+func (o PathfindingObjective) String() string {
+	switch o {
+	case SumInverse:
+		return "sum_inverse"
+	case MaxCost:
+		return "max_cost"
+	case MeanCost:
+		return "mean_cost"
+	}
+	return "unknown"
+}
+
+// scoredCost returns the cost a reachable item walking cost is scored by,
+// given the longest walk and the total over the reached items
+func (o PathfindingObjective) scoredCost(cost, longest, total float64, reached int) float64 {
+	switch o {
+	case MaxCost:
+		return longest
+	case MeanCost:
+		return total / float64(reached)
+	}
+	return cost
+}
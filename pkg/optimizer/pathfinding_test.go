@@ -0,0 +1,191 @@
+package optimizer
+
+import (
+	"fmt"
+	"math"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestEvaluatePathfindingPalboxes(t *testing.T) {
+	tests := []struct {
+		name       string
+		palboxes   []types.Position
+		wantPalbox map[string]string
+	}{
+		{"no palbox", nil, map[string]string{"bed_east": "", "bed_west": ""}},
+		{"one palbox", []types.Position{{X: 0, Z: 0}}, map[string]string{"bed_east": "palbox0", "bed_west": "palbox0"}},
+		{"two palboxes", []types.Position{{X: 0, Z: 0}, {X: 10, Z: 0}}, map[string]string{"bed_east": "palbox1", "bed_west": "palbox0"}},
+	}
+
+	scores := make([]float64, len(tests))
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(12, 2, 4)
+			for j, pos := range tt.palboxes {
+				place(t, base, "palbox"+string(rune('0'+j)), types.StructureNamePalbox, pos)
+			}
+			place(t, base, "bed_west", types.StructureNamePalBed, types.Position{X: 1, Z: 3})
+			place(t, base, "bed_east", types.StructureNamePalBed, types.Position{X: 10, Z: 3})
+
+			po := NewPlacementOptimizer(base)
+			config := po.configure(testConfig())
+			breakdown := make(map[string]ItemScoreBreakdown)
+			scores[i] = po.evaluatePathfinding(base, base.SortedItems(), config, breakdown)
+
+			for id, want := range tt.wantPalbox {
+				entry := breakdown[id]
+				if entry.Palbox != want {
+					t.Errorf("%s is served by %q, want %q", id, entry.Palbox, want)
+				}
+				if entry.Reachable != (want != "") {
+					t.Errorf("%s reachable = %v", id, entry.Reachable)
+				}
+			}
+		})
+	}
+
+	// Without a Palbox every item is penalized; a second, nearer Palbox
+	// shortens the east bed's walk
+	if scores[0] != -100 {
+		t.Errorf("score without a palbox = %v, want -100", scores[0])
+	}
+	if !(scores[0] < scores[1] && scores[1] < scores[2]) {
+		t.Errorf("scores for zero, one and two palboxes = %v, want increasing", scores)
+	}
+}
+
+// TestPathfindingObjectives scores two layouts of the same beds: one bed
+// beside the Palbox and one at the far end, or both halfway. Summing
+// inverse costs rewards the close bed enough to prefer the first; the max
+// and mean objectives prefer the second, shorter worst-case walk.
+func TestPathfindingObjectives(t *testing.T) {
+	layout := func(t *testing.T, first, second types.Position) *types.Base {
+		base := types.NewBase(24, 2, 3)
+		place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+		place(t, base, "bed_a", types.StructureNamePalBed, first)
+		place(t, base, "bed_b", types.StructureNamePalBed, second)
+		return base
+	}
+	uneven := layout(t, types.Position{X: 3, Z: 0}, types.Position{X: 23, Z: 2})
+	even := layout(t, types.Position{X: 10, Z: 0}, types.Position{X: 10, Z: 2})
+
+	tests := []struct {
+		objective   PathfindingObjective
+		prefersEven bool
+	}{
+		{SumInverse, false},
+		{MaxCost, true},
+		{MeanCost, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.objective.String(), func(t *testing.T) {
+			config := testConfig()
+			config.PathfindingObjective = tt.objective
+			score := func(base *types.Base) float64 {
+				po := NewPlacementOptimizer(base)
+				return po.evaluatePathfinding(base, base.SortedItems(), po.configure(config), nil)
+			}
+			unevenScore, evenScore := score(uneven), score(even)
+			if (evenScore > unevenScore) != tt.prefersEven {
+				t.Errorf("even layout scored %v, uneven %v; want even preferred = %v", evenScore, unevenScore, tt.prefersEven)
+			}
+		})
+	}
+}
+
+func TestPathfindingObjectiveString(t *testing.T) {
+	tests := []struct {
+		objective PathfindingObjective
+		want      string
+	}{
+		{SumInverse, "sum_inverse"},
+		{MaxCost, "max_cost"},
+		{MeanCost, "mean_cost"},
+		{PathfindingObjective(9), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.objective.String(); got != tt.want {
+			t.Errorf("PathfindingObjective(%d).String() = %q, want %q", int(tt.objective), got, tt.want)
+		}
+	}
+}
+
+func TestPathfindingWeights(t *testing.T) {
+	items := []*types.Item{
+		types.NewItem("palbox", types.StructureNamePalbox),
+		types.NewItem("box", types.StructureNameFoodBox),
+		types.NewItem("bed", types.StructureNamePalBed),
+		types.NewItem("barrel", types.StructureNameWoodenBarrel),
+	}
+	items[1].Priority, items[2].Priority, items[3].Priority = 60, 30, 0
+	isPalbox := map[string]bool{"palbox": true}
+
+	tests := []struct {
+		name      string
+		configure func(*OptimizationConfig)
+		want      map[string]float64
+	}{
+		{"unweighted", func(*OptimizationConfig) {}, map[string]float64{"box": 1, "bed": 1, "barrel": 1}},
+		{"by priority", func(c *OptimizationConfig) { c.PriorityWeightedPathfinding = true },
+			map[string]float64{"box": 2, "bed": 1, "barrel": 0}},
+		{"by category", func(c *OptimizationConfig) {
+			c.PriorityWeightedPathfinding = true // ignored in favour of categories
+			c.CategoryPathWeights = map[types.StructureCategory]float64{
+				types.StructureCategoryFood: 4,
+				types.StructureCategoryPals: -3,
+			}
+		}, map[string]float64{"box": 2.4, "bed": 0, "barrel": 0.6}},
+		{"all zero", func(c *OptimizationConfig) {
+			c.CategoryPathWeights = map[types.StructureCategory]float64{
+				types.StructureCategoryFood:    0,
+				types.StructureCategoryPals:    0,
+				types.StructureCategoryStorage: 0,
+			}
+		}, map[string]float64{"box": 1, "bed": 1, "barrel": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			tt.configure(config)
+			got := pathfindingWeights(items, isPalbox, config)
+			if len(got) != len(tt.want) {
+				t.Fatalf("pathfindingWeights() = %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if math.Abs(got[id]-want) > 1e-9 {
+					t.Errorf("pathfindingWeights() = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPriorityPullsFoodBoxTowardsPalbox(t *testing.T) {
+	foodBoxDistance := func(priority int) float64 {
+		items := []*types.Item{
+			types.NewItem("palbox", types.StructureNamePalbox),
+			types.NewItem("box", types.StructureNameFoodBox),
+		}
+		items[1].Priority = priority
+		for i := range 8 {
+			barrel := types.NewItem(fmt.Sprintf("barrel_%d", i), types.StructureNameWoodenBarrel)
+			barrel.Priority = 20
+			items = append(items, barrel)
+		}
+		config := testConfig()
+		config.PriorityWeightedPathfinding = true
+
+		result, err := NewPlacementOptimizer(types.NewBase(10, 3, 10)).OptimizePlacement(items, config)
+		if err != nil {
+			t.Fatalf("OptimizePlacement: %v", err)
+		}
+		return result.Base.Items["box"].Position.Distance(result.Base.Items["palbox"].Position)
+	}
+
+	low, high := foodBoxDistance(1), foodBoxDistance(500)
+	if high >= low {
+		t.Errorf("food box at priority 500 is %v from the Palbox, no closer than %v at priority 1", high, low)
+	}
+}
@@ -1,10 +1,13 @@
 package optimizer
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"math/rand"
 	"palbaseiq/pkg/pathing"
 	"palbaseiq/pkg/types"
+	"slices"
 	"sort"
 	"time"
 )
@@ -13,6 +16,13 @@ import (
 type PlacementOptimizer struct {
 	Base  *types.Base
 	Graph *pathing.Graph
+
+	// Config is the configuration used by placement and scoring. It is
+	// replaced by the config passed to OptimizePlacement.
+	Config *OptimizationConfig
+
+	// rng is seeded from Config.RandomSeed so runs are reproducible
+	rng *rand.Rand
 }
 
 // OptimizationConfig holds configuration for the optimization process
@@ -25,6 +35,188 @@ type OptimizationConfig struct {
 	PathfindingWeight float64
 	EfficiencyWeight  float64
 	CompactnessWeight float64
+
+	// MaxDuration bounds a run in wall-clock time, for interactive use;
+	// zero means no limit. It is checked after every annealing iteration,
+	// and whichever of MaxIterations and MaxDuration runs out first ends
+	// the run with the best layout found so far. The starting layout is
+	// always completed, so a run can overshoot a very short limit by the
+	// time greedy placement takes.
+	MaxDuration time.Duration
+
+	// PathfindingObjective chooses whether the pathfinding term favours
+	// short walks overall (SumInverse, the default), the shortest longest
+	// walk (MaxCost) or the shortest mean walk (MeanCost)
+	PathfindingObjective PathfindingObjective
+
+	// PriorityWeightedPathfinding weights each item's share of the
+	// pathfinding term by its Priority, so that walks to important items
+	// such as food boxes count for more than walks to decor.
+	// CategoryPathWeights, when set, weights items by structure category
+	// instead, treating missing categories as 1. Either way the weights
+	// are scaled to average 1, keeping the term's scale.
+	PriorityWeightedPathfinding bool
+	CategoryPathWeights         map[types.StructureCategory]float64
+
+	// CenterBiasTypes pulls items of the listed types towards Center during
+	// placement, adding weight/(1+distance) to a position's score. Center
+	// defaults to the middle of the base at ground level when nil.
+	CenterBiasTypes map[types.ItemType]float64
+	Center          *types.Position
+
+	// ProximityRadius limits the related-item proximity bonus during
+	// placement to items within this many cells, looked up through the
+	// base's spatial index, which speeds up placement on large bases at the
+	// cost of ignoring farther items. Zero or less, the default, considers
+	// every item.
+	ProximityRadius float64
+
+	// MaxFootprint limits the X/Z extent of the layout. Positions that would
+	// grow the footprint beyond Width x Depth are rejected. A zero Width or
+	// Depth leaves that axis unconstrained.
+	MaxFootprint types.BoundingBox
+
+	// ReheatCount enables an adaptive schedule that resets the temperature
+	// to Temperature up to this many times when the search stalls, either
+	// because the best score has not improved for StallIterations steps or
+	// because the temperature dropped below MinTemperature. Zero disables
+	// reheating.
+	ReheatCount     int
+	StallIterations int
+
+	// ConnectivityWeight enables the optional connectivity term, which
+	// rewards keeping the walkable space in one piece. Zero disables it.
+	ConnectivityWeight float64
+
+	// PerimeterWeight enables the perimeter coverage term, rewarding walls
+	// that fully enclose the base at PerimeterFloor. Zero disables it.
+	PerimeterWeight float64
+	PerimeterFloor  int
+
+	// Groups lists sets of item IDs that should be kept together as a
+	// station. Greedy placement pulls group members towards each other and
+	// GroupWeight scores how tightly the groups are clustered.
+	Groups      [][]string
+	GroupWeight float64
+
+	// AlignmentWeight rewards items sharing rows and columns with other
+	// items. Zero disables it.
+	AlignmentWeight float64
+
+	// SymmetryWeight rewards layouts that mirror about SymmetryAxis, using
+	// Base.SymmetryScore. Zero disables it. Pair it with MirroredStrategy
+	// to start from a symmetric layout.
+	SymmetryWeight float64
+	SymmetryAxis   types.Axis
+
+	// SupplyChains lists the directed supply links between structures, such
+	// as food plots feeding food boxes. SupplyChainWeight rewards short
+	// walks along them; zero disables the term. DefaultSupplyChains
+	// provides the usual food chain.
+	SupplyChains      []SupplyLink
+	SupplyChainWeight float64
+
+	// ProductionChains lists the crafting links between production
+	// stations, such as furnaces feeding workbenches, in the same form as
+	// SupplyChains. ProductionChainWeight rewards short walks along them,
+	// clustering each chain of stations; zero disables the term.
+	// DefaultProductionChains provides the standard stations.
+	ProductionChains      []SupplyLink
+	ProductionChainWeight float64
+
+	// LightWeight rewards layouts whose work cells lie within LightRadius
+	// of a light source, since lit Pals work better. Zero disables the
+	// term; a LightRadius of zero or less uses DefaultLightRadius.
+	LightWeight float64
+	LightRadius float64
+
+	// RetrievalWeight rewards storage within RetrievalRadius of an item
+	// retrieval machine, which can only pull items from storage in range.
+	// Zero disables the term; a RetrievalRadius of zero or less uses
+	// DefaultRetrievalRadius.
+	RetrievalWeight float64
+	RetrievalRadius float64
+
+	// FootprintAspectWeight rewards a footprint whose width:depth ratio is
+	// close to FootprintAspectRatio, complementing compactness, which
+	// happily packs items into a long thin strip. Zero disables the term;
+	// a ratio of zero or less asks for a square.
+	FootprintAspectWeight float64
+	FootprintAspectRatio  float64
+
+	// CommuteWeight rewards short walks from the nearest Pal bed to each
+	// workstation, since Pals set out from their beds to work. Zero
+	// disables the term.
+	CommuteWeight float64
+
+	// AccessRedundancyWeight rewards Palboxes that Pals can approach from
+	// several sides, so that one blocked corridor cannot cut the base off.
+	// Items with a priority of at least AccessRedundancyPriority are
+	// rewarded the same way when it is positive. Zero disables the term.
+	AccessRedundancyWeight   float64
+	AccessRedundancyPriority int
+
+	// MaxBuildWork limits the total structure build work of the placed
+	// items. When the items exceed it, the highest-priority subset that
+	// fits is placed and the rest are reported as dropped. Zero means no
+	// limit.
+	MaxBuildWork int
+
+	// MaxTechLevel is the player's technology level. Items whose structure
+	// unlocks later are replaced by a lower-tier structure doing the same
+	// job, or dropped when there is none. Zero means everything is
+	// unlocked.
+	MaxTechLevel int
+
+	// Constraints reject candidate positions during placement, or penalize
+	// them when they are soft
+	Constraints []PlacementConstraint
+
+	// InitialStrategy builds the starting layout for annealing. Nil uses
+	// GreedyStrategy.
+	InitialStrategy InitialStrategy
+
+	// AcceptanceStrategy decides which candidate layouts annealing moves
+	// to. Nil uses MetropolisAcceptance.
+	AcceptanceStrategy AcceptanceStrategy
+
+	// MoveOperators are the moves annealing builds candidates with, one
+	// drawn by weight each iteration. Nil uses GreedyRelocateMove alone;
+	// DefaultMoveOperators adds random relocations, swaps, rotations and
+	// cluster shifts.
+	MoveOperators []WeightedMove
+
+	// RecordTrace captures every accepted annealing move in the result's
+	// Trace, storing per-step diffs rather than full layouts
+	RecordTrace bool
+
+	// RecordScoreHistory captures the current and best total score after
+	// every annealing iteration in the result's CurrentScores and
+	// BestScores, for plotting convergence
+	RecordScoreHistory bool
+
+	// NormalizeTerms scales the pathfinding, efficiency and compactness
+	// terms onto [0,1] by their theoretical bounds before weighting them,
+	// so that all weights act on comparable scales like the optional
+	// terms, which are already in [0,1]. The PathfindingScore,
+	// EfficiencyScore and CompactnessScore fields, TotalScore and the
+	// scores in a Trace then use the normalized values, while Details and
+	// ItemScores keep the raw ones.
+	NormalizeTerms bool
+
+	// RecordItemScores fills PlacementScore.ItemScores with a per-item
+	// breakdown of every evaluated layout. It adds overhead to each
+	// evaluation, so leave it off outside of debugging.
+	RecordItemScores bool
+
+	// SupportRatios sets how many Pal beds each food structure sustains,
+	// for the Undersupplied warning of OptimizePlacement. Nil uses
+	// types.DefaultSupportRatios.
+	SupportRatios types.SupportRatios
+
+	// Template seeds the base with fixed items that are never moved and
+	// confines slot-eligible items to their tagged regions
+	Template *types.Template
 }
 
 // DefaultConfig returns a default optimization configuration
@@ -38,89 +230,530 @@ func DefaultConfig() *OptimizationConfig {
 		PathfindingWeight: 0.4,
 		EfficiencyWeight:  0.3,
 		CompactnessWeight: 0.3,
+		CenterBiasTypes: map[types.ItemType]float64{
+			types.ItemTypePalbox: 100.0,
+		},
+		Constraints:   []PlacementConstraint{SupportConstraint{}, AccessConstraint{}, ExposureConstraint{}},
+		MoveOperators: DefaultMoveOperators(),
+	}
+}
+
+// Validate reports every setting of the config that would make annealing
+// misbehave: a CoolingRate outside (0,1), which never cools, a non-positive
+// MaxIterations, a negative MaxDuration, a MinTemperature at or above
+// Temperature, or a negative weight. Nil means the config is usable.
+func (c *OptimizationConfig) Validate() error {
+	var errs []error
+	if c.CoolingRate <= 0 || c.CoolingRate >= 1 {
+		errs = append(errs, fmt.Errorf("CoolingRate %g must be between 0 and 1, exclusive", c.CoolingRate))
+	}
+	if c.MaxIterations <= 0 {
+		errs = append(errs, fmt.Errorf("MaxIterations %d must be positive", c.MaxIterations))
+	}
+	if c.MaxDuration < 0 {
+		errs = append(errs, fmt.Errorf("MaxDuration %s must not be negative", c.MaxDuration))
+	}
+	if c.MinTemperature >= c.Temperature {
+		errs = append(errs, fmt.Errorf("MinTemperature %g must be below Temperature %g", c.MinTemperature, c.Temperature))
+	}
+
+	for _, weight := range c.scoringWeights() {
+		if *weight.value < 0 {
+			errs = append(errs, fmt.Errorf("%s %g must not be negative", weight.name, *weight.value))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid optimization config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// namedWeight points at one of a config's scoring weights
+type namedWeight struct {
+	name  string
+	value *float64
+}
+
+// scoringWeights lists the weights of every scoring term
+func (c *OptimizationConfig) scoringWeights() []namedWeight {
+	return []namedWeight{
+		{"PathfindingWeight", &c.PathfindingWeight},
+		{"EfficiencyWeight", &c.EfficiencyWeight},
+		{"CompactnessWeight", &c.CompactnessWeight},
+		{"ConnectivityWeight", &c.ConnectivityWeight},
+		{"PerimeterWeight", &c.PerimeterWeight},
+		{"GroupWeight", &c.GroupWeight},
+		{"AlignmentWeight", &c.AlignmentWeight},
+		{"SymmetryWeight", &c.SymmetryWeight},
+		{"SupplyChainWeight", &c.SupplyChainWeight},
+		{"ProductionChainWeight", &c.ProductionChainWeight},
+		{"LightWeight", &c.LightWeight},
+		{"RetrievalWeight", &c.RetrievalWeight},
+		{"FootprintAspectWeight", &c.FootprintAspectWeight},
+		{"CommuteWeight", &c.CommuteWeight},
+		{"AccessRedundancyWeight", &c.AccessRedundancyWeight},
+	}
+}
+
+// Normalize scales the scoring weights so that they sum to 1, keeping their
+// proportions. Weights of 4, 3 and 3 therefore behave exactly like 0.4, 0.3
+// and 0.3. Weights summing to zero or less are left alone, for Validate to
+// report.
+func (c *OptimizationConfig) Normalize() {
+	weights := c.scoringWeights()
+	sum := 0.0
+	for _, weight := range weights {
+		sum += *weight.value
+	}
+	if sum <= 0 {
+		return
+	}
+	for _, weight := range weights {
+		*weight.value /= sum
 	}
 }
 
 // NewPlacementOptimizer creates a new placement optimizer
 func NewPlacementOptimizer(base *types.Base) *PlacementOptimizer {
 	graph := pathing.NewGraph(base)
+	config := DefaultConfig()
 	return &PlacementOptimizer{
-		Base:  base,
-		Graph: graph,
+		Base:   base,
+		Graph:  graph,
+		Config: config,
+		rng:    rand.New(rand.NewSource(config.RandomSeed)),
 	}
 }
 
 // PlacementScore represents the score of a placement configuration
 type PlacementScore struct {
-	TotalScore       float64
-	PathfindingScore float64
-	EfficiencyScore  float64
-	CompactnessScore float64
-	Details          map[string]float64
+	TotalScore             float64
+	PathfindingScore       float64
+	EfficiencyScore        float64
+	CompactnessScore       float64
+	ConnectivityScore      float64
+	PerimeterCoverageScore float64
+	GroupScore             float64
+	AlignmentScore         float64
+	SymmetryScore          float64
+	SupplyChainScore       float64
+	ProductionChainScore   float64
+	LightCoverageScore     float64
+	RetrievalScore         float64
+	FootprintAspectScore   float64
+	CommuteScore           float64
+	AccessRedundancyScore  float64
+	Details                map[string]float64
+
+	// ItemScores breaks the pathfinding and efficiency terms down by item
+	// ID. It is diagnostic only and is nil unless RecordItemScores is set.
+	ItemScores map[string]ItemScoreBreakdown
+
+	// MaxScore is an estimate of the best TotalScore achievable for the
+	// evaluated base and item set, used to scale Rating
+	MaxScore float64
 }
 
-// OptimizePlacement optimizes the placement of items in the base
-func (po *PlacementOptimizer) OptimizePlacement(items []*types.Item, config *OptimizationConfig) (*types.Base, *PlacementScore, error) {
-	if config == nil {
-		config = DefaultConfig()
+// ItemScoreBreakdown is one item's share of the pathfinding and efficiency
+// terms of a PlacementScore
+type ItemScoreBreakdown struct {
+	// Reachable reports whether a path from a Palbox reaches the item.
+	// PathCost is the cost of the walk from the nearest Palbox, 0 for a
+	// Palbox itself and +Inf when the item is unreachable. Palbox is the ID
+	// of that nearest Palbox, empty when the item is unreachable.
+	Reachable bool
+	PathCost  float64
+	Palbox    string
+
+	// Pathfinding is the item's contribution to PathfindingScore
+	Pathfinding float64
+
+	// Efficiency is the item's contribution to EfficiencyScore from its
+	// proximity to related items
+	Efficiency float64
+}
+
+// Rating maps TotalScore onto a 0-100 scale relative to MaxScore
+func (ps *PlacementScore) Rating() float64 {
+	if ps.MaxScore <= 0 {
+		return 0.0
 	}
 
-	// Set random seed
-	rand.Seed(config.RandomSeed)
+	rating := 100.0 * ps.TotalScore / ps.MaxScore
+	return math.Max(0.0, math.Min(100.0, rating))
+}
+
+// PlacementResult holds the outcome of an optimization run
+type PlacementResult struct {
+	Base  *types.Base
+	Score *PlacementScore
+
+	// Unplaced lists the requested items that could not be fit into the
+	// base. A non-empty slice means the base is too small for the items.
+	Unplaced []*types.Item
+
+	// Dropped lists the items left out to stay within MaxBuildWork or
+	// because nothing at MaxTechLevel can stand in for them
+	Dropped []*types.Item
+
+	// Substituted lists the lower-tier items placed in place of requested
+	// items above MaxTechLevel. Each keeps the ID of the item it replaces.
+	Substituted []*types.Item
+
+	// Undersupplied lists the support structures the requested items have
+	// too few of to feed their Pal beds, under SupportRatios
+	Undersupplied []types.SupportShortfall
+
+	// MissingDependencies lists the items placed without a structure they
+	// depend on (see StructureDefinition.DependsOn)
+	MissingDependencies []types.MissingDependency
+
+	// Trace is set when RecordTrace is enabled
+	Trace *Trace
+
+	// CurrentScores and BestScores hold the total score of the current
+	// layout and of the best layout so far after each annealing iteration.
+	// They are nil unless RecordScoreHistory is enabled.
+	CurrentScores []float64
+	BestScores    []float64
+}
+
+// OptimizePlacement optimizes the placement of items in the base. It builds
+// a starting layout with GreedyPlace (or the configured InitialStrategy) and
+// refines it with Anneal. A config that fails Validate is rejected before
+// anything is placed.
+func (po *PlacementOptimizer) OptimizePlacement(items []*types.Item, config *OptimizationConfig) (*PlacementResult, error) {
+	config, err := po.configureValid(config)
+	if err != nil {
+		return nil, err
+	}
+	deadline := config.deadline()
+
+	// Only build what the player has unlocked
+	items, substituted, locked := gateTechLevel(items, config.MaxTechLevel)
+
+	// Reject items that could never fit before spending time optimizing
+	for _, item := range items {
+		if err := po.Base.CheckFits(item); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create a copy of the base for optimization
-	optimizedBase := po.Base.Clone()
+	startBase := po.Base.Clone()
 
-	// Build the pathfinding graph
-	po.Graph.Base = optimizedBase
-	po.Graph.BuildGraph()
+	if config.Template != nil {
+		if err := config.Template.Apply(startBase); err != nil {
+			return nil, err
+		}
+	}
 
-	// Sort items by priority (higher priority first)
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].Priority > items[j].Priority
+	// Sort items by priority (higher priority first). Equal priorities are
+	// ordered by item ID so that placement order never depends on the
+	// input order.
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Priority != items[j].Priority {
+			return items[i].Priority > items[j].Priority
+		}
+		return items[i].ID < items[j].ID
 	})
 
-	// Initial placement using greedy algorithm
-	po.placeItemsGreedy(optimizedBase, items)
+	// Keep only the most valuable items that fit the build work budget
+	items, dropped := selectWithinBudget(items, config.MaxBuildWork)
+	dropped = append(locked, dropped...)
+
+	// Place what other structures depend on first
+	items = types.OrderByDependencies(items)
+
+	// Initial placement, greedy unless another strategy is configured
+	var initialBase *types.Base
+	if config.InitialStrategy == nil {
+		initialBase = po.GreedyPlace(startBase, items)
+	} else {
+		initialBase = startBase.Clone()
+		po.Graph.Base = initialBase
+		config.InitialStrategy.Initialize(po, initialBase, items)
+	}
+
+	result, err := po.anneal(initialBase, items, config, deadline)
+	if err != nil {
+		return nil, err
+	}
+	result.Dropped = dropped
+	result.Substituted = substituted
+
+	ratios := config.SupportRatios
+	if ratios == nil {
+		ratios = types.DefaultSupportRatios
+	}
+	result.Undersupplied = ratios.CheckSupport(items)
+	result.MissingDependencies = types.CheckDependencies(items)
+
+	return result, nil
+}
+
+// configure makes a normalized copy of config (see Normalize) the active
+// configuration, falling back to the defaults for nil, and reseeds the
+// random source so a fixed seed reproduces the run. The caller's config is
+// not modified.
+func (po *PlacementOptimizer) configure(config *OptimizationConfig) *OptimizationConfig {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	normalized := *config
+	normalized.Normalize()
+	normalized.Constraints = po.bindConstraints(normalized.Constraints)
+	config = &normalized
+	po.Config = config
+	po.rng = rand.New(rand.NewSource(config.RandomSeed))
+	return config
+}
+
+// configureValid is configure for a config that must first pass Validate.
+// The caller's values are validated before normalizing rescales them, so
+// errors quote the weights as they were set.
+func (po *PlacementOptimizer) configureValid(config *OptimizationConfig) (*OptimizationConfig, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return po.configure(config), nil
+}
+
+// bindConstraints returns the constraints with every AccessConstraint that
+// has no Graph of its own checking with the optimizer's graph, so it walks
+// the same MovementPlane and shares its distance fields. The caller's slice
+// is not modified.
+func (po *PlacementOptimizer) bindConstraints(constraints []PlacementConstraint) []PlacementConstraint {
+	bound := slices.Clone(constraints)
+	for i, constraint := range bound {
+		if access, ok := constraint.(AccessConstraint); ok && access.Graph == nil {
+			bound[i] = AccessConstraint{Graph: po.Graph}
+		}
+	}
+	return bound
+}
+
+// GreedyPlace places the items into a copy of base, one at a time in the
+// given order, each at the position that scores best against the items
+// already placed. Fixed environmental items go in first at their own
+// positions.
+//
+// Precondition: base holds any fixed layout the items must fit around and
+// is not modified. The optimizer's Config must be set (OptimizePlacement
+// and Anneal set it). Post-condition: the returned base contains every item
+// that could be fit; the others are left out. Placed items have their
+// Position updated.
+func (po *PlacementOptimizer) GreedyPlace(base *types.Base, items []*types.Item) *types.Base {
+	placed := base.Clone()
+
+	// Score blocking against the layout as it fills up
+	po.Graph.Base = placed
+	po.placeItemsGreedy(placed, items)
+	return placed
+}
+
+// Anneal refines an already placed layout with simulated annealing,
+// repeatedly relocating one of the movable items and accepting or rejecting
+// the move according to config.
+//
+// Precondition: base already contains the items that are to be optimized,
+// for example the output of GreedyPlace; items absent from base are only
+// placed if a perturbation finds room for them. A nil items slice refines
+// the items base already holds, other than the fixed items of the configured
+// Template, which lets a layout be re-optimized in place after items were
+// removed from it, for example by RemoveByCategory.
+// Post-condition: base is not modified, and the returned layout never scores
+// below base. Items missing from the returned layout are listed in Unplaced.
+// MaxDuration counts from the call. Like OptimizePlacement, it fails
+// without annealing when config does not pass Validate.
+func (po *PlacementOptimizer) Anneal(base *types.Base, items []*types.Item, config *OptimizationConfig) (*PlacementResult, error) {
+	config, err := po.configureValid(config)
+	if err != nil {
+		return nil, err
+	}
+	return po.anneal(base, items, config, config.deadline())
+}
 
-	// Optimize using simulated annealing
-	bestBase := optimizedBase.Clone()
-	bestScore := po.evaluatePlacement(optimizedBase, items, config)
+// deadline returns when a run starting now must stop under MaxDuration, or
+// the zero time when there is no limit, without reading the clock
+func (c *OptimizationConfig) deadline() time.Time {
+	if c.MaxDuration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(c.MaxDuration)
+}
+
+// anneal is Anneal with a configured config, stopping once deadline has
+// passed unless it is zero
+func (po *PlacementOptimizer) anneal(base *types.Base, items []*types.Item, config *OptimizationConfig, deadline time.Time) (*PlacementResult, error) {
+	if items == nil {
+		items = placedItems(base, config.Template)
+	}
+
+	// Point the pathfinding graph at the layout being refined
+	currentBase := base.Clone()
+	po.Graph.Base = currentBase
+
+	// The best layout starts out as the starting one and is only replaced
+	// by higher scores, so the result never scores below base
+	startBase := base.Clone()
+	startScore := po.evaluatePlacement(startBase, items, config)
+
+	currentScore := startScore
+	bestBase := startBase.Clone()
+	bestScore := startScore
+
+	var trace *Trace
+	if config.RecordTrace {
+		trace = &Trace{Initial: diffBases(nil, startBase)}
+	}
+
+	acceptance := config.AcceptanceStrategy
+	if acceptance == nil {
+		acceptance = MetropolisAcceptance{}
+	}
+
+	// The histories grow as the run goes, since MaxDuration may end it long
+	// before MaxIterations
+	var currentScores, bestScores []float64
 
 	temperature := config.Temperature
+	reheats := 0
+	stalled := 0
 
 	for iteration := 0; iteration < config.MaxIterations; iteration++ {
 		// Create a new candidate by perturbing the current placement
-		candidateBase := optimizedBase.Clone()
-		po.perturbPlacement(candidateBase, items)
+		candidateBase := currentBase.Clone()
+		po.applyMove(candidateBase, items, config.MoveOperators)
 
 		// Evaluate the candidate
 		candidateScore := po.evaluatePlacement(candidateBase, items, config)
 
-		// Accept or reject based on simulated annealing
-		if po.shouldAccept(bestScore.TotalScore, candidateScore.TotalScore, temperature) {
-			optimizedBase = candidateBase
+		// Accept or reject relative to the current state, not the best one
+		stalled++
+		accepted := acceptance.Accept(currentScore.TotalScore, candidateScore.TotalScore, IterationContext{
+			Iteration:          iteration,
+			MaxIterations:      config.MaxIterations,
+			Temperature:        temperature,
+			InitialTemperature: config.Temperature,
+			InitialScore:       startScore.TotalScore,
+			BestScore:          bestScore.TotalScore,
+			Rand:               po.rng,
+		})
+		if accepted {
+			if trace != nil {
+				trace.Steps = append(trace.Steps, TraceStep{
+					Iteration:   iteration,
+					Temperature: temperature,
+					Score:       candidateScore.TotalScore,
+					Moves:       diffBases(currentBase, candidateBase),
+				})
+			}
+
+			currentBase = candidateBase
+			currentScore = candidateScore
+			po.Graph.Base = currentBase
 
 			// Update best if this is better
 			if candidateScore.TotalScore > bestScore.TotalScore {
 				bestBase = candidateBase.Clone()
 				bestScore = candidateScore
+				stalled = 0
 			}
 		}
 
+		if config.RecordScoreHistory {
+			currentScores = append(currentScores, currentScore.TotalScore)
+			bestScores = append(bestScores, bestScore.TotalScore)
+		}
+
+		// Only a time-limited run reads the clock
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+
 		// Cool down
 		temperature *= config.CoolingRate
+
+		// Reheat to escape a local optimum while reheats remain
+		stuck := config.StallIterations > 0 && stalled >= config.StallIterations
+		if reheats < config.ReheatCount && (stuck || temperature < config.MinTemperature) {
+			temperature = config.Temperature
+			reheats++
+			stalled = 0
+			continue
+		}
+
 		if temperature < config.MinTemperature {
 			break
 		}
 	}
 
-	return bestBase, bestScore, nil
+	return &PlacementResult{
+		Base:          bestBase,
+		Score:         bestScore,
+		Unplaced:      unplacedItems(bestBase, items),
+		Trace:         trace,
+		CurrentScores: currentScores,
+		BestScores:    bestScores,
+	}, nil
+}
+
+// placedItems returns the items in base sorted by ID, so that annealing a
+// layout without an explicit item list stays deterministic. The copies of
+// the template's fixed items are left out, since they must never move.
+func placedItems(base *types.Base, template *types.Template) []*types.Item {
+	fixed := make(map[string]bool)
+	if template != nil {
+		for _, item := range template.Fixed {
+			fixed[item.ID] = true
+		}
+	}
+
+	items := make([]*types.Item, 0, len(base.Items))
+	for _, item := range base.Items {
+		if !fixed[item.ID] {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// unplacedItems returns the items that are missing from the base
+func unplacedItems(base *types.Base, items []*types.Item) []*types.Item {
+	var unplaced []*types.Item
+	for _, item := range items {
+		if _, placed := base.Items[item.ID]; !placed {
+			unplaced = append(unplaced, item)
+		}
+	}
+	return unplaced
+}
+
+// placeFixedItems places fixed environmental features at their given
+// positions so that every movable item is placed around them
+func (po *PlacementOptimizer) placeFixedItems(base *types.Base, items []*types.Item) {
+	for _, item := range items {
+		if item.IsFixed() {
+			base.PlaceItem(item)
+		}
+	}
 }
 
 // placeItemsGreedy places items using a greedy algorithm
 func (po *PlacementOptimizer) placeItemsGreedy(base *types.Base, items []*types.Item) {
+	po.placeFixedItems(base, items)
+
 	for _, item := range items {
+		if item.IsFixed() {
+			continue
+		}
+
 		bestPosition := po.findBestPosition(base, item)
 		if bestPosition != nil {
 			item.Position = *bestPosition
@@ -129,53 +762,163 @@ func (po *PlacementOptimizer) placeItemsGreedy(base *types.Base, items []*types.
 	}
 }
 
-// findBestPosition finds the best position for an item
+// validPositions returns every position where the item could be placed
+// right now, honoring its anchor points, the footprint limit, the template
+// slots and the placement constraints
+func (po *PlacementOptimizer) validPositions(base *types.Base, item *types.Item) []types.Position {
+	footprint := newFootprintLimit(base, po.Config.MaxFootprint)
+
+	var valid []types.Position
+	consider := func(pos types.Position) bool {
+		// Check the cells first, which needs no copy of the item
+		if !base.CanPlaceAt(item, pos, item.Rotation) {
+			return true
+		}
+
+		testItem := *item
+		testItem.Position = pos
+		if footprint.allows(&testItem) && po.templateAllows(&testItem) && po.constraintsAllow(base, &testItem) {
+			valid = append(valid, pos)
+		}
+		return true
+	}
+
+	// Try different positions, limited to the item's anchor points if it
+	// has any. Free positions are streamed rather than collected, since
+	// this runs for every item on every placement.
+	if len(item.CandidatePositions) > 0 {
+		for _, pos := range item.CandidatePositions {
+			consider(pos)
+		}
+	} else {
+		base.EachFreePosition(consider)
+	}
+
+	return valid
+}
+
+// allowsAt reports whether the item may be placed at its current position,
+// applying the same checks as validPositions
+func (po *PlacementOptimizer) allowsAt(base *types.Base, item *types.Item) bool {
+	if len(item.CandidatePositions) > 0 && !slices.Contains(item.CandidatePositions, item.Position) {
+		return false
+	}
+	return base.CanPlaceItem(item) && newFootprintLimit(base, po.Config.MaxFootprint).allows(item) &&
+		po.templateAllows(item) && po.constraintsAllow(base, item)
+}
+
+// findBestPosition finds the best position for an item, or nil if there is
+// none. FindBestPlacement wraps it with the reason for a failure.
 func (po *PlacementOptimizer) findBestPosition(base *types.Base, item *types.Item) *types.Position {
+	return po.bestPositionAmong(base, item, po.validPositions(base, item))
+}
+
+// bestPositionAmong returns the highest scoring of the given positions for
+// the item, or nil if there are none. Ties go to the lowest X, then Y, then
+// Z, whatever order the positions come in.
+func (po *PlacementOptimizer) bestPositionAmong(base *types.Base, item *types.Item, positions []types.Position) *types.Position {
 	var bestPosition *types.Position
 	bestScore := math.Inf(-1)
 
-	// Try different positions
-	freePositions := base.GetFreePositions()
-	for _, pos := range freePositions {
-		// Check if item can be placed here
-		testItem := &types.Item{
-			ID:       item.ID,
-			Type:     item.Type,
-			Position: pos,
-			Bounds:   item.Bounds,
-			Rotation: item.Rotation,
-			Priority: item.Priority,
-		}
-
-		if base.CanPlaceItem(testItem) {
-			score := po.evaluateItemPosition(base, testItem)
-			if score > bestScore {
-				bestScore = score
-				bestPosition = &pos
-			}
+	for _, pos := range positions {
+		testItem := *item
+		testItem.Position = pos
+
+		// Soft constraint violations count against the position
+		score := po.evaluateItemPosition(base, &testItem) - po.constraintViolation(base, &testItem)
+		if bestPosition == nil || score > bestScore || (score == bestScore && pos.Less(*bestPosition)) {
+			// Point at a copy, never at the loop variable
+			best := pos
+			bestScore = score
+			bestPosition = &best
 		}
 	}
 
 	return bestPosition
 }
 
+// templateAllows reports whether the configured template's slots permit
+// the item at its current position
+func (po *PlacementOptimizer) templateAllows(item *types.Item) bool {
+	return po.Config.Template == nil || po.Config.Template.Allows(item)
+}
+
+// footprintLimit checks candidate placements against a maximum X/Z extent
+type footprintLimit struct {
+	max                    types.BoundingBox
+	minX, maxX, minZ, maxZ int
+	empty                  bool
+}
+
+// newFootprintLimit captures the current X/Z extent of the movable items in
+// the base
+func newFootprintLimit(base *types.Base, maxFootprint types.BoundingBox) *footprintLimit {
+	limit := &footprintLimit{max: maxFootprint, empty: true}
+	if low, high := base.FootprintBounds(); high.X >= low.X {
+		limit.minX, limit.maxX = low.X, high.X
+		limit.minZ, limit.maxZ = low.Z, high.Z
+		limit.empty = false
+	}
+	return limit
+}
+
+// extend grows the tracked extent to include the item
+func (fl *footprintLimit) extend(item *types.Item) {
+	bounds := item.EffectiveBounds()
+	lowX, highX := item.Position.X, item.Position.X+bounds.Width-1
+	lowZ, highZ := item.Position.Z, item.Position.Z+bounds.Depth-1
+	if fl.empty {
+		fl.minX, fl.maxX, fl.minZ, fl.maxZ = lowX, highX, lowZ, highZ
+		fl.empty = false
+		return
+	}
+	fl.minX, fl.maxX = min(fl.minX, lowX), max(fl.maxX, highX)
+	fl.minZ, fl.maxZ = min(fl.minZ, lowZ), max(fl.maxZ, highZ)
+}
+
+// allows reports whether placing the item keeps the extent within the limit
+func (fl *footprintLimit) allows(item *types.Item) bool {
+	if item.IsFixed() || (fl.max.Width <= 0 && fl.max.Depth <= 0) {
+		return true
+	}
+
+	grown := *fl
+	grown.extend(item)
+	if fl.max.Width > 0 && grown.maxX-grown.minX+1 > fl.max.Width {
+		return false
+	}
+	if fl.max.Depth > 0 && grown.maxZ-grown.minZ+1 > fl.max.Depth {
+		return false
+	}
+	return true
+}
+
 // evaluateItemPosition evaluates how good a position is for an item
 func (po *PlacementOptimizer) evaluateItemPosition(base *types.Base, item *types.Item) float64 {
 	score := 0.0
 
 	// Prefer positions near the center for important items
-	if item.Type == types.ItemTypePalbox {
+	if weight := po.Config.CenterBiasTypes[item.Type]; weight != 0 {
 		center := types.Position{X: base.Width / 2, Y: 0, Z: base.Depth / 2}
+		if po.Config.Center != nil {
+			center = *po.Config.Center
+		}
 		distance := item.Position.Distance(center)
-		score += 100.0 / (1.0 + distance)
+		score += weight / (1.0 + distance)
 	}
 
 	// Prefer positions near related items
 	score += po.evaluateProximityToRelatedItems(base, item)
 
+	// Prefer positions near members of the same group
+	score += po.evaluateProximityToGroupMates(base, item)
+
 	// Prefer positions that don't block paths
 	score += po.evaluatePathAccessibility(base, item)
 
+	// Keep heavily weighted destinations close to the Palboxes
+	score += po.evaluatePalboxPull(base, item)
+
 	return score
 }
 
@@ -186,7 +929,14 @@ func (po *PlacementOptimizer) evaluateProximityToRelatedItems(base *types.Base,
 	// Define related item types
 	relatedItems := po.getRelatedItemTypes(item.Type)
 
-	for _, existingItem := range base.Items {
+	// With a proximity radius only items within it contribute, saving the
+	// scan over every item per candidate position
+	nearby := base.SortedItems()
+	if po.Config.ProximityRadius > 0 {
+		nearby = base.ItemsNear(item.Position, po.Config.ProximityRadius)
+	}
+
+	for _, existingItem := range nearby {
 		if relatedItems[existingItem.Type] {
 			distance := item.Position.Distance(existingItem.Position)
 			score += 10.0 / (1.0 + distance)
@@ -198,27 +948,7 @@ func (po *PlacementOptimizer) evaluateProximityToRelatedItems(base *types.Base,
 
 // getRelatedItemTypes returns item types that are related to the given type
 func (po *PlacementOptimizer) getRelatedItemTypes(itemType types.ItemType) map[types.ItemType]bool {
-	related := make(map[types.ItemType]bool)
-
-	switch itemType {
-	case types.ItemTypeFoodBox:
-		related[types.ItemTypeFoodPlot] = true
-		related[types.ItemTypeCookingPot] = true
-	case types.ItemTypeFoodPlot:
-		related[types.ItemTypeFoodBox] = true
-		related[types.ItemTypeCookingPot] = true
-	case types.ItemTypePowerGenerator:
-		related[types.ItemTypeAccumulator] = true
-		related[types.ItemTypeWorkbench] = true
-	case types.ItemTypeWorkbench:
-		related[types.ItemTypePowerGenerator] = true
-		related[types.ItemTypeStorage] = true
-	case types.ItemTypeStorage:
-		related[types.ItemTypeWorkbench] = true
-		related[types.ItemTypeFurnace] = true
-	}
-
-	return related
+	return types.RelatedItemTypes(itemType)
 }
 
 // evaluatePathAccessibility evaluates how well an item placement maintains path accessibility
@@ -248,18 +978,20 @@ func (po *PlacementOptimizer) calculateIsolationPenalty(base *types.Base, item *
 func (po *PlacementOptimizer) calculateBlockingPenalty(base *types.Base, item *types.Item) float64 {
 	penalty := 0.0
 
-	// Check if item blocks access to important items
-	for _, existingItem := range base.Items {
-		if existingItem.Type == types.ItemTypePalbox {
-			// Check if path to Palbox is blocked
-			path, err := po.Graph.FindPath(item.Position, existingItem.Position)
-			if err != nil {
-				penalty += 50.0 // High penalty for blocking Palbox access
-			} else {
-				// Lower penalty for longer paths
-				penalty += path.Cost * 0.1
-			}
+	// Check if item blocks access to important items. Every candidate of
+	// an item is scored against the same layout, so the walking costs come
+	// from each Palbox's cached distance field rather than a search per
+	// candidate.
+	graph := *po.Graph
+	graph.Base = base
+	for _, palbox := range base.ItemsByName(types.StructureNamePalbox) {
+		cost, reachable := graph.DistanceField(palbox.Position)[pathing.GetNodeKey(item.Position)]
+		if !reachable {
+			penalty += 50.0 // High penalty for blocking Palbox access
+			continue
 		}
+		// Lower penalty for longer walks
+		penalty += cost * 0.1
 	}
 
 	return penalty
@@ -267,36 +999,35 @@ func (po *PlacementOptimizer) calculateBlockingPenalty(base *types.Base, item *t
 
 // perturbPlacement creates a perturbation of the current placement
 func (po *PlacementOptimizer) perturbPlacement(base *types.Base, items []*types.Item) {
-	// Randomly select an item to move
-	if len(items) == 0 {
+	// Randomly select an item to move, never touching fixed features
+	movable := make([]*types.Item, 0, len(items))
+	for _, item := range items {
+		if !item.IsFixed() {
+			movable = append(movable, item)
+		}
+	}
+
+	if len(movable) == 0 {
 		return
 	}
 
-	itemIndex := rand.Intn(len(items))
-	item := items[itemIndex]
+	itemIndex := po.rng.Intn(len(movable))
+
+	// Work on a copy so the caller's items and other bases sharing them
+	// are never mutated by a rejected candidate
+	item := *movable[itemIndex]
 
 	// Remove the item
-	base.RemoveItem(item.ID)
+	if _, placed := base.Items[item.ID]; placed {
+		base.RemoveItem(item.ID)
+	}
 
 	// Find a new position
-	newPosition := po.findBestPosition(base, item)
+	newPosition := po.findBestPosition(base, &item)
 	if newPosition != nil {
 		item.Position = *newPosition
-		base.PlaceItem(item)
-	}
-}
-
-// shouldAccept determines if a candidate should be accepted in simulated annealing
-func (po *PlacementOptimizer) shouldAccept(currentScore, candidateScore, temperature float64) bool {
-	if candidateScore > currentScore {
-		return true
+		base.PlaceItem(&item)
 	}
-
-	// Calculate acceptance probability
-	delta := candidateScore - currentScore
-	probability := math.Exp(delta / temperature)
-
-	return rand.Float64() < probability
 }
 
 // evaluatePlacement evaluates the overall quality of a placement
@@ -304,85 +1035,468 @@ func (po *PlacementOptimizer) evaluatePlacement(base *types.Base, items []*types
 	score := &PlacementScore{
 		Details: make(map[string]float64),
 	}
+	if config.RecordItemScores {
+		score.ItemScores = make(map[string]ItemScoreBreakdown)
+	}
 
 	// Evaluate pathfinding efficiency
-	pathfindingScore := po.evaluatePathfinding(base, items)
+	pathfindingScore := po.evaluatePathfinding(base, items, config, score.ItemScores)
 	score.PathfindingScore = pathfindingScore
 
 	// Evaluate efficiency (proximity of related items)
-	efficiencyScore := po.evaluateEfficiency(base, items)
+	efficiencyScore := po.evaluateEfficiency(base, items, score.ItemScores)
 	score.EfficiencyScore = efficiencyScore
 
 	// Evaluate compactness
 	compactnessScore := po.evaluateCompactness(base)
 	score.CompactnessScore = compactnessScore
 
+	// Store detailed scores
+	score.Details["pathfinding"] = pathfindingScore
+	score.Details["efficiency"] = efficiencyScore
+	score.Details["compactness"] = compactnessScore
+
+	// Bring the core terms onto a common [0,1] scale so their weights are
+	// comparable. Details keeps the raw values.
+	if config.NormalizeTerms {
+		pathfindingScore, efficiencyScore, compactnessScore =
+			po.normalizeCoreTerms(base, pathfindingScore, efficiencyScore, compactnessScore)
+		score.PathfindingScore = pathfindingScore
+		score.EfficiencyScore = efficiencyScore
+		score.CompactnessScore = compactnessScore
+	}
+
 	// Calculate weighted total score
 	score.TotalScore = config.PathfindingWeight*pathfindingScore +
 		config.EfficiencyWeight*efficiencyScore +
 		config.CompactnessWeight*compactnessScore
 
-	// Store detailed scores
-	score.Details["pathfinding"] = pathfindingScore
-	score.Details["efficiency"] = efficiencyScore
-	score.Details["compactness"] = compactnessScore
+	// Optional terms are only evaluated when weighted
+	if config.ConnectivityWeight != 0 {
+		score.ConnectivityScore = po.evaluateConnectivity(base)
+		score.TotalScore += config.ConnectivityWeight * score.ConnectivityScore
+		score.Details["connectivity"] = score.ConnectivityScore
+	}
+
+	if config.PerimeterWeight != 0 {
+		score.PerimeterCoverageScore = po.evaluatePerimeterCoverage(base, config.PerimeterFloor)
+		score.TotalScore += config.PerimeterWeight * score.PerimeterCoverageScore
+		score.Details["perimeter_coverage"] = score.PerimeterCoverageScore
+	}
+
+	if config.GroupWeight != 0 {
+		score.GroupScore = po.evaluateGroups(base, config.Groups)
+		score.TotalScore += config.GroupWeight * score.GroupScore
+		score.Details["groups"] = score.GroupScore
+	}
+
+	if config.AlignmentWeight != 0 {
+		score.AlignmentScore = po.evaluateAlignment(base)
+		score.TotalScore += config.AlignmentWeight * score.AlignmentScore
+		score.Details["alignment"] = score.AlignmentScore
+	}
+
+	if config.SymmetryWeight != 0 {
+		score.SymmetryScore = base.SymmetryScore(config.SymmetryAxis)
+		score.TotalScore += config.SymmetryWeight * score.SymmetryScore
+		score.Details["symmetry"] = score.SymmetryScore
+	}
+
+	if config.SupplyChainWeight != 0 && len(config.SupplyChains) > 0 {
+		score.SupplyChainScore = po.evaluateSupplyChains(base, config.SupplyChains)
+		score.TotalScore += config.SupplyChainWeight * score.SupplyChainScore
+		score.Details["supply_chains"] = score.SupplyChainScore
+	}
+
+	if config.ProductionChainWeight != 0 && len(config.ProductionChains) > 0 {
+		score.ProductionChainScore = po.evaluateSupplyChains(base, config.ProductionChains)
+		score.TotalScore += config.ProductionChainWeight * score.ProductionChainScore
+		score.Details["production_chains"] = score.ProductionChainScore
+	}
+
+	if config.LightWeight != 0 {
+		score.LightCoverageScore = LightCoverageScore(base, config.LightRadius)
+		score.TotalScore += config.LightWeight * score.LightCoverageScore
+		score.Details["light_coverage"] = score.LightCoverageScore
+	}
+
+	if config.RetrievalWeight != 0 {
+		score.RetrievalScore = RetrievalCoverageScore(base, config.RetrievalRadius)
+		score.TotalScore += config.RetrievalWeight * score.RetrievalScore
+		score.Details["retrieval"] = score.RetrievalScore
+	}
+
+	if config.FootprintAspectWeight != 0 {
+		score.FootprintAspectScore = FootprintAspectScore(base, config.FootprintAspectRatio)
+		score.TotalScore += config.FootprintAspectWeight * score.FootprintAspectScore
+		score.Details["footprint_aspect"] = score.FootprintAspectScore
+	}
+
+	if config.CommuteWeight != 0 {
+		score.CommuteScore = po.evaluateCommute(base)
+		score.TotalScore += config.CommuteWeight * score.CommuteScore
+		score.Details["commute"] = score.CommuteScore
+	}
+
+	if config.AccessRedundancyWeight != 0 {
+		score.AccessRedundancyScore = po.evaluateAccessRedundancy(base, config.AccessRedundancyPriority)
+		score.TotalScore += config.AccessRedundancyWeight * score.AccessRedundancyScore
+		score.Details["access_redundancy"] = score.AccessRedundancyScore
+	}
+
+	score.MaxScore = po.estimateMaxScore(base, config)
 
 	return score
 }
 
-// evaluatePathfinding evaluates the pathfinding efficiency of the placement
-func (po *PlacementOptimizer) evaluatePathfinding(base *types.Base, items []*types.Item) float64 {
-	score := 0.0
+// coreTermBounds returns upper bounds on the pathfinding and efficiency terms
+// for the items placed in the base. The pathfinding term is also bounded
+// below by the negated bound, when every item is unreachable.
+func (po *PlacementOptimizer) coreTermBounds(base *types.Base) (pathfinding, efficiency float64) {
+	if len(base.Items) == 0 {
+		return 0.0, 0.0
+	}
+
+	// Pathfinding: 100/(1+cost) per item reachable from a Palbox, where
+	// every walk costs at least one step
+	palboxes := len(base.ItemsByName(types.StructureNamePalbox))
+	pathfinding = 50.0 * float64(len(base.Items)-palboxes)
 
-	// Find the Palbox
-	var palbox *types.Item
+	// Efficiency: 20/(1+distance) per ordered pair of related items
 	for _, item := range base.Items {
-		if item.Type == types.ItemTypePalbox {
-			palbox = item
-			break
+		related := po.getRelatedItemTypes(item.Type)
+		for _, other := range base.Items {
+			if item.ID != other.ID && related[other.Type] {
+				efficiency += 10.0
+			}
 		}
 	}
 
-	if palbox == nil {
+	return pathfinding, efficiency
+}
+
+// normalizeCoreTerms maps the raw pathfinding, efficiency and compactness
+// terms onto [0,1] using the bounds from coreTermBounds. Pathfinding spans
+// from every item unreachable (0) to every item one step away (1), so it is
+// 0 without a Palbox. The raw compactness divides by the span between the
+// outermost cells, which can exceed 1, so it is replaced by the share of
+// the inclusive bounding box the items fill.
+func (po *PlacementOptimizer) normalizeCoreTerms(base *types.Base, pathfinding, efficiency, compactness float64) (float64, float64, float64) {
+	maxPathfinding, maxEfficiency := po.coreTermBounds(base)
+
+	normalized := func(value, low, high float64) float64 {
+		if high <= low {
+			return 0.0
+		}
+		return math.Max(0.0, math.Min(1.0, (value-low)/(high-low)))
+	}
+
+	return normalized(pathfinding, -maxPathfinding, maxPathfinding), normalized(efficiency, 0, maxEfficiency), boundingBoxFill(base)
+}
+
+// boundingBoxFill returns the fraction of the cells in the inclusive
+// bounding box of all items that the items occupy, in [0,1]
+func boundingBoxFill(base *types.Base) float64 {
+	occupied := 0
+	var low, high types.Position
+	for _, item := range base.Items {
+		for _, pos := range item.GetOccupiedPositions() {
+			if occupied == 0 {
+				low, high = pos, pos
+			}
+			low = types.Position{X: min(low.X, pos.X), Y: min(low.Y, pos.Y), Z: min(low.Z, pos.Z)}
+			high = types.Position{X: max(high.X, pos.X), Y: max(high.Y, pos.Y), Z: max(high.Z, pos.Z)}
+			occupied++
+		}
+	}
+
+	if occupied == 0 {
+		return 0.0
+	}
+	volume := (high.X - low.X + 1) * (high.Y - low.Y + 1) * (high.Z - low.Z + 1)
+	return float64(occupied) / float64(volume)
+}
+
+// estimateMaxScore returns an upper bound on TotalScore for the items placed
+// in the base. Every path costs at least one step, related items sit at
+// least one cell apart, and the bounding volume of a layout is at least one
+// cell, which bounds each term by the item count and the base size.
+func (po *PlacementOptimizer) estimateMaxScore(base *types.Base, config *OptimizationConfig) float64 {
+	itemCount := len(base.Items)
+	if itemCount == 0 {
 		return 0.0
 	}
 
-	// Evaluate paths from Palbox to all other items
+	maxPathfinding, maxEfficiency := po.coreTermBounds(base)
+
+	// Compactness: item volume over a bounding volume of at least one cell
+	itemVolume := 0
 	for _, item := range base.Items {
-		if item.ID == palbox.ID {
+		itemVolume += item.Bounds.Volume()
+	}
+	maxCompactness := float64(min(itemVolume, base.Width*base.Height*base.Depth))
+
+	// Normalized terms top out at 1
+	if config.NormalizeTerms {
+		maxPathfinding, maxEfficiency, maxCompactness = 1.0, 1.0, 1.0
+	}
+
+	terms := []struct{ weight, max float64 }{
+		{config.PathfindingWeight, maxPathfinding},
+		{config.EfficiencyWeight, maxEfficiency},
+		{config.CompactnessWeight, maxCompactness},
+		{config.ConnectivityWeight, 1.0},
+		{config.PerimeterWeight, 1.0},
+		{config.GroupWeight, 1.0},
+		{config.AlignmentWeight, 1.0},
+		{config.SymmetryWeight, 1.0},
+		{config.SupplyChainWeight, 1.0},
+		{config.ProductionChainWeight, 1.0},
+		{config.LightWeight, 1.0},
+		{config.RetrievalWeight, 1.0},
+		{config.FootprintAspectWeight, 1.0},
+		{config.CommuteWeight, 1.0},
+		{config.AccessRedundancyWeight, 1.0},
+	}
+
+	maxScore := 0.0
+	for _, term := range terms {
+		if term.weight > 0 {
+			maxScore += term.weight * term.max
+		}
+	}
+
+	return maxScore
+}
+
+// evaluatePathfinding evaluates the pathfinding efficiency of the placement.
+// Every item other than a Palbox is scored by the walk from its nearest
+// Palbox: a single search starts from the free cells beside every Palbox
+// and ends at the free cells beside each item, or at its access cell for
+// items used from one side, and the step onto the item counts as one more
+// unit of cost. Reachable items add 100/(1+cost), where the configured
+// PathfindingObjective picks the item's own cost or the longest or mean cost
+// over the reachable items; unreachable ones add -50. Each contribution is
+// scaled by the item's weight from pathfindingWeights. Without a Palbox
+// every item is unreachable, so a missing Palbox is penalized rather than
+// ignored.
+// When breakdown is non-nil each item's path and contribution are recorded
+// in it.
+func (po *PlacementOptimizer) evaluatePathfinding(base *types.Base, items []*types.Item, config *OptimizationConfig, breakdown map[string]ItemScoreBreakdown) float64 {
+	score := 0.0
+
+	// Remember which Palbox each start cell belongs to, preferring the
+	// first by ID where two Palboxes share a cell
+	palboxes := base.ItemsByName(types.StructureNamePalbox)
+	isPalbox := make(map[string]bool, len(palboxes))
+	startOwner := make(map[types.Position]string)
+	var starts []types.Position
+	for _, palbox := range palboxes {
+		isPalbox[palbox.ID] = true
+		for _, pos := range base.AdjacentFreePositions(palbox) {
+			if _, taken := startOwner[pos]; !taken {
+				startOwner[pos] = palbox.ID
+				starts = append(starts, pos)
+			}
+		}
+		if breakdown != nil {
+			breakdown[palbox.ID] = ItemScoreBreakdown{Reachable: true, Palbox: palbox.ID}
+		}
+	}
+
+	// Evaluate paths from the Palboxes to all other items with one search
+	access := make(map[string][]types.Position)
+	var targets []types.Position
+	sorted := base.SortedItems()
+	for _, item := range sorted {
+		if !isPalbox[item.ID] {
+			access[item.ID] = accessPositions(base, item)
+			targets = append(targets, access[item.ID]...)
+		}
+	}
+	// Search the evaluated layout with the optimizer's cost settings
+	graph := *po.Graph
+	graph.Base = base
+	paths := graph.ShortestPathsFromAny(starts, targets)
+
+	// Pick each item's cheapest walk, tracking the longest and total cost
+	// over the reachable items for the objectives that need them
+	bestPaths := make(map[string]*pathing.Path)
+	longest, total := 0.0, 0.0
+	for _, item := range sorted {
+		if isPalbox[item.ID] {
+			continue
+		}
+
+		var best *pathing.Path
+		for _, pos := range access[item.ID] {
+			if path, ok := paths[pathing.GetNodeKey(pos)]; ok && (best == nil || path.Cost < best.Cost) {
+				best = path
+			}
+		}
+		if best != nil {
+			bestPaths[item.ID] = best
+			longest = math.Max(longest, best.Cost)
+			total += best.Cost
+		}
+	}
+
+	weights := pathfindingWeights(sorted, isPalbox, config)
+	for _, item := range sorted {
+		if isPalbox[item.ID] {
 			continue
 		}
 
-		path, err := po.Graph.FindPath(palbox.Position, item.Position)
-		if err == nil {
+		best := bestPaths[item.ID]
+		contribution := -50.0 // Penalty for unreachable items
+		if best != nil {
 			// Shorter paths are better
-			score += 100.0 / (1.0 + path.Cost)
-		} else {
-			// Penalty for unreachable items
-			score -= 50.0
+			cost := config.PathfindingObjective.scoredCost(best.Cost, longest, total, len(bestPaths))
+			contribution = 100.0 / (2.0 + cost)
+		}
+		contribution *= weights[item.ID]
+		score += contribution
+
+		if breakdown != nil {
+			entry := breakdown[item.ID]
+			entry.Reachable = best != nil
+			entry.PathCost = math.Inf(1)
+			entry.Palbox = ""
+			if best != nil {
+				entry.PathCost = best.Cost + 1.0
+				entry.Palbox = startOwner[best.Nodes[0]]
+			}
+			entry.Pathfinding = contribution
+			breakdown[item.ID] = entry
 		}
 	}
 
 	return score
 }
 
-// evaluateEfficiency evaluates the efficiency of item placement
-func (po *PlacementOptimizer) evaluateEfficiency(base *types.Base, items []*types.Item) float64 {
+// pathfindingWeights returns the weight of each non-Palbox item in the
+// pathfinding term (see itemPathWeight), scaled to average 1 so the term
+// keeps its bounds however the weights are chosen. Weights summing to zero
+// fall back to 1 each.
+func pathfindingWeights(items []*types.Item, isPalbox map[string]bool, config *OptimizationConfig) map[string]float64 {
+	weights := make(map[string]float64, len(items))
+	sum, count := 0.0, 0
+	for _, item := range items {
+		if isPalbox[item.ID] {
+			continue
+		}
+		weight := itemPathWeight(item, config)
+		weights[item.ID] = weight
+		sum += weight
+		count++
+	}
+
+	for id, weight := range weights {
+		if sum <= 0 {
+			weights[id] = 1.0
+		} else {
+			weights[id] = weight * float64(count) / sum
+		}
+	}
+	return weights
+}
+
+// itemPathWeight returns how much the walk to the item counts: its
+// CategoryPathWeights entry when that map is set (1 for missing
+// categories), else its Priority when PriorityWeightedPathfinding is set,
+// else 1. Negative weights count as 0.
+func itemPathWeight(item *types.Item, config *OptimizationConfig) float64 {
+	weight := 1.0
+	if config.CategoryPathWeights != nil {
+		if def, err := item.Type.Definition(); err == nil {
+			if categoryWeight, ok := config.CategoryPathWeights[def.Category]; ok {
+				weight = categoryWeight
+			}
+		}
+	} else if config.PriorityWeightedPathfinding {
+		weight = float64(item.Priority)
+	}
+	return math.Max(weight, 0.0)
+}
+
+// evaluatePalboxPull pulls the item towards the nearest Palbox by
+// 10*weight/(1+distance), where weight is its itemPathWeight over the mean
+// of the placed items', so that greedy placement and relocation favour the
+// same items the weighted pathfinding term does. It is 0 unless
+// PriorityWeightedPathfinding or CategoryPathWeights is set.
+func (po *PlacementOptimizer) evaluatePalboxPull(base *types.Base, item *types.Item) float64 {
+	if !po.Config.PriorityWeightedPathfinding && po.Config.CategoryPathWeights == nil {
+		return 0.0
+	}
+	if hasName(item, types.StructureNamePalbox) {
+		return 0.0
+	}
+
+	nearest := math.Inf(1)
+	for _, palbox := range base.ItemsByName(types.StructureNamePalbox) {
+		if palbox.ID != item.ID {
+			nearest = math.Min(nearest, item.Position.Distance(palbox.Position))
+		}
+	}
+	sum, count := itemPathWeight(item, po.Config), 1
+	for _, other := range base.SortedItems() {
+		if other.ID != item.ID && !hasName(other, types.StructureNamePalbox) {
+			sum += itemPathWeight(other, po.Config)
+			count++
+		}
+	}
+	if math.IsInf(nearest, 1) || sum <= 0 {
+		return 0.0
+	}
+
+	weight := itemPathWeight(item, po.Config) * float64(count) / sum
+	return 10.0 * weight / (1.0 + nearest)
+}
+
+// accessPositions returns the cells a Pal can use the item from: the
+// traversable cells of its access face when it has an access side, or else
+// every free cell beside it
+func accessPositions(base *types.Base, item *types.Item) []types.Position {
+	if item.RequiredAccessSide() == types.SideNone {
+		return base.AdjacentFreePositions(item)
+	}
+	var cells []types.Position
+	for _, cell := range base.AccessFaceCells(item) {
+		if base.IsTraversable(cell) {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// evaluateEfficiency evaluates the efficiency of item placement. When
+// breakdown is non-nil each item's contribution is recorded in it.
+func (po *PlacementOptimizer) evaluateEfficiency(base *types.Base, items []*types.Item, breakdown map[string]ItemScoreBreakdown) float64 {
 	score := 0.0
 
-	for _, item := range base.Items {
+	// Sum in a fixed order so equal layouts score identically
+	sorted := base.SortedItems()
+	for _, item := range sorted {
 		relatedItems := po.getRelatedItemTypes(item.Type)
+		contribution := 0.0
 
-		for _, otherItem := range base.Items {
+		for _, otherItem := range sorted {
 			if item.ID == otherItem.ID {
 				continue
 			}
 
 			if relatedItems[otherItem.Type] {
 				distance := item.Position.Distance(otherItem.Position)
-				score += 20.0 / (1.0 + distance)
+				contribution += 20.0 / (1.0 + distance)
 			}
 		}
+		score += contribution
+
+		if breakdown != nil {
+			entry := breakdown[item.ID]
+			entry.Efficiency = contribution
+			breakdown[item.ID] = entry
+		}
 	}
 
 	return score
@@ -411,7 +1525,7 @@ func (po *PlacementOptimizer) evaluateCompactness(base *types.Base) float64 {
 
 	// Calculate total item volume
 	totalItemVolume := 0.0
-	for _, item := range base.Items {
+	for _, item := range base.SortedItems() {
 		totalItemVolume += float64(item.Bounds.Volume())
 	}
 
@@ -422,3 +1536,36 @@ func (po *PlacementOptimizer) evaluateCompactness(base *types.Base) float64 {
 
 	return 0.0
 }
+
+// evaluateConnectivity returns the fraction of free space that belongs to
+// the largest connected free region, in [0,1]. A layout that leaves the
+// walkable area in one piece scores 1.
+func (po *PlacementOptimizer) evaluateConnectivity(base *types.Base) float64 {
+	totalFree := 0
+	largest := 0
+	for _, region := range base.ConnectedFreeRegions() {
+		totalFree += len(region)
+		if len(region) > largest {
+			largest = len(region)
+		}
+	}
+
+	if totalFree == 0 {
+		return 0.0
+	}
+
+	return float64(largest) / float64(totalFree)
+}
+
+// evaluatePerimeterCoverage returns the fraction of the base's boundary
+// cells at level y covered by defensive structures, in [0,1]. A fully
+// enclosed base scores 1.
+func (po *PlacementOptimizer) evaluatePerimeterCoverage(base *types.Base, y int) float64 {
+	total := len(base.PerimeterCells(y))
+	if total == 0 {
+		return 0.0
+	}
+
+	gaps := len(base.PerimeterGaps(y))
+	return float64(total-gaps) / float64(total)
+}
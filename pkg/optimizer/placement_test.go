@@ -0,0 +1,484 @@
+package optimizer
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"palbaseiq/pkg/types"
+	"slices"
+	"testing"
+)
+
+// place puts a new item of the named structure at pos, failing the test if
+// it does not fit
+func place(t testing.TB, base *types.Base, id string, name types.StructureName, pos types.Position) *types.Item {
+	t.Helper()
+	item := types.NewItem(id, name)
+	item.Position = pos
+	if err := base.PlaceItem(item); err != nil {
+		t.Fatalf("placing %s: %v", id, err)
+	}
+	return item
+}
+
+// testConfig returns a short, seeded run suitable for tests
+func testConfig() *OptimizationConfig {
+	config := DefaultConfig()
+	config.MaxIterations = 50
+	config.RandomSeed = 1
+	return config
+}
+
+// testItems returns a small base's worth of unplaced items
+func testItems() []*types.Item {
+	return []*types.Item{
+		types.NewItem("palbox", types.StructureNamePalbox),
+		types.NewItem("workbench", types.StructureNameWorkbench),
+		types.NewItem("bed1", types.StructureNamePalBed),
+		types.NewItem("bed2", types.StructureNamePalBed),
+		types.NewItem("foodbox", types.StructureNameFoodBox),
+	}
+}
+
+// checkLayout fails the test unless every item was placed in base, inside
+// its bounds and without overlapping another item
+func checkLayout(t *testing.T, base *types.Base, items []*types.Item) {
+	t.Helper()
+	owners := make(map[types.Position]string)
+	for _, want := range items {
+		item, ok := base.Items[want.ID]
+		if !ok {
+			t.Errorf("%s was not placed", want.ID)
+			continue
+		}
+		for _, pos := range item.GetOccupiedPositions() {
+			if !base.IsPositionValid(pos) {
+				t.Errorf("%s occupies %s, outside the base", item.ID, pos)
+			}
+			if owner, taken := owners[pos]; taken {
+				t.Errorf("%s and %s both occupy %s", owner, item.ID, pos)
+			}
+			owners[pos] = item.ID
+		}
+	}
+}
+
+func TestEvaluateConnectivity(t *testing.T) {
+	tests := []struct {
+		name  string
+		walls []types.Position
+		want  float64
+	}{
+		{"contiguous", []types.Position{{X: 0}, {X: 1}, {X: 2}, {X: 3}}, 1.0},
+		{"fragmented", []types.Position{{X: 1}, {X: 0, Z: 1}}, 22.0 / 23.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(5, 1, 5)
+			for i, pos := range tt.walls {
+				place(t, base, string(rune('a'+i)), types.StructureNameWoodenBarrel, pos)
+			}
+
+			po := NewPlacementOptimizer(base)
+			if got := po.evaluateConnectivity(base); got != tt.want {
+				t.Errorf("evaluateConnectivity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnealNeverScoresBelowGreedy(t *testing.T) {
+	for seed := int64(1); seed <= 10; seed++ {
+		config := testConfig()
+		config.RandomSeed = seed
+
+		base := types.NewBase(10, 3, 10)
+		po := NewPlacementOptimizer(base)
+		config = po.configure(config)
+		items := testItems()
+		greedy := po.GreedyPlace(base, items)
+		greedyScore := po.evaluatePlacement(greedy, items, config).TotalScore
+
+		result, err := po.Anneal(greedy, items, config)
+		if err != nil {
+			t.Fatalf("seed %d: Anneal: %v", seed, err)
+		}
+		if result.Score.TotalScore < greedyScore {
+			t.Errorf("seed %d: annealed score %v is below the greedy score %v", seed, result.Score.TotalScore, greedyScore)
+		}
+	}
+}
+
+func TestOptimizePlacementReportsUnplacedItems(t *testing.T) {
+	base := types.NewBase(2, 1, 2)
+	var items []*types.Item
+	for i := 0; i < 6; i++ {
+		items = append(items, types.NewItem(string(rune('a'+i)), types.StructureNameWoodenBarrel))
+	}
+
+	result, err := NewPlacementOptimizer(base).OptimizePlacement(items, testConfig())
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+
+	if got := len(result.Base.Items); got != 4 {
+		t.Errorf("placed %d items, want 4", got)
+	}
+	if got := len(result.Unplaced); got != 2 {
+		t.Fatalf("got %d unplaced items, want 2", got)
+	}
+	for _, item := range result.Unplaced {
+		if _, placed := result.Base.Items[item.ID]; placed {
+			t.Errorf("item %s is reported unplaced but is in the base", item.ID)
+		}
+	}
+}
+
+func TestEvaluatePerimeterCoverage(t *testing.T) {
+	ring := []types.Position{
+		{X: 0, Z: 0}, {X: 1, Z: 0}, {X: 2, Z: 0}, {X: 0, Z: 1},
+		{X: 2, Z: 1}, {X: 0, Z: 2}, {X: 1, Z: 2}, {X: 2, Z: 2},
+	}
+	tests := []struct {
+		name  string
+		walls []types.Position
+		want  float64
+	}{
+		{"open", nil, 0},
+		{"half", ring[:4], 0.5},
+		{"enclosed", ring, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(3, 2, 3)
+			for i, pos := range tt.walls {
+				place(t, base, string(rune('a'+i)), types.StructureNameOuterWall, pos)
+			}
+
+			po := NewPlacementOptimizer(base)
+			if got := po.evaluatePerimeterCoverage(base, 0); got != tt.want {
+				t.Errorf("evaluatePerimeterCoverage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptimizePlacementCandidatePositions(t *testing.T) {
+	candidates := []types.Position{{X: 1, Z: 6}, {X: 6, Z: 1}, {X: 7, Z: 7}}
+
+	tests := []struct {
+		name     string
+		blockers []types.Position
+		placed   bool
+	}{
+		{"free candidates", nil, true},
+		{"one candidate free", candidates[:2], true},
+		{"all candidates occupied", candidates, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(8, 2, 8)
+			for i, pos := range tt.blockers {
+				place(t, base, string(rune('a'+i)), types.StructureNameWoodenBarrel, pos)
+			}
+
+			items := testItems()
+			chest := types.NewItem("chest", types.StructureNameWoodenBarrel)
+			chest.CandidatePositions = candidates
+			items = append(items, chest)
+
+			config := testConfig()
+			config.MoveOperators = DefaultMoveOperators()
+			result, err := NewPlacementOptimizer(base).OptimizePlacement(items, config)
+			if err != nil {
+				t.Fatalf("OptimizePlacement: %v", err)
+			}
+
+			placed, ok := result.Base.Items["chest"]
+			if ok != tt.placed {
+				t.Fatalf("chest placed = %v, want %v", ok, tt.placed)
+			}
+			if ok && !slices.Contains(candidates, placed.Position) {
+				t.Errorf("chest placed at %s, which is not a candidate", placed.Position)
+			}
+		})
+	}
+}
+
+func TestPlacementScoreRating(t *testing.T) {
+	tests := []struct {
+		name       string
+		total, max float64
+		want       float64
+	}{
+		{"zero", 0, 200, 0},
+		{"half", 100, 200, 50},
+		{"best", 200, 200, 100},
+		{"above the estimate", 300, 200, 100},
+		{"negative", -50, 200, 0},
+		{"no estimate", 100, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := &PlacementScore{TotalScore: tt.total, MaxScore: tt.max}
+			if got := score.Rating(); got != tt.want {
+				t.Errorf("Rating() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlacementScoreRatingMonotonic(t *testing.T) {
+	base := types.NewBase(10, 3, 10)
+	po := NewPlacementOptimizer(base)
+	config := po.configure(testConfig())
+	items := testItems()
+	layout := po.GreedyPlace(base, items)
+	score := po.evaluatePlacement(layout, items, config)
+
+	previous := -1.0
+	for _, total := range []float64{-10, 0, score.TotalScore / 2, score.TotalScore, score.MaxScore, 2 * score.MaxScore} {
+		scaled := *score
+		scaled.TotalScore = total
+		rating := scaled.Rating()
+		if rating < 0 || rating > 100 {
+			t.Errorf("TotalScore %v: rating %v is outside [0,100]", total, rating)
+		}
+		if rating < previous {
+			t.Errorf("TotalScore %v: rating %v fell below %v", total, rating, previous)
+		}
+		previous = rating
+	}
+}
+
+func TestReheatingEscapesLocalOptima(t *testing.T) {
+	// A random start explored only by random relocations under a schedule
+	// that freezes within a few iterations leaves a rugged landscape in
+	// which plain annealing stops at whatever optimum it first hits
+	run := func(seed int64, reheats int) float64 {
+		config := testConfig()
+		config.RandomSeed = seed
+		config.MaxIterations = 200
+		config.Temperature = 10
+		config.CoolingRate = 0.5
+		config.InitialStrategy = RandomStrategy{}
+		config.MoveOperators = []WeightedMove{{Operator: RandomRelocateMove{}, Weight: 1}}
+		config.ReheatCount = reheats
+		config.StallIterations = 10
+
+		result, err := NewPlacementOptimizer(types.NewBase(10, 3, 10)).OptimizePlacement(testItems(), config)
+		if err != nil {
+			t.Fatalf("seed %d: OptimizePlacement: %v", seed, err)
+		}
+		return result.Score.TotalScore
+	}
+
+	improved := 0
+	for seed := int64(1); seed <= 5; seed++ {
+		plain, reheated := run(seed, 0), run(seed, 20)
+		if reheated < plain {
+			t.Errorf("seed %d: reheated score %v is below the plain score %v", seed, reheated, plain)
+		}
+		if reheated > plain {
+			improved++
+		}
+	}
+	if improved == 0 {
+		t.Error("reheating never improved on plain annealing")
+	}
+}
+
+func TestMaxFootprintCompactsLayout(t *testing.T) {
+	footprint := func(limit types.BoundingBox) (int, int) {
+		items := testItems()
+		for i := 0; i < 8; i++ {
+			items = append(items, types.NewItem(string(rune('a'+i)), types.StructureNameFoodPlot))
+		}
+		config := testConfig()
+		config.MaxFootprint = limit
+
+		result, err := NewPlacementOptimizer(types.NewBase(16, 3, 16)).OptimizePlacement(items, config)
+		if err != nil {
+			t.Fatalf("OptimizePlacement: %v", err)
+		}
+		if len(result.Unplaced) > 0 {
+			t.Fatalf("%d items left unplaced", len(result.Unplaced))
+		}
+		low, high := result.Base.FootprintBounds()
+		return high.X - low.X + 1, high.Z - low.Z + 1
+	}
+
+	width, depth := footprint(types.BoundingBox{Width: 5, Depth: 5})
+	if width > 5 || depth > 5 {
+		t.Errorf("constrained footprint is %dx%d, want at most 5x5", width, depth)
+	}
+	freeWidth, freeDepth := footprint(types.BoundingBox{})
+	if width*depth >= freeWidth*freeDepth {
+		t.Errorf("constrained footprint %dx%d is no smaller than unconstrained %dx%d", width, depth, freeWidth, freeDepth)
+	}
+}
+
+func TestOptimizePlacementIsDeterministic(t *testing.T) {
+	run := func(reverse bool) map[string]types.Position {
+		items := testItems()
+		for i := 0; i < 6; i++ {
+			items = append(items, types.NewItem(string(rune('a'+i)), types.StructureNamePalBed))
+		}
+		if reverse {
+			slices.Reverse(items)
+		}
+
+		result, err := NewPlacementOptimizer(types.NewBase(10, 3, 10)).OptimizePlacement(items, testConfig())
+		if err != nil {
+			t.Fatalf("OptimizePlacement: %v", err)
+		}
+		positions := make(map[string]types.Position)
+		for _, bed := range result.Base.ItemsByName(types.StructureNamePalBed) {
+			positions[bed.ID] = bed.Position
+		}
+		return positions
+	}
+
+	first := run(false)
+	for _, reverse := range []bool{false, true} {
+		if got := run(reverse); !maps.Equal(got, first) {
+			t.Errorf("reversed input %v: beds at %v, first run at %v", reverse, got, first)
+		}
+	}
+}
+
+func TestCenterBiasPullsTowardsCenter(t *testing.T) {
+	center := types.Position{X: 7, Z: 7}
+
+	// A food plot in the corner pulls the food box away from the center
+	base := types.NewBase(10, 2, 10)
+	place(t, base, "plot", types.StructureNameFoodPlot, types.Position{X: 0, Z: 0})
+
+	distance := func(weight float64) float64 {
+		t.Helper()
+		config := testConfig()
+		config.Center = &center
+		config.CenterBiasTypes = map[types.ItemType]float64{types.ItemTypeFoodBox: weight}
+
+		po := NewPlacementOptimizer(base)
+		po.configure(config)
+		pos := po.findBestPosition(base, types.NewItem("foodbox", types.StructureNameFoodBox))
+		if pos == nil {
+			t.Fatalf("no position for the food box at weight %v", weight)
+		}
+		return pos.Distance(center)
+	}
+
+	weights := []float64{0, 10, 100, 1000}
+	previous := math.Inf(1)
+	for _, weight := range weights {
+		got := distance(weight)
+		if got > previous {
+			t.Errorf("weight %v placed the food box %v from the center, farther than %v at a lower weight", weight, got, previous)
+		}
+		previous = got
+	}
+	if near, far := distance(weights[len(weights)-1]), distance(weights[0]); near >= far {
+		t.Errorf("the highest weight left the food box %v from the center, no closer than %v unbiased", near, far)
+	}
+	if got := distance(weights[len(weights)-1]); got != 0 {
+		t.Errorf("a dominant weight placed the food box %v from the center, want 0", got)
+	}
+}
+
+func TestDefaultCenterBias(t *testing.T) {
+	config := DefaultConfig()
+	for itemType, weight := range config.CenterBiasTypes {
+		if itemType != types.ItemTypePalbox && weight != 0 {
+			t.Errorf("default center bias for %s is %v, want 0", itemType, weight)
+		}
+	}
+	if got := config.CenterBiasTypes[types.ItemTypePalbox]; got != 100 {
+		t.Errorf("default palbox center bias is %v, want 100", got)
+	}
+}
+
+func TestOptimizePlacementRejectsOversizedItems(t *testing.T) {
+	result, err := NewPlacementOptimizer(types.NewBase(1, 16, 1)).OptimizePlacement(testItems(), testConfig())
+	if err == nil {
+		t.Fatalf("OptimizePlacement() placed %d items in a base smaller than the palbox", len(result.Base.Items))
+	}
+	if want := "item palbox (2x2x2) cannot fit in base 1x16x1 along axis X"; err.Error() != want {
+		t.Errorf("OptimizePlacement() error = %q, want %q", err, want)
+	}
+}
+
+func TestBestPositionTieBreaking(t *testing.T) {
+	base := types.NewBase(8, 3, 8)
+	po := NewPlacementOptimizer(base)
+	po.configure(testConfig())
+	item := types.NewItem("barrel", types.StructureNameWoodenBarrel)
+
+	tied := []types.Position{{X: 4, Z: 4}, {X: 4, Z: 3}, {X: 3, Y: 1, Z: 4}, {X: 3, Z: 4}, {X: 3, Y: 1, Z: 3}}
+	for _, pos := range tied[1:] {
+		a, b := *item, *item
+		a.Position, b.Position = tied[0], pos
+		if po.evaluateItemPosition(base, &a) != po.evaluateItemPosition(base, &b) {
+			t.Fatalf("%s and %s do not tie", tied[0], pos)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		positions []types.Position
+		want      types.Position
+	}{
+		{"lower X wins", []types.Position{{X: 4, Z: 3}, {X: 3, Z: 4}}, types.Position{X: 3, Z: 4}},
+		{"then lower Y", []types.Position{{X: 3, Y: 1, Z: 3}, {X: 3, Z: 4}}, types.Position{X: 3, Z: 4}},
+		{"then lower Z", []types.Position{{X: 4, Z: 4}, {X: 4, Z: 3}}, types.Position{X: 4, Z: 3}},
+		{"any order", tied, types.Position{X: 3, Z: 4}},
+		{"none", nil, types.Position{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The result must not change with the order positions come in,
+			// nor alias the loop variable
+			reversed := slices.Clone(tt.positions)
+			slices.Reverse(reversed)
+			for _, positions := range [][]types.Position{tt.positions, reversed} {
+				got := po.bestPositionAmong(base, item, positions)
+				if len(positions) == 0 {
+					if got != nil {
+						t.Errorf("bestPositionAmong() = %s with no positions", got)
+					}
+					continue
+				}
+				if got == nil || *got != tt.want {
+					t.Errorf("bestPositionAmong(%v) = %v, want %s", positions, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluatePlacementIsBitIdentical(t *testing.T) {
+	base := types.NewBase(20, 16, 20)
+	items := testItems()
+	for i := range 12 {
+		items = append(items, types.NewItem(fmt.Sprintf("barrel_%d", i), types.StructureNameWoodenBarrel))
+	}
+	result, err := NewPlacementOptimizer(base).OptimizePlacement(items, testConfig())
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+
+	po := NewPlacementOptimizer(result.Base)
+	config := po.configure(testConfig())
+	want := po.evaluatePlacement(result.Base, items, config).TotalScore
+	// Map iteration order changes between ranges, so repeat enough times
+	// that an order-dependent sum would show up
+	for range 20 {
+		if got := po.evaluatePlacement(result.Base, items, config).TotalScore; math.Float64bits(got) != math.Float64bits(want) {
+			t.Fatalf("TotalScore = %v, then %v", want, got)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// DefaultProductionChains returns the crafting chains of a typical base as
+// supply links: furnaces smelt the ingots the workbenches and the Pal
+// sphere workbench use, and workbench parts feed the assembly line and the
+// civilization workshop. Append links to cover modded stations.
+func DefaultProductionChains() []SupplyLink {
+	return []SupplyLink{
+		{From: types.StructureNameFurnace, To: types.StructureNameWorkbench, Weight: 1.0},
+		{From: types.StructureNameFurnace, To: types.StructureNamePalSphereWorkbench, Weight: 0.5},
+		{From: types.StructureNameWorkbench, To: types.StructureNameProductionAssemblyLineII, Weight: 1.0},
+		{From: types.StructureNameWorkbench, To: types.StructureNameAdvancedCivilizationWorkshop, Weight: 0.5},
+		{From: types.StructureNameFurnace, To: types.StructureNameGoldCoinAssemblyLine, Weight: 0.5},
+	}
+}
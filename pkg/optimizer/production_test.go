@@ -0,0 +1,68 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestProductionChainScoring(t *testing.T) {
+	layout := func(t *testing.T, furnace, workbench, assembly types.Position) *types.Base {
+		base := types.NewBase(16, 2, 4)
+		place(t, base, "furnace", types.StructureNameFurnace, furnace)
+		place(t, base, "workbench", types.StructureNameWorkbench, workbench)
+		place(t, base, "assembly", types.StructureNameProductionAssemblyLineII, assembly)
+		return base
+	}
+	contiguous := layout(t, types.Position{X: 0, Z: 1}, types.Position{X: 2, Z: 1}, types.Position{X: 5, Z: 1})
+	spread := layout(t, types.Position{X: 15, Z: 0}, types.Position{X: 0, Z: 3}, types.Position{X: 7, Z: 1})
+
+	tests := []struct {
+		name   string
+		weight float64
+		chains []SupplyLink
+		scored bool
+	}{
+		{"default chains", 1, DefaultProductionChains(), true},
+		{"zero weight", 0, DefaultProductionChains(), false},
+		{"no chains", 1, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.ProductionChainWeight = tt.weight
+			config.ProductionChains = tt.chains
+
+			score := func(base *types.Base) *PlacementScore {
+				po := NewPlacementOptimizer(base)
+				return po.evaluatePlacement(base, nil, po.configure(config))
+			}
+			near, far := score(contiguous), score(spread)
+
+			if _, ok := near.Details["production_chains"]; ok != tt.scored {
+				t.Errorf("production_chains detail present = %v, want %v", ok, tt.scored)
+			}
+			if !tt.scored {
+				if near.ProductionChainScore != 0 || far.ProductionChainScore != 0 {
+					t.Errorf("ProductionChainScore = %v and %v with the term disabled", near.ProductionChainScore, far.ProductionChainScore)
+				}
+				return
+			}
+			if near.ProductionChainScore <= far.ProductionChainScore {
+				t.Errorf("contiguous chain scored %v, no better than spread out %v", near.ProductionChainScore, far.ProductionChainScore)
+			}
+		})
+	}
+}
+
+func TestDefaultProductionChainsAreDefined(t *testing.T) {
+	for _, link := range DefaultProductionChains() {
+		for _, name := range []types.StructureName{link.From, link.To} {
+			if _, ok := types.StructureDefinitions[name]; !ok {
+				t.Errorf("link %s -> %s: %s has no definition", link.From, link.To, name)
+			}
+		}
+		if link.Weight <= 0 {
+			t.Errorf("link %s -> %s has weight %v", link.From, link.To, link.Weight)
+		}
+	}
+}
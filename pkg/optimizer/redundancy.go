@@ -0,0 +1,40 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// accessRedundancyTarget is the number of open approaches at which an item
+// counts as fully accessible
+const accessRedundancyTarget = 4
+
+// evaluateAccessRedundancy rewards Palboxes, and items of at least
+// minPriority when it is positive, that Pals can approach from several
+// sides rather than through a single dead-end corridor, in [0,1]. Each
+// such item scores the free cells beside its bottom layer (see
+// Graph.AccessDegree) over accessRedundancyTarget, capped at 1, and the
+// result is the mean over those items. A base without any scores 0.
+func (po *PlacementOptimizer) evaluateAccessRedundancy(base *types.Base, minPriority int) float64 {
+	graph := *po.Graph
+	graph.Base = base
+
+	total, count := 0.0, 0
+	for _, item := range base.SortedItems() {
+		isPalbox := hasName(item, types.StructureNamePalbox)
+		if !isPalbox && (minPriority <= 0 || item.Priority < minPriority) {
+			continue
+		}
+
+		degree := 0
+		for _, pos := range item.GetOccupiedPositions() {
+			if pos.Y == item.Position.Y {
+				degree += graph.AccessDegree(pos)
+			}
+		}
+		total += float64(min(degree, accessRedundancyTarget)) / accessRedundancyTarget
+		count++
+	}
+
+	if count == 0 {
+		return 0.0
+	}
+	return total / float64(count)
+}
@@ -0,0 +1,62 @@
+package optimizer
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestEvaluateAccessRedundancy(t *testing.T) {
+	tests := []struct {
+		name        string
+		layout      func(t *testing.T, base *types.Base)
+		minPriority int
+		want        float64
+	}{
+		{
+			"open Palbox",
+			func(t *testing.T, base *types.Base) {
+				place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 2, Z: 2})
+			},
+			0, 1,
+		},
+		{
+			"single-access Palbox",
+			func(t *testing.T, base *types.Base) {
+				place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+				place(t, base, "a", types.StructureNameWoodenBarrel, types.Position{X: 2, Z: 1})
+				place(t, base, "b", types.StructureNameWoodenBarrel, types.Position{X: 0, Z: 2})
+				place(t, base, "c", types.StructureNameWoodenBarrel, types.Position{X: 1, Z: 2})
+			},
+			0, 0.25,
+		},
+		{"no Palbox", func(t *testing.T, base *types.Base) {}, 0, 0},
+		{
+			"high priority items counted",
+			func(t *testing.T, base *types.Base) {
+				place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 2, Z: 2})
+				place(t, base, "bed", types.StructureNamePalBed, types.Position{X: 0, Z: 0})
+			},
+			90, 0.75,
+		},
+		{
+			"low priority items ignored",
+			func(t *testing.T, base *types.Base) {
+				place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 2, Z: 2})
+				place(t, base, "bed", types.StructureNamePalBed, types.Position{X: 0, Z: 0})
+			},
+			91, 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(6, 2, 6)
+			tt.layout(t, base)
+			po := NewPlacementOptimizer(base)
+			po.configure(testConfig())
+			if got := po.evaluateAccessRedundancy(base, tt.minPriority); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("evaluateAccessRedundancy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package optimizer
+
+import (
+	"errors"
+	"fmt"
+	"palbaseiq/pkg/types"
+)
+
+// OptimizePlacementDeterministic runs OptimizePlacement on copies of the
+// items, so that neither their order nor their positions change between
+// calls. Given the same items, the same config and the same starting base,
+// every call produces the same layout, and so the same Base.Fingerprint,
+// which lets tests outside the package pin a tuned config to a known-good
+// layout. The config is required because DefaultConfig seeds from the
+// clock; set its RandomSeed to a fixed value. A config with a MaxDuration
+// is rejected rather than run without its limit, since where a time-limited
+// run stops depends on the machine's speed.
+func (po *PlacementOptimizer) OptimizePlacementDeterministic(items []*types.Item, config *OptimizationConfig) (*PlacementResult, error) {
+	if config == nil {
+		return nil, errors.New("deterministic optimization needs a config with a fixed RandomSeed")
+	}
+	if config.MaxDuration > 0 {
+		return nil, fmt.Errorf("deterministic optimization cannot use a MaxDuration (%s), which depends on wall-clock time", config.MaxDuration)
+	}
+
+	copies := make([]*types.Item, len(items))
+	for i, item := range items {
+		itemCopy := *item
+		copies[i] = &itemCopy
+	}
+
+	return po.OptimizePlacement(copies, config)
+}
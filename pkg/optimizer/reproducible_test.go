@@ -0,0 +1,53 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptimizePlacementDeterministicFingerprint(t *testing.T) {
+	items := testItems()
+	run := func() string {
+		result, err := NewPlacementOptimizer(types.NewBase(12, 6, 12)).OptimizePlacementDeterministic(items, testConfig())
+		if err != nil {
+			t.Fatalf("OptimizePlacementDeterministic: %v", err)
+		}
+		return result.Base.Fingerprint()
+	}
+
+	want := run()
+	for range 3 {
+		if got := run(); got != want {
+			t.Fatalf("Fingerprint() = %s, first run %s", got, want)
+		}
+	}
+	for _, item := range items {
+		if item.Position != (types.Position{}) {
+			t.Errorf("%s moved to %s; the caller's items must be left alone", item.ID, item.Position)
+		}
+	}
+}
+
+func TestOptimizePlacementDeterministicRejects(t *testing.T) {
+	timed := testConfig()
+	timed.MaxDuration = time.Second
+
+	tests := []struct {
+		name    string
+		config  *OptimizationConfig
+		wantErr string
+	}{
+		{"nil config", nil, "fixed RandomSeed"},
+		{"time limit", timed, "MaxDuration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPlacementOptimizer(types.NewBase(12, 6, 12)).OptimizePlacementDeterministic(testItems(), tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// DefaultRetrievalRadius is the distance, in cells, an item retrieval
+// machine reaches when OptimizationConfig.RetrievalRadius is unset
+const DefaultRetrievalRadius = 8.0
+
+// RetrievalCoverageScore returns the fraction of the base's storage
+// structures that at least one item retrieval machine can pull from (see
+// Base.StorageOutOfRange), in [0,1]. A radius of zero or less uses
+// DefaultRetrievalRadius. A base without a machine or without storage
+// scores 0.
+func RetrievalCoverageScore(base *types.Base, radius float64) float64 {
+	if radius <= 0 {
+		radius = DefaultRetrievalRadius
+	}
+
+	machines := base.ItemsByName(types.StructureNameItemRetrievalMachine)
+	storage := 0
+	for _, item := range base.ItemsByCategory(types.StructureCategoryStorage) {
+		if name, err := item.Type.StructureName(); err == nil && name != types.StructureNameItemRetrievalMachine {
+			storage++
+		}
+	}
+	if len(machines) == 0 || storage == 0 {
+		return 0.0
+	}
+
+	// Storage is covered unless every machine leaves it out
+	outOfRange := make(map[string]int)
+	for _, machine := range machines {
+		for _, item := range base.StorageOutOfRange(machine, radius) {
+			outOfRange[item.ID]++
+		}
+	}
+	uncovered := 0
+	for _, misses := range outOfRange {
+		if misses == len(machines) {
+			uncovered++
+		}
+	}
+
+	return 1.0 - float64(uncovered)/float64(storage)
+}
@@ -0,0 +1,63 @@
+package optimizer
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestRetrievalCoverageScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		machines []types.Position
+		crates   []types.Position
+		radius   float64
+		want     float64
+	}{
+		{"no machine", nil, []types.Position{{X: 1}}, 8, 0},
+		{"no storage", []types.Position{{X: 0}}, nil, 8, 0},
+		{"all in range", []types.Position{{X: 0}}, []types.Position{{X: 2}, {X: 4}}, 8, 1},
+		{"half in range", []types.Position{{X: 0}}, []types.Position{{X: 2}, {X: 15}}, 8, 0.5},
+		{"default radius", []types.Position{{X: 0}}, []types.Position{{X: 8}, {X: 9}}, 0, 0.5},
+		{"each covered by a different machine", []types.Position{{X: 0}, {X: 19}}, []types.Position{{X: 2}, {X: 17}}, 4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(20, 2, 2)
+			for i, pos := range tt.machines {
+				place(t, base, string(rune('a'+i)), types.StructureNameItemRetrievalMachine, pos)
+			}
+			for i, pos := range tt.crates {
+				place(t, base, string(rune('m'+i)), types.StructureNameWoodenBarrel, pos)
+			}
+			if got := RetrievalCoverageScore(base, tt.radius); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("RetrievalCoverageScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMovingStorageIntoRangeImprovesScore(t *testing.T) {
+	base := types.NewBase(20, 2, 4)
+	place(t, base, "machine", types.StructureNameItemRetrievalMachine, types.Position{X: 0, Z: 0})
+	place(t, base, "near", types.StructureNameWoodenBarrel, types.Position{X: 2, Z: 0})
+	place(t, base, "crate", types.StructureNameWoodenBarrel, types.Position{X: 18, Z: 3})
+
+	config := testConfig()
+	config.RetrievalWeight = 1
+	po := NewPlacementOptimizer(base)
+	config = po.configure(config)
+	before := po.evaluatePlacement(base, nil, config)
+
+	if err := base.MoveItem("crate", types.Position{X: 3, Z: 2}, 0); err != nil {
+		t.Fatalf("MoveItem: %v", err)
+	}
+	after := po.evaluatePlacement(base, nil, config)
+
+	if after.RetrievalScore <= before.RetrievalScore {
+		t.Errorf("RetrievalScore went from %v to %v moving the crate into range", before.RetrievalScore, after.RetrievalScore)
+	}
+	if after.Details["retrieval"] != 1 {
+		t.Errorf("retrieval detail = %v with every crate in range, want 1", after.Details["retrieval"])
+	}
+}
@@ -0,0 +1,74 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+	"time"
+)
+
+func TestRecordScoreHistory(t *testing.T) {
+	tests := []struct {
+		name       string
+		record     bool
+		iterations int
+		want       int
+	}{
+		{"disabled", false, 100, 0},
+		{"100 iterations", true, 100, 100},
+		{"single iteration", true, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testConfig()
+			config.MaxIterations = tt.iterations
+			config.MinTemperature = 1e-9 // Never stop early for the cold
+			config.RecordScoreHistory = tt.record
+
+			result, err := NewPlacementOptimizer(types.NewBase(12, 6, 12)).OptimizePlacement(testItems(), config)
+			if err != nil {
+				t.Fatalf("OptimizePlacement: %v", err)
+			}
+
+			if !tt.record {
+				if result.CurrentScores != nil || result.BestScores != nil {
+					t.Errorf("history recorded while disabled: %d and %d entries", len(result.CurrentScores), len(result.BestScores))
+				}
+				return
+			}
+			if len(result.CurrentScores) != tt.want || len(result.BestScores) != tt.want {
+				t.Fatalf("got %d current and %d best scores, want %d each", len(result.CurrentScores), len(result.BestScores), tt.want)
+			}
+			for i, best := range result.BestScores {
+				if i > 0 && best < result.BestScores[i-1] {
+					t.Errorf("best score fell from %v to %v at iteration %d", result.BestScores[i-1], best, i)
+				}
+				if best < result.CurrentScores[i] {
+					t.Errorf("iteration %d: best %v below current %v", i, best, result.CurrentScores[i])
+				}
+			}
+			if last := result.BestScores[len(result.BestScores)-1]; last != result.Score.TotalScore {
+				t.Errorf("last best score %v, result scored %v", last, result.Score.TotalScore)
+			}
+		})
+	}
+}
+
+func TestScoreHistoryGrowsWithTheRun(t *testing.T) {
+	// A time limit ends a run with a huge iteration budget almost at once;
+	// the history must not have been sized for the whole budget
+	config := testConfig()
+	config.MaxIterations = 1 << 30
+	config.MaxDuration = time.Nanosecond
+	config.RecordScoreHistory = true
+
+	result, err := NewPlacementOptimizer(types.NewBase(8, 2, 8)).OptimizePlacement(testItems(), config)
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+	if len(result.BestScores) == 0 {
+		t.Fatal("no iterations recorded")
+	}
+	if c := cap(result.BestScores) + cap(result.CurrentScores); c > 1024 {
+		t.Errorf("histories of %d entries have capacity %d", len(result.BestScores), c)
+	}
+}
@@ -0,0 +1,85 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestGreedyPlace(t *testing.T) {
+	base := types.NewBase(8, 3, 8)
+	po := NewPlacementOptimizer(base)
+	po.configure(testConfig())
+
+	items := testItems()
+	placed := po.GreedyPlace(base, items)
+	if len(base.Items) != 0 {
+		t.Errorf("GreedyPlace modified its input base, which now holds %d items", len(base.Items))
+	}
+	checkLayout(t, placed, items)
+
+	// Items that do not fit are left out rather than failing the call
+	tiny := types.NewBase(2, 2, 2)
+	po = NewPlacementOptimizer(tiny)
+	po.configure(testConfig())
+	placed = po.GreedyPlace(tiny, testItems())
+	if _, ok := placed.Items["palbox"]; !ok || len(placed.Items) != 1 {
+		t.Errorf("GreedyPlace into a palbox-sized base placed %v, want only the palbox", placed.SortedItems())
+	}
+}
+
+func TestAnnealExternalStart(t *testing.T) {
+	// A hand-built start, not the output of GreedyPlace
+	base := types.NewBase(8, 3, 8)
+	items := []*types.Item{
+		place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0}),
+		place(t, base, "bed1", types.StructureNamePalBed, types.Position{X: 7, Z: 7}),
+		place(t, base, "bed2", types.StructureNamePalBed, types.Position{X: 7, Z: 0}),
+		place(t, base, "foodbox", types.StructureNameFoodBox, types.Position{X: 0, Z: 7}),
+	}
+	before := make(map[string]types.Position)
+	for _, item := range items {
+		before[item.ID] = item.Position
+	}
+
+	po := NewPlacementOptimizer(base)
+	config := po.configure(testConfig())
+	startScore := po.evaluatePlacement(base, items, config).TotalScore
+
+	result, err := po.Anneal(base, items, config)
+	if err != nil {
+		t.Fatalf("Anneal: %v", err)
+	}
+	checkLayout(t, result.Base, items)
+	if result.Score.TotalScore < startScore {
+		t.Errorf("annealed score %v is below the start's %v", result.Score.TotalScore, startScore)
+	}
+	for id, pos := range before {
+		if got := base.Items[id].Position; got != pos {
+			t.Errorf("Anneal moved %s in its input base from %s to %s", id, pos, got)
+		}
+	}
+}
+
+func BenchmarkGreedyPlace(b *testing.B) {
+	base := types.NewBase(20, 16, 20)
+	po := NewPlacementOptimizer(base)
+	po.configure(testConfig())
+	b.ReportAllocs()
+	for b.Loop() {
+		po.GreedyPlace(base, testItems())
+	}
+}
+
+func BenchmarkAnneal(b *testing.B) {
+	base := types.NewBase(20, 16, 20)
+	po := NewPlacementOptimizer(base)
+	config := po.configure(testConfig())
+	items := testItems()
+	start := po.GreedyPlace(base, items)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := po.Anneal(start, items, config); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
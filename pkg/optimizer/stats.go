@@ -0,0 +1,88 @@
+package optimizer
+
+import (
+	"math"
+	"math/big"
+	"palbaseiq/pkg/types"
+)
+
+// ScoreStats summarizes final scores over several optimization runs
+type ScoreStats struct {
+	Runs   int
+	Mean   float64
+	Min    float64
+	Max    float64
+	StdDev float64 // population standard deviation
+	Scores []float64
+}
+
+// ScoreDistribution runs OptimizePlacement runs times, seeding run i with
+// config.RandomSeed+i, and summarizes the final total scores. Each run works
+// on its own copies of the items so the runs do not influence each other.
+func (po *PlacementOptimizer) ScoreDistribution(items []*types.Item, config *OptimizationConfig, runs int) (ScoreStats, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	stats := ScoreStats{Runs: runs}
+	if runs <= 0 {
+		return stats, nil
+	}
+
+	stats.Scores = make([]float64, 0, runs)
+	for run := 0; run < runs; run++ {
+		runConfig := *config
+		runConfig.RandomSeed = config.RandomSeed + int64(run)
+
+		runItems := make([]*types.Item, len(items))
+		for i, item := range items {
+			runItem := *item
+			runItems[i] = &runItem
+		}
+
+		result, err := po.OptimizePlacement(runItems, &runConfig)
+		if err != nil {
+			return stats, err
+		}
+		stats.Scores = append(stats.Scores, result.Score.TotalScore)
+	}
+
+	stats.Min, stats.Max = math.Inf(1), math.Inf(-1)
+	sum := 0.0
+	for _, score := range stats.Scores {
+		sum += score
+		stats.Min = math.Min(stats.Min, score)
+		stats.Max = math.Max(stats.Max, score)
+	}
+	stats.Mean = sum / float64(runs)
+
+	variance := 0.0
+	for _, score := range stats.Scores {
+		variance += (score - stats.Mean) * (score - stats.Mean)
+	}
+	stats.StdDev = math.Sqrt(variance / float64(runs))
+
+	return stats, nil
+}
+
+// EstimateSearchSpace approximates the number of distinct placements the
+// annealer could explore as C(free, n): the free cells of the base choose
+// the n movable items. Footprints larger than one cell and placement rules
+// shrink the real count, so this is an upper estimate for comparing
+// against MaxIterations. The result is 1 when nothing can move and 0 when
+// there are more items than free cells.
+func (po *PlacementOptimizer) EstimateSearchSpace(items []*types.Item) *big.Int {
+	movable := 0
+	for _, item := range items {
+		if !item.IsFixed() {
+			movable++
+		}
+	}
+
+	free := 0
+	po.Base.EachFreePosition(func(types.Position) bool {
+		free++
+		return true
+	})
+	return new(big.Int).Binomial(int64(free), int64(movable))
+}
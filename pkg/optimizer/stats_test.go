@@ -0,0 +1,108 @@
+package optimizer
+
+import (
+	"math"
+	"math/big"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestScoreDistribution(t *testing.T) {
+	config := testConfig()
+	config.RandomSeed = 7
+	stats, err := NewPlacementOptimizer(types.NewBase(8, 3, 8)).ScoreDistribution(testItems(), config, 3)
+	if err != nil {
+		t.Fatalf("ScoreDistribution: %v", err)
+	}
+
+	// Each run must match a standalone run with its seed
+	want := make([]float64, 3)
+	for i := range want {
+		runConfig := *config
+		runConfig.RandomSeed = config.RandomSeed + int64(i)
+		result, err := NewPlacementOptimizer(types.NewBase(8, 3, 8)).OptimizePlacement(testItems(), &runConfig)
+		if err != nil {
+			t.Fatalf("OptimizePlacement: %v", err)
+		}
+		want[i] = result.Score.TotalScore
+	}
+
+	if stats.Runs != 3 || len(stats.Scores) != 3 {
+		t.Fatalf("got %d runs with %d scores, want 3", stats.Runs, len(stats.Scores))
+	}
+	mean := (want[0] + want[1] + want[2]) / 3
+	variance := 0.0
+	for i, score := range want {
+		if stats.Scores[i] != score {
+			t.Errorf("run %d scored %v, a standalone run %v", i, stats.Scores[i], score)
+		}
+		variance += (score - mean) * (score - mean) / 3
+	}
+
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Mean", stats.Mean, mean},
+		{"Min", stats.Min, math.Min(want[0], math.Min(want[1], want[2]))},
+		{"Max", stats.Max, math.Max(want[0], math.Max(want[1], want[2]))},
+		{"StdDev", stats.StdDev, math.Sqrt(variance)},
+	}
+	for _, check := range checks {
+		if math.Abs(check.got-check.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", check.name, check.got, check.want)
+		}
+	}
+}
+
+func TestScoreDistributionWithoutRuns(t *testing.T) {
+	stats, err := NewPlacementOptimizer(types.NewBase(8, 3, 8)).ScoreDistribution(testItems(), testConfig(), 0)
+	if err != nil {
+		t.Fatalf("ScoreDistribution: %v", err)
+	}
+	if stats.Runs != 0 || stats.Scores != nil {
+		t.Errorf("got %+v, want no runs", stats)
+	}
+}
+
+func TestEstimateSearchSpace(t *testing.T) {
+	barrels := func(n int) []*types.Item {
+		items := make([]*types.Item, n)
+		for i := range items {
+			items[i] = types.NewItem(string(rune('a'+i)), types.StructureNameWoodenBarrel)
+		}
+		return items
+	}
+	packed := types.NewBase(2, 1, 1)
+	for x := 0; x < 2; x++ {
+		place(t, packed, string(rune('p'+x)), types.StructureNameWoodenBarrel, types.Position{X: x})
+	}
+
+	tests := []struct {
+		name  string
+		base  *types.Base
+		items []*types.Item
+		want  int64
+	}{
+		{"no items", types.NewBase(4, 1, 4), nil, 1},
+		{"one item", types.NewBase(4, 1, 4), barrels(1), 16},
+		{"two items", types.NewBase(4, 1, 4), barrels(2), 120},
+		{"more free space", types.NewBase(5, 1, 4), barrels(2), 190},
+		{"fully packed", packed, barrels(2), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPlacementOptimizer(tt.base).EstimateSearchSpace(tt.items)
+			if got.Cmp(big.NewInt(tt.want)) != 0 {
+				t.Errorf("EstimateSearchSpace() = %v, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Environmental items are already in the world and add nothing to the search
+	environmental := barrels(2)
+	environmental[1].Environmental = true
+	if got := NewPlacementOptimizer(types.NewBase(4, 1, 4)).EstimateSearchSpace(environmental); got.Cmp(big.NewInt(16)) != 0 {
+		t.Errorf("EstimateSearchSpace() with an environmental item = %v, want 16", got)
+	}
+}
@@ -0,0 +1,82 @@
+package optimizer
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+)
+
+// SupplyLink is a directed hop in a supply chain: structures named From
+// deliver to the nearest structure named To. Weight sets how much a short
+// walk along this link matters relative to the other links.
+type SupplyLink struct {
+	From, To types.StructureName
+	Weight   float64
+}
+
+// DefaultSupplyChains returns the food chain of a typical base: plots and
+// plantations feed the food boxes, the boxes feed the kitchens, and the
+// prepared food is eaten by Pals resting at their beds
+func DefaultSupplyChains() []SupplyLink {
+	return []SupplyLink{
+		{From: types.StructureNameFoodPlot, To: types.StructureNameFoodBox, Weight: 1.0},
+		{From: types.StructureNameFoodPlot, To: types.StructureNameColdFoodBox, Weight: 1.0},
+		{From: types.StructureNameBerryPlantation, To: types.StructureNameFoodBox, Weight: 1.0},
+		{From: types.StructureNameBerryPlantation, To: types.StructureNameColdFoodBox, Weight: 1.0},
+		{From: types.StructureNameFoodBox, To: types.StructureNameCookingPot, Weight: 1.0},
+		{From: types.StructureNameFoodBox, To: types.StructureNameElectricKitchen, Weight: 1.0},
+		{From: types.StructureNameColdFoodBox, To: types.StructureNameCookingPot, Weight: 1.0},
+		{From: types.StructureNameColdFoodBox, To: types.StructureNameElectricKitchen, Weight: 1.0},
+		{From: types.StructureNameCookingPot, To: types.StructureNamePalBed, Weight: 0.5},
+		{From: types.StructureNameElectricKitchen, To: types.StructureNamePalBed, Weight: 0.5},
+	}
+}
+
+// evaluateSupplyChains rewards short walks along the configured supply
+// links, in [0,1]. Every placed From structure with at least one To
+// structure in the base contributes weight/(1+cost), where cost is the
+// cheapest path from a free cell beside it to a free cell beside the
+// nearest To structure, or nothing if none can be reached. The result is
+// the weighted mean over those contributions.
+func (po *PlacementOptimizer) evaluateSupplyChains(base *types.Base, links []SupplyLink) float64 {
+	byName := make(map[types.StructureName][]*types.Item)
+	for _, item := range base.SortedItems() {
+		if name, err := item.Type.StructureName(); err == nil {
+			byName[name] = append(byName[name], item)
+		}
+	}
+
+	// Search the evaluated layout with the optimizer's cost settings
+	graph := *po.Graph
+	graph.Base = base
+
+	total, weights := 0.0, 0.0
+	for _, link := range links {
+		consumers := byName[link.To]
+		if len(consumers) == 0 || link.Weight <= 0 {
+			continue
+		}
+
+		var targets []types.Position
+		for _, consumer := range consumers {
+			targets = append(targets, base.AdjacentFreePositions(consumer)...)
+		}
+
+		for _, supplier := range byName[link.From] {
+			weights += link.Weight
+
+			// Walk from whichever face of the supplier is open
+			cost := math.Inf(1)
+			for _, path := range graph.ShortestPathsFromAny(base.AdjacentFreePositions(supplier), targets) {
+				cost = math.Min(cost, path.Cost)
+			}
+			if !math.IsInf(cost, 1) {
+				total += link.Weight / (1.0 + cost)
+			}
+		}
+	}
+
+	if weights == 0 {
+		return 0.0
+	}
+	return total / weights
+}
@@ -0,0 +1,49 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestEvaluateSupplyChains(t *testing.T) {
+	chain := []SupplyLink{
+		{From: types.StructureNameFoodPlot, To: types.StructureNameFoodBox, Weight: 1},
+		{From: types.StructureNameFoodBox, To: types.StructureNameCookingPot, Weight: 1},
+	}
+	layout := func(t *testing.T, plot, box, pot int) *types.Base {
+		base := types.NewBase(12, 1, 3)
+		place(t, base, "plot", types.StructureNameFoodPlot, types.Position{X: plot, Z: 1})
+		place(t, base, "box", types.StructureNameFoodBox, types.Position{X: box, Z: 1})
+		place(t, base, "pot", types.StructureNameCookingPot, types.Position{X: pot, Z: 1})
+		return base
+	}
+
+	// Both layouts use the same three cells
+	sequence := layout(t, 0, 2, 4)
+	scrambled := layout(t, 4, 0, 2)
+
+	po := NewPlacementOptimizer(sequence)
+	inOrder, outOfOrder := po.evaluateSupplyChains(sequence, chain), po.evaluateSupplyChains(scrambled, chain)
+	if inOrder <= outOfOrder {
+		t.Errorf("plot->box->pot in sequence scored %v, no better than out of sequence %v", inOrder, outOfOrder)
+	}
+
+	tests := []struct {
+		name  string
+		links []SupplyLink
+		want  float64
+	}{
+		{"no links", nil, 0},
+		{"no consumer placed", []SupplyLink{{From: types.StructureNameFoodPlot, To: types.StructureNamePalBed, Weight: 1}}, 0},
+		{"zero weight ignored", []SupplyLink{{From: types.StructureNameFoodPlot, To: types.StructureNameFoodBox, Weight: 0}}, 0},
+		// One free cell lies beside both items, so the walk costs nothing
+		{"single adjacent link", []SupplyLink{{From: types.StructureNameFoodBox, To: types.StructureNameCookingPot, Weight: 1}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := po.evaluateSupplyChains(sequence, tt.links); got != tt.want {
+				t.Errorf("evaluateSupplyChains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
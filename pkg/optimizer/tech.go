@@ -0,0 +1,39 @@
+package optimizer
+
+import "palbaseiq/pkg/types"
+
+// gateTechLevel keeps the items a player at maxTech can build. Items above
+// that level are replaced by their highest unlocked fallback (see
+// types.SubstituteForTech), which keeps the ID and priority but takes the
+// fallback's footprint, or dropped when there is none. Items of unknown
+// type are kept. A maxTech of zero or less keeps everything. The returned
+// items keep the input order, with substitutes in place of the originals.
+func gateTechLevel(items []*types.Item, maxTech int) (kept, substituted, dropped []*types.Item) {
+	if maxTech <= 0 {
+		return items, nil, nil
+	}
+
+	for _, item := range items {
+		name, err := item.Type.StructureName()
+		if err != nil {
+			kept = append(kept, item)
+			continue
+		}
+
+		substitute, ok := types.SubstituteForTech(name, maxTech)
+		switch {
+		case !ok:
+			dropped = append(dropped, item)
+		case substitute == name:
+			kept = append(kept, item)
+		default:
+			replacement := *item
+			replacement.Type = types.ItemType(substitute)
+			replacement.Bounds = types.StructureDefinitions[substitute].DefaultBounds
+			kept = append(kept, &replacement)
+			substituted = append(substituted, &replacement)
+		}
+	}
+
+	return kept, substituted, dropped
+}
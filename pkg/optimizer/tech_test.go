@@ -0,0 +1,79 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestGateTechLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		structure   types.StructureName
+		maxTech     int
+		want        types.StructureName
+		substituted bool
+		dropped     bool
+	}{
+		{"unlimited", types.StructureNameElectricKitchen, 0, types.StructureNameElectricKitchen, false, false},
+		{"unlocked", types.StructureNameElectricKitchen, 40, types.StructureNameElectricKitchen, false, false},
+		{"substituted", types.StructureNameElectricKitchen, 5, types.StructureNameCampfire, true, false},
+		{"campfire stays", types.StructureNameCampfire, 5, types.StructureNameCampfire, false, false},
+		{"negative is unlimited", types.StructureNameElectricKitchen, -1, types.StructureNameElectricKitchen, false, false},
+		{"dropped", types.StructureNameMetalDefensiveWall, 5, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := types.NewItem("kitchen", tt.structure)
+			kept, substituted, dropped := gateTechLevel([]*types.Item{item}, tt.maxTech)
+			if tt.dropped {
+				if len(kept) != 0 || len(dropped) != 1 {
+					t.Errorf("kept %d, dropped %d, want 0 and 1", len(kept), len(dropped))
+				}
+				return
+			}
+			if len(kept) != 1 || len(dropped) != 0 {
+				t.Fatalf("kept %d, dropped %d, want 1 and 0", len(kept), len(dropped))
+			}
+			if kept[0].Type != types.ItemType(tt.want) || kept[0].ID != "kitchen" {
+				t.Errorf("kept %s of type %s, want kitchen of type %s", kept[0].ID, kept[0].Type, tt.want)
+			}
+			if (len(substituted) == 1) != tt.substituted {
+				t.Errorf("substituted = %v, want %v", substituted, tt.substituted)
+			}
+		})
+	}
+}
+
+func TestOptimizePlacementLowTech(t *testing.T) {
+	items := []*types.Item{
+		types.NewItem("palbox", types.StructureNamePalbox),
+		types.NewItem("kitchen", types.StructureNameElectricKitchen),
+		types.NewItem("campfire", types.StructureNameCampfire),
+		types.NewItem("defence", types.StructureNameMetalDefensiveWall),
+	}
+	config := testConfig()
+	config.MaxTechLevel = 5
+
+	result, err := NewPlacementOptimizer(types.NewBase(12, 6, 12)).OptimizePlacement(items, config)
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+
+	for _, item := range result.Base.Items {
+		if item.Type == types.ItemType(types.StructureNameElectricKitchen) {
+			t.Errorf("electric kitchen %s placed at tech level 5", item.ID)
+		}
+	}
+	if campfire := result.Base.Items["campfire"]; campfire == nil || campfire.Type != types.ItemType(types.StructureNameCampfire) {
+		t.Errorf("campfire missing at tech level 5: %v", campfire)
+	}
+	if kitchen := result.Base.Items["kitchen"]; kitchen == nil || kitchen.Type != types.ItemType(types.StructureNameCampfire) {
+		t.Errorf("kitchen not substituted by a campfire: %v", kitchen)
+	}
+	if len(result.Dropped) != 1 || result.Dropped[0].ID != "defence" {
+		t.Errorf("Dropped = %v, want the metal wall, which has no unlocked fallback", result.Dropped)
+	}
+	if len(result.Substituted) != 1 || result.Substituted[0].ID != "kitchen" {
+		t.Errorf("Substituted = %v, want the kitchen", result.Substituted)
+	}
+}
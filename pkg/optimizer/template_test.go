@@ -0,0 +1,91 @@
+package optimizer
+
+import (
+	"fmt"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestOptimizePlacementWithTemplate(t *testing.T) {
+	wall := types.NewItem("template_wall", types.StructureNameOuterWall)
+	wall.Position = types.Position{X: 5, Z: 5}
+	slot := types.Slot{
+		Name:  "fields",
+		Min:   types.Position{X: 0, Z: 0},
+		Max:   types.Position{X: 3, Y: 2, Z: 3},
+		Types: []types.ItemType{types.ItemType(types.StructureNameFoodPlot)},
+	}
+	template := &types.Template{Name: "farm", Fixed: []*types.Item{wall}, Slots: []types.Slot{slot}}
+
+	for seed := int64(1); seed <= 3; seed++ {
+		items := testItems()
+		for i := 0; i < 4; i++ {
+			items = append(items, types.NewItem(string(rune('a'+i)), types.StructureNameFoodPlot))
+		}
+		config := testConfig()
+		config.RandomSeed = seed
+		config.Template = template
+
+		result, err := NewPlacementOptimizer(types.NewBase(10, 3, 10)).OptimizePlacement(items, config)
+		if err != nil {
+			t.Fatalf("seed %d: OptimizePlacement: %v", seed, err)
+		}
+
+		if placed, ok := result.Base.Items[wall.ID]; !ok || placed.Position != wall.Position {
+			t.Errorf("seed %d: the template wall moved or vanished", seed)
+		}
+		for _, item := range result.Base.Items {
+			if slot.Accepts(item.Type) && !slot.Contains(item) {
+				t.Errorf("seed %d: %s at %s lies outside its slot", seed, item.ID, item.Position)
+			}
+		}
+	}
+}
+
+func TestReoptimizeAfterRemoveByCategory(t *testing.T) {
+	anchor := types.NewItem("template_barrel", types.StructureNameWoodenBarrel)
+	anchor.Position = types.Position{X: 3, Z: 3}
+	config := testConfig()
+	config.Template = &types.Template{Name: "corner", Fixed: []*types.Item{anchor}}
+
+	var items []*types.Item
+	for i := 0; i < 8; i++ {
+		items = append(items, types.NewItem(fmt.Sprintf("decor%d", i), types.StructureNameRedMetalBarrel))
+	}
+	for i := 0; i < 4; i++ {
+		items = append(items, types.NewItem(fmt.Sprintf("bed%d", i), types.StructureNamePalBed))
+	}
+
+	po := NewPlacementOptimizer(types.NewBase(4, 1, 4))
+	result, err := po.OptimizePlacement(items, config)
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+	layout := result.Base
+	if free := len(layout.GetFreePositions()); free != 3 {
+		t.Fatalf("optimized layout leaves %d free cells, want 3", free)
+	}
+
+	if removed := layout.RemoveByCategory(types.StructureCategoryFurniture); removed != 8 {
+		t.Fatalf("RemoveByCategory() = %d, want 8", removed)
+	}
+
+	// Re-optimize what is left in place; the template item must stay put
+	resumed, err := po.Anneal(layout, nil, config)
+	if err != nil {
+		t.Fatalf("Anneal: %v", err)
+	}
+	if got := resumed.Base.Items[anchor.ID]; got == nil || got.Position != anchor.Position {
+		t.Errorf("the template item moved or vanished while re-optimizing")
+	}
+	if len(resumed.Base.Items) != 5 || len(resumed.Unplaced) != 0 {
+		t.Errorf("re-optimized layout holds %d items with %d unplaced, want 5 and 0", len(resumed.Base.Items), len(resumed.Unplaced))
+	}
+
+	// The freed cells take new items that could not have fit before
+	var beds []*types.Item
+	for i := 0; i < 8; i++ {
+		beds = append(beds, types.NewItem(fmt.Sprintf("new_bed%d", i), types.StructureNamePalBed))
+	}
+	checkLayout(t, po.GreedyPlace(resumed.Base, beds), beds)
+}
@@ -0,0 +1,74 @@
+package optimizer
+
+import (
+	"palbaseiq/pkg/types"
+	"sort"
+)
+
+// ItemMove records how one item changed between two layouts. Placed and
+// WasPlaced tell apart items that entered or left the base from items that
+// only moved.
+type ItemMove struct {
+	ItemID       string
+	From, To     types.Position
+	FromRotation int
+	ToRotation   int
+	WasPlaced    bool
+	Placed       bool
+}
+
+// TraceStep captures an accepted annealing move
+type TraceStep struct {
+	Iteration   int
+	Temperature float64
+	Score       float64
+	Moves       []ItemMove
+}
+
+// Trace records how the annealer arrived at its result. Initial holds the
+// greedy starting layout and each step stores only the items it changed,
+// so replaying Steps over Initial reproduces every accepted layout.
+type Trace struct {
+	Initial []ItemMove
+	Steps   []TraceStep
+}
+
+// diffBases lists the items whose placement differs between two layouts,
+// ordered by item ID. A nil previous base treats every item as new.
+func diffBases(previous, next *types.Base) []ItemMove {
+	ids := make(map[string]bool)
+	if previous != nil {
+		for id := range previous.Items {
+			ids[id] = true
+		}
+	}
+	for id := range next.Items {
+		ids[id] = true
+	}
+
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	var moves []ItemMove
+	for _, id := range sorted {
+		move := ItemMove{ItemID: id}
+		if previous != nil {
+			if before, ok := previous.Items[id]; ok {
+				move.From, move.FromRotation, move.WasPlaced = before.Position, before.Rotation, true
+			}
+		}
+		if after, ok := next.Items[id]; ok {
+			move.To, move.ToRotation, move.Placed = after.Position, after.Rotation, true
+		}
+
+		if move.WasPlaced == move.Placed && move.From == move.To && move.FromRotation == move.ToRotation {
+			continue
+		}
+		moves = append(moves, move)
+	}
+
+	return moves
+}
@@ -0,0 +1,154 @@
+package optimizer
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+// replay applies moves to a layout held as item ID to placed copy,
+// failing the test when a move does not start where the layout has the item
+func replay(t *testing.T, layout map[string]*types.Item, byID map[string]*types.Item, moves []ItemMove) {
+	t.Helper()
+	for _, move := range moves {
+		before, placed := layout[move.ItemID]
+		if placed != move.WasPlaced || (placed && (before.Position != move.From || before.Rotation != move.FromRotation)) {
+			t.Fatalf("move %+v does not start from the replayed layout", move)
+		}
+		if !move.Placed {
+			delete(layout, move.ItemID)
+			continue
+		}
+		item := *byID[move.ItemID]
+		item.Position, item.Rotation = move.To, move.ToRotation
+		layout[move.ItemID] = &item
+	}
+}
+
+// layoutBase builds a base of the given size holding the layout's items
+func layoutBase(t *testing.T, size *types.Base, layout map[string]*types.Item) *types.Base {
+	t.Helper()
+	base := types.NewBase(size.Width, size.Height, size.Depth)
+	for _, item := range layout {
+		copied := *item
+		if err := base.PlaceItem(&copied); err != nil {
+			t.Fatalf("replayed layout does not fit: %v", err)
+		}
+	}
+	return base
+}
+
+func TestTraceReplaysAcceptedLayouts(t *testing.T) {
+	items := testItems()
+	byID := make(map[string]*types.Item)
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	config := testConfig()
+	config.MaxIterations = 200
+	config.RecordTrace = true
+	po := NewPlacementOptimizer(types.NewBase(8, 2, 8))
+	result, err := po.OptimizePlacement(items, config)
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+	if result.Trace == nil {
+		t.Fatal("no trace recorded")
+	}
+	if len(result.Trace.Steps) == 0 {
+		t.Fatal("no accepted moves to replay")
+	}
+
+	layout := make(map[string]*types.Item)
+	replay(t, layout, byID, result.Trace.Initial)
+	if len(layout) != len(items) {
+		t.Fatalf("initial layout holds %d items, want %d", len(layout), len(items))
+	}
+
+	// Every accepted layout scores as recorded, and the best one, starting
+	// from the initial layout, is the result
+	best := make(map[string]*types.Item, len(layout))
+	for id, item := range layout {
+		best[id] = item
+	}
+	bestScore := po.evaluatePlacement(layoutBase(t, result.Base, layout), items, po.Config).TotalScore
+	previous := -1
+	for _, step := range result.Trace.Steps {
+		if step.Iteration <= previous {
+			t.Errorf("step at iteration %d follows iteration %d", step.Iteration, previous)
+		}
+		previous = step.Iteration
+
+		replay(t, layout, byID, step.Moves)
+		replayed := layoutBase(t, result.Base, layout)
+		score := po.evaluatePlacement(replayed, items, po.Config)
+		if math.Abs(score.TotalScore-step.Score) > 1e-9 {
+			t.Errorf("replayed layout at iteration %d scores %v, recorded %v", step.Iteration, score.TotalScore, step.Score)
+		}
+		if step.Score > bestScore {
+			bestScore = step.Score
+			best = make(map[string]*types.Item, len(layout))
+			for id, item := range layout {
+				best[id] = item
+			}
+		}
+	}
+
+	if math.Abs(bestScore-result.Score.TotalScore) > 1e-9 {
+		t.Errorf("best replayed layout scores %v, the result %v", bestScore, result.Score.TotalScore)
+	}
+	for id, item := range result.Base.Items {
+		if got := best[id]; got == nil || got.Position != item.Position || got.Rotation != item.Rotation {
+			t.Errorf("%s is at %s in the result but %v in the best replayed layout", id, item.Position, got)
+		}
+	}
+}
+
+func TestTraceDisabled(t *testing.T) {
+	result, err := NewPlacementOptimizer(types.NewBase(8, 2, 8)).OptimizePlacement(testItems(), testConfig())
+	if err != nil {
+		t.Fatalf("OptimizePlacement: %v", err)
+	}
+	if result.Trace != nil {
+		t.Errorf("Trace = %+v without RecordTrace", result.Trace)
+	}
+}
+
+func TestDiffBases(t *testing.T) {
+	previous := types.NewBase(6, 1, 6)
+	place(t, previous, "stays", types.StructureNameWoodenBarrel, types.Position{X: 0})
+	place(t, previous, "moves", types.StructureNameWoodenBarrel, types.Position{X: 2})
+	place(t, previous, "turns", types.StructureNameWorkbench, types.Position{X: 0, Z: 3})
+	place(t, previous, "leaves", types.StructureNameWoodenBarrel, types.Position{X: 5, Z: 5})
+
+	next := types.NewBase(6, 1, 6)
+	place(t, next, "stays", types.StructureNameWoodenBarrel, types.Position{X: 0})
+	place(t, next, "moves", types.StructureNameWoodenBarrel, types.Position{X: 3})
+	turned := types.NewItem("turns", types.StructureNameWorkbench)
+	turned.Position, turned.Rotation = types.Position{X: 0, Z: 3}, 90
+	if err := next.PlaceItem(turned); err != nil {
+		t.Fatalf("placing turns: %v", err)
+	}
+	place(t, next, "arrives", types.StructureNameWoodenBarrel, types.Position{X: 4, Z: 4})
+
+	want := []ItemMove{
+		{ItemID: "arrives", To: types.Position{X: 4, Z: 4}, Placed: true},
+		{ItemID: "leaves", From: types.Position{X: 5, Z: 5}, WasPlaced: true},
+		{ItemID: "moves", From: types.Position{X: 2}, To: types.Position{X: 3}, WasPlaced: true, Placed: true},
+		{ItemID: "turns", From: types.Position{X: 0, Z: 3}, To: types.Position{X: 0, Z: 3}, ToRotation: 90, WasPlaced: true, Placed: true},
+	}
+	got := diffBases(previous, next)
+	if len(got) != len(want) {
+		t.Fatalf("diffBases() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("move %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if initial := diffBases(nil, next); len(initial) != len(next.Items) {
+		t.Errorf("diffBases(nil) lists %d moves, want one per item (%d)", len(initial), len(next.Items))
+	}
+}
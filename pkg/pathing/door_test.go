@@ -0,0 +1,68 @@
+package pathing
+
+import (
+	"errors"
+	"fmt"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestPathThroughDoor(t *testing.T) {
+	gate := types.Position{X: 2, Z: 2}
+
+	tests := []struct {
+		name     string
+		gate     types.StructureName
+		passable bool
+		wantErr  error
+	}{
+		{"glass door", types.StructureNameGlassWallAndDoor, false, nil},
+		{"solid wall", types.StructureNameStoneDefensiveWall, false, ErrNoPath},
+		{"wall marked passable", types.StructureNameStoneDefensiveWall, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A wall across the base with one gate cell in the middle
+			base := types.NewBase(5, 2, 5)
+			for z := 0; z < 5; z++ {
+				pos := types.Position{X: 2, Z: z}
+				if pos == gate {
+					item := types.NewItem("gate", tt.gate)
+					item.Position = pos
+					item.Passable = tt.passable
+					if err := base.PlaceItem(item); err != nil {
+						t.Fatalf("placing the gate: %v", err)
+					}
+					continue
+				}
+				place(t, base, fmt.Sprintf("wall_%d", z), types.StructureNameStoneDefensiveWall, pos)
+			}
+
+			// The gate still blocks building
+			barrel := types.NewItem("barrel", types.StructureNameWoodenBarrel)
+			barrel.Position = gate
+			if base.CanPlaceItem(barrel) {
+				t.Error("an item can be placed on the gate cell")
+			}
+
+			path, err := NewGraph(base).FindPath(types.Position{X: 0, Z: 2}, types.Position{X: 4, Z: 2})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("FindPath() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindPath: %v", err)
+			}
+			crossed := false
+			for _, pos := range path.Nodes {
+				crossed = crossed || pos == gate
+			}
+			if !crossed {
+				t.Errorf("path %v does not pass through the gate at %s", path.Nodes, gate)
+			}
+		})
+	}
+}
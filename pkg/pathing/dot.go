@@ -0,0 +1,57 @@
+package pathing
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"palbaseiq/pkg/types"
+)
+
+// ToDOT writes the graph in Graphviz DOT format: a node for every walkable
+// cell (see Base.IsTraversable) and a directed edge for every move between
+// neighboring ones, labelled with CalculateEdgeCost. Moves are listed in
+// both directions because slopes and cell weights can make their costs
+// differ. Render it with, for example, "dot -Tsvg". Only small bases make
+// readable pictures; see ToDOTLevel for larger ones.
+func (g *Graph) ToDOT(w io.Writer) error {
+	return g.writeDOT(w, func(types.Position) bool { return true })
+}
+
+// ToDOTLevel is ToDOT limited to the cells of level y, leaving out moves
+// up or down
+func (g *Graph) ToDOTLevel(w io.Writer, y int) error {
+	return g.writeDOT(w, func(pos types.Position) bool { return pos.Y == y })
+}
+
+// writeDOT writes the cells include selects and the moves between them
+func (g *Graph) writeDOT(w io.Writer, include func(types.Position) bool) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph base {")
+
+	var cells []types.Position
+	for x := 0; x < g.Base.Width; x++ {
+		for y := 0; y < g.Base.Height; y++ {
+			for z := 0; z < g.Base.Depth; z++ {
+				pos := types.Position{X: x, Y: y, Z: z}
+				if include(pos) && g.Base.IsTraversable(pos) {
+					cells = append(cells, pos)
+					fmt.Fprintf(bw, "  %q;\n", GetNodeKey(pos))
+				}
+			}
+		}
+	}
+
+	var neighbors []types.Position
+	for _, pos := range cells {
+		neighbors = g.appendNeighbors(neighbors[:0], pos)
+		for _, neighbor := range neighbors {
+			if include(neighbor) {
+				fmt.Fprintf(bw, "  %q -> %q [label=\"%.2f\"];\n",
+					GetNodeKey(pos), GetNodeKey(neighbor), g.CalculateEdgeCost(pos, neighbor))
+			}
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
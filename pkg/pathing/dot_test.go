@@ -0,0 +1,76 @@
+package pathing
+
+import (
+	"bytes"
+	"palbaseiq/pkg/types"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	dotNode = regexp.MustCompile(`^  "[^"]+";$`)
+	dotEdge = regexp.MustCompile(`^  "[^"]+" -> "[^"]+" \[label="\d+\.\d\d"\];$`)
+)
+
+// countDOT checks that out is a digraph of node and edge lines and counts
+// them
+func countDOT(t *testing.T, out string) (nodes, edges int) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) < 2 || lines[0] != "digraph base {" || lines[len(lines)-1] != "}" {
+		t.Fatalf("output is not a digraph:\n%s", out)
+	}
+	for _, line := range lines[1 : len(lines)-1] {
+		switch {
+		case dotNode.MatchString(line):
+			nodes++
+		case dotEdge.MatchString(line):
+			edges++
+		default:
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+	return nodes, edges
+}
+
+func TestToDOT(t *testing.T) {
+	tests := []struct {
+		name                 string
+		width, height, depth int
+		blocked              []types.Position
+		level                int
+		nodes, edges         int
+	}{
+		{"2x1x2", 2, 1, 2, nil, -1, 4, 8},
+		{"2x1x2 with a barrel", 2, 1, 2, []types.Position{{X: 1, Z: 1}}, -1, 3, 4},
+		{"2x2x2", 2, 2, 2, nil, -1, 8, 24},
+		{"2x2x2 lower level", 2, 2, 2, nil, 0, 4, 8},
+		{"2x2x2 upper level over a barrel", 2, 2, 2, []types.Position{{X: 0, Z: 0}}, 1, 4, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(tt.width, tt.height, tt.depth)
+			for i, pos := range tt.blocked {
+				place(t, base, string(rune('a'+i)), types.StructureNameWoodenBarrel, pos)
+			}
+			graph := NewGraph(base)
+
+			var buf bytes.Buffer
+			var err error
+			if tt.level < 0 {
+				err = graph.ToDOT(&buf)
+			} else {
+				err = graph.ToDOTLevel(&buf, tt.level)
+			}
+			if err != nil {
+				t.Fatalf("writing DOT: %v", err)
+			}
+
+			nodes, edges := countDOT(t, buf.String())
+			if nodes != tt.nodes || edges != tt.edges {
+				t.Errorf("got %d nodes and %d edges, want %d and %d", nodes, edges, tt.nodes, tt.edges)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package pathing
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestElevationEdgeCost(t *testing.T) {
+	elevation := ElevationMap(map[[2]int]int{{1, 0}: 2, {2, 0}: 2})
+
+	tests := []struct {
+		name     string
+		from, to types.Position
+		extra    float64
+	}{
+		{"flat", types.Position{X: 1}, types.Position{X: 2}, 0},
+		{"uphill", types.Position{X: 0}, types.Position{X: 1}, 2 * ClimbCostPerUnit},
+		{"downhill", types.Position{X: 2}, types.Position{X: 3}, 2 * DescentCostPerUnit},
+	}
+
+	base := types.NewBase(4, 1, 1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewGraph(base)
+			flat := graph.CalculateEdgeCost(tt.from, tt.to)
+			graph.Elevation = elevation
+			if got := graph.CalculateEdgeCost(tt.from, tt.to) - flat; got != tt.extra {
+				t.Errorf("elevation adds %v to the edge cost, want %v", got, tt.extra)
+			}
+		})
+	}
+}
+
+func TestPathRoutesAroundRidge(t *testing.T) {
+	// A steep ridge along X=3 that only the Z=6 row crosses on flat ground
+	heights := make(map[[2]int]int)
+	for z := 0; z < 6; z++ {
+		heights[[2]int{3, z}] = 10
+	}
+
+	base := types.NewBase(7, 1, 7)
+	start, end := types.Position{X: 0, Z: 2}, types.Position{X: 6, Z: 2}
+
+	straight, err := NewGraph(base).FindPath(start, end)
+	if err != nil {
+		t.Fatalf("FindPath on flat ground: %v", err)
+	}
+
+	graph := NewGraph(base)
+	graph.Elevation = ElevationMap(heights)
+	path, err := graph.FindPath(start, end)
+	if err != nil {
+		t.Fatalf("FindPath over the ridge: %v", err)
+	}
+
+	for _, pos := range path.Nodes {
+		if heights[[2]int{pos.X, pos.Z}] > 0 {
+			t.Errorf("path climbs the ridge at %s", pos)
+		}
+	}
+	if len(path.Nodes) <= len(straight.Nodes) {
+		t.Errorf("detour has %d steps, no more than the %d of the straight path", len(path.Nodes), len(straight.Nodes))
+	}
+}
@@ -0,0 +1,75 @@
+package pathing
+
+import (
+	"errors"
+	"palbaseiq/pkg/types"
+	"slices"
+	"testing"
+)
+
+func TestEnvironmentalItemsBlockPathing(t *testing.T) {
+	// A row of features across x = 2, open at z = 4 unless the gap is closed
+	tests := []struct {
+		name     string
+		itemType types.ItemType
+		gap      bool
+	}{
+		{"trees with a gap", types.ItemTypeTree, true},
+		{"rocks with a gap", types.ItemTypeRock, true},
+		{"water with a gap", types.ItemTypeWater, true},
+		{"trees all the way", types.ItemTypeTree, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(5, 1, 5)
+			var blocked []types.Position
+			rows := 4
+			if !tt.gap {
+				rows = 5
+			}
+			for z := 0; z < rows; z++ {
+				pos := types.Position{X: 2, Z: z}
+				feature := &types.Item{ID: GetNodeKey(pos), Type: tt.itemType, Position: pos, Bounds: types.BoundingBox{Width: 1, Height: 1, Depth: 1}}
+				if err := base.PlaceItem(feature); err != nil {
+					t.Fatalf("placing %s: %v", feature.ID, err)
+				}
+				blocked = append(blocked, pos)
+			}
+
+			graph := NewGraph(base)
+			start, end := types.Position{X: 0, Z: 0}, types.Position{X: 4, Z: 0}
+			field := graph.DistanceField(start)
+			for _, pos := range blocked {
+				if _, ok := field[GetNodeKey(pos)]; ok {
+					t.Errorf("distance field reaches %s", pos)
+				}
+			}
+
+			path, err := graph.FindPath(start, end)
+			if !tt.gap {
+				if !errors.Is(err, ErrNoPath) {
+					t.Errorf("FindPath() error = %v, want ErrNoPath", err)
+				}
+				if _, ok := field[GetNodeKey(end)]; ok {
+					t.Error("distance field reaches the far side")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindPath: %v", err)
+			}
+			if !slices.Contains(path.Nodes, types.Position{X: 2, Z: 4}) {
+				t.Errorf("path %v does not go through the gap", path.Nodes)
+			}
+			for _, pos := range blocked {
+				if slices.Contains(path.Nodes, pos) {
+					t.Errorf("path crosses %s", pos)
+				}
+			}
+			if _, ok := field[GetNodeKey(end)]; !ok {
+				t.Error("distance field misses the far side")
+			}
+		})
+	}
+}
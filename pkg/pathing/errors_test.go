@@ -0,0 +1,81 @@
+package pathing
+
+import (
+	"errors"
+	"fmt"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestPathErrors(t *testing.T) {
+	// A 10x1x3 base cut in two by a wall at x = 5, with a barrel in each half
+	base := types.NewBase(10, 1, 3)
+	for z := 0; z < 3; z++ {
+		place(t, base, fmt.Sprintf("wall_%d", z), types.StructureNameWoodenBarrel, types.Position{X: 5, Z: z})
+	}
+	place(t, base, "left", types.StructureNameWoodenBarrel, types.Position{X: 1, Z: 1})
+	place(t, base, "right", types.StructureNameWoodenBarrel, types.Position{X: 8, Z: 1})
+
+	tests := []struct {
+		name       string
+		start, end types.Position
+		limit      int
+		wantErr    error
+	}{
+		{"reachable", types.Position{X: 0}, types.Position{X: 4, Z: 2}, 0, nil},
+		{"start out of bounds", types.Position{X: -1}, types.Position{X: 4}, 0, ErrOutOfBounds},
+		{"end out of bounds", types.Position{X: 0}, types.Position{X: 4, Y: 1}, 0, ErrOutOfBounds},
+		{"start occupied", types.Position{X: 1, Z: 1}, types.Position{X: 4}, 0, ErrStartOccupied},
+		{"end occupied", types.Position{X: 0}, types.Position{X: 1, Z: 1}, 0, ErrEndOccupied},
+		{"walled off", types.Position{X: 0}, types.Position{X: 9}, 0, ErrNoPath},
+		{"search limit", types.Position{X: 0}, types.Position{X: 4, Z: 2}, 3, ErrSearchLimitExceeded},
+	}
+
+	searches := []struct {
+		name   string
+		search func(graph *Graph, start, end types.Position) error
+	}{
+		{"FindPath", func(graph *Graph, start, end types.Position) error {
+			_, err := graph.FindPath(start, end)
+			return err
+		}},
+		{"CheckReachable", (*Graph).CheckReachable},
+	}
+
+	for _, search := range searches {
+		for _, tt := range tests {
+			t.Run(search.name+"/"+tt.name, func(t *testing.T) {
+				graph := NewGraph(base)
+				graph.MaxExpansions = tt.limit
+				err := search.search(graph, tt.start, tt.end)
+				if tt.wantErr == nil {
+					if err != nil {
+						t.Fatalf("error = %v, want none", err)
+					}
+					return
+				}
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				for _, other := range []error{ErrOutOfBounds, ErrStartOccupied, ErrEndOccupied} {
+					if other != tt.wantErr && errors.Is(err, other) {
+						t.Errorf("error = %v also matches %v", err, other)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestErrSearchLimitExceededWrapsErrNoPath(t *testing.T) {
+	if !errors.Is(ErrSearchLimitExceeded, ErrNoPath) {
+		t.Error("ErrSearchLimitExceeded does not wrap ErrNoPath")
+	}
+	if errors.Is(ErrNoPath, ErrSearchLimitExceeded) {
+		t.Error("ErrNoPath matches ErrSearchLimitExceeded")
+	}
+	wrapped := fmt.Errorf("finding a route: %w", ErrSearchLimitExceeded)
+	if !errors.Is(wrapped, ErrNoPath) {
+		t.Error("a wrapped ErrSearchLimitExceeded does not match ErrNoPath")
+	}
+}
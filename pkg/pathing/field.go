@@ -0,0 +1,113 @@
+package pathing
+
+import (
+	"container/heap"
+	"palbaseiq/pkg/types"
+	"sync"
+)
+
+// distanceCache keeps the distance fields a graph has computed, each valid
+// for as long as the version of the base it was computed over
+type distanceCache struct {
+	mu     sync.Mutex
+	fields map[types.Position]cachedField
+}
+
+// cachedField is a distance field and what it was computed from
+type cachedField struct {
+	base    *types.Base
+	version uint64
+	field   map[string]float64
+}
+
+// newDistanceCache returns an empty cache
+func newDistanceCache() *distanceCache {
+	return &distanceCache{fields: make(map[types.Position]cachedField)}
+}
+
+// DistanceField returns the cost of the cheapest walk from source to every
+// traversable cell reachable from it, keyed by GetNodeKey, from a single
+// Dijkstra search. source may be a cell of an item, such as its position;
+// the walk then begins, at no cost, on any free cell beside the item, which
+// is where a Pal using it stands. Unreachable cells are absent.
+//
+// Fields are cached per source until the Base, or its Version, changes, so
+// scoring many candidates against one layout costs a single search and
+// then a map lookup each. The returned map is shared with the cache and
+// must not be modified. Changing the graph's cost settings does not
+// invalidate the cache, and copies of a Graph share it, so a copy should
+// keep the original's cost settings; Snapshot starts with an empty cache.
+func (g *Graph) DistanceField(source types.Position) map[string]float64 {
+	if g.fields == nil {
+		return g.computeDistanceField(source)
+	}
+
+	g.fields.mu.Lock()
+	cached, ok := g.fields.fields[source]
+	g.fields.mu.Unlock()
+	if ok && cached.base == g.Base && cached.version == g.Base.Version() {
+		return cached.field
+	}
+
+	field := g.computeDistanceField(source)
+	g.fields.mu.Lock()
+	g.fields.fields[source] = cachedField{base: g.Base, version: g.Base.Version(), field: field}
+	g.fields.mu.Unlock()
+	return field
+}
+
+// computeDistanceField runs the search behind DistanceField. It visits
+// most of the base, so its nodes live in one slice indexed by cell rather
+// than in maps.
+func (g *Graph) computeDistanceField(source types.Position) map[string]float64 {
+	base := g.Base
+	cell := func(pos types.Position) int {
+		return (pos.Y*base.Depth+pos.Z)*base.Width + pos.X
+	}
+	nodes := make([]Node, base.Width*base.Height*base.Depth)
+	queued := make([]bool, len(nodes))
+	settled := make([]bool, len(nodes))
+	openSet := &PriorityQueue{}
+
+	relax := func(pos types.Position, cost float64) {
+		i := cell(pos)
+		node := &nodes[i]
+		if queued[i] && cost >= node.Cost {
+			return
+		}
+		node.Position = pos
+		node.Cost = cost
+		node.Priority = cost
+		if queued[i] {
+			heap.Fix(openSet, node.Index)
+		} else {
+			queued[i] = true
+			heap.Push(openSet, node)
+		}
+	}
+
+	if base.IsTraversable(source) {
+		relax(source, 0)
+	} else if item := base.GetItemAtPosition(source); item != nil {
+		for _, start := range base.AdjacentFreePositions(item) {
+			relax(start, 0)
+		}
+	}
+
+	field := make(map[string]float64)
+	var neighbors []types.Position
+	for openSet.Len() > 0 {
+		current := heap.Pop(openSet).(*Node)
+		settled[cell(current.Position)] = true
+		field[GetNodeKey(current.Position)] = current.Cost
+
+		neighbors = g.appendNeighbors(neighbors[:0], current.Position)
+		for _, next := range neighbors {
+			if !settled[cell(next)] {
+				relax(next, current.Cost+g.CalculateEdgeCost(current.Position, next))
+			}
+		}
+	}
+
+	return field
+}
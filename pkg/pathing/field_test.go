@@ -0,0 +1,133 @@
+package pathing
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+	"reflect"
+	"testing"
+)
+
+func TestDistanceFieldMatchesShortestPaths(t *testing.T) {
+	base, _, _ := benchmarkLayout(t)
+	graph := NewGraph(base)
+
+	tests := []struct {
+		name   string
+		source types.Position
+	}{
+		{"free cell", types.Position{X: 0, Z: 0}},
+		{"upper level", types.Position{X: 5, Y: 3, Z: 12}},
+		{"item cell", types.Position{X: 9, Z: 9}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var starts []types.Position
+			if base.IsTraversable(tt.source) {
+				starts = []types.Position{tt.source}
+			} else {
+				starts = base.AdjacentFreePositions(base.GetItemAtPosition(tt.source))
+			}
+			targets := []types.Position{{X: 18, Z: 18}, {X: 0, Z: 19}, {X: 12, Y: 3, Z: 2}, {X: 11, Z: 19}}
+			paths := graph.ShortestPathsFromAny(starts, targets)
+
+			field := graph.DistanceField(tt.source)
+			for _, target := range targets {
+				key := GetNodeKey(target)
+				if math.Abs(field[key]-paths[key].Cost) > 1e-9 {
+					t.Errorf("field cost to %s = %v, shortest path %v", target, field[key], paths[key].Cost)
+				}
+			}
+			if _, ok := field[GetNodeKey(types.Position{X: 1, Z: 1})]; ok {
+				t.Error("field reaches a barrel's cell")
+			}
+		})
+	}
+}
+
+func TestDistanceFieldCache(t *testing.T) {
+	base := types.NewBase(6, 1, 6)
+	graph := NewGraph(base)
+	source, target := types.Position{X: 0, Z: 0}, types.Position{X: 5, Z: 0}
+	same := func(a, b map[string]float64) bool {
+		return reflect.ValueOf(a).UnsafePointer() == reflect.ValueOf(b).UnsafePointer()
+	}
+
+	first := graph.DistanceField(source)
+	if !same(first, graph.DistanceField(source)) {
+		t.Error("an unchanged base recomputed its field")
+	}
+
+	base.Reserve(types.Position{X: 1, Z: 4})
+	if !same(first, graph.DistanceField(source)) {
+		t.Error("a reservation, which keeps cells walkable, recomputed the field")
+	}
+
+	// Wall off the target's row, so the walk must go round
+	for z := 0; z < 5; z++ {
+		place(t, base, string(rune('a'+z)), types.StructureNameWoodenBarrel, types.Position{X: 3, Z: z})
+	}
+	changed := graph.DistanceField(source)
+	if same(first, changed) {
+		t.Fatal("placing items did not invalidate the field")
+	}
+	if changed[GetNodeKey(target)] <= first[GetNodeKey(target)] {
+		t.Errorf("cost to %s went from %v to %v round a wall", target, first[GetNodeKey(target)], changed[GetNodeKey(target)])
+	}
+
+	other := NewGraph(base.Clone())
+	if same(changed, other.DistanceField(source)) {
+		t.Error("another base shared the cached field")
+	}
+}
+
+// groundCells returns the traversable cells of the ground level, the
+// candidates greedy placement scores a single-cell item at
+func groundCells(base *types.Base) []types.Position {
+	var cells []types.Position
+	for x := 0; x < base.Width; x++ {
+		for z := 0; z < base.Depth; z++ {
+			if pos := (types.Position{X: x, Z: z}); base.IsTraversable(pos) {
+				cells = append(cells, pos)
+			}
+		}
+	}
+	return cells
+}
+
+// BenchmarkCandidateCostsFindPath prices the walk from the Palbox to every
+// ground candidate with a search each, as scoring did before distance
+// fields, for comparison with BenchmarkCandidateCostsDistanceField
+func BenchmarkCandidateCostsFindPath(b *testing.B) {
+	base, starts, _ := benchmarkLayout(b)
+	graph := NewGraph(base)
+	cells := groundCells(base)
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, cell := range cells {
+			graph.FindPath(starts[0], cell)
+		}
+	}
+}
+
+func BenchmarkCandidateCostsDistanceField(b *testing.B) {
+	base, _, _ := benchmarkLayout(b)
+	graph := NewGraph(base)
+	cells := groundCells(base)
+	palbox := types.Position{X: 9, Z: 9}
+	b.ReportAllocs()
+	for b.Loop() {
+		field := graph.DistanceField(palbox)
+		for _, cell := range cells {
+			_ = field[GetNodeKey(cell)]
+		}
+	}
+}
+
+func BenchmarkDistanceFieldUncached(b *testing.B) {
+	base, _, _ := benchmarkLayout(b)
+	palbox := types.Position{X: 9, Z: 9}
+	b.ReportAllocs()
+	for b.Loop() {
+		NewGraph(base).DistanceField(palbox)
+	}
+}
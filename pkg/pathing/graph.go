@@ -2,11 +2,27 @@ package pathing
 
 import (
 	"container/heap"
+	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"palbaseiq/pkg/types"
 )
 
+// Errors returned by FindPath and CheckReachable. They are wrapped with the
+// positions involved, so callers should test for them with errors.Is.
+var (
+	ErrOutOfBounds   = errors.New("position out of bounds")
+	ErrStartOccupied = errors.New("start position is occupied")
+	ErrEndOccupied   = errors.New("end position is occupied")
+	ErrNoPath        = errors.New("no path found")
+
+	// ErrSearchLimitExceeded reports that a search gave up after
+	// Graph.MaxExpansions nodes. It wraps ErrNoPath, so callers that only
+	// care whether a path was found need not tell the two apart.
+	ErrSearchLimitExceeded = fmt.Errorf("%w: search limit exceeded", ErrNoPath)
+)
+
 // Node represents a node in the pathfinding graph
 type Node struct {
 	Position types.Position
@@ -23,93 +39,217 @@ type Path struct {
 	Cost     float64
 }
 
-// Graph represents the pathfinding graph for the base
+// EstimatedTime converts the path's distance into in-game seconds for a Pal
+// moving at palSpeedMetersPerSec, where each grid cell spans cellMeters.
+// A non-positive speed never arrives and yields +Inf.
+func (p *Path) EstimatedTime(cellMeters, palSpeedMetersPerSec float64) float64 {
+	if palSpeedMetersPerSec <= 0 {
+		return math.Inf(1)
+	}
+	return p.Distance * cellMeters / palSpeedMetersPerSec
+}
+
+// Graph represents the pathfinding graph for the base. Nodes and edges are
+// never materialized: neighbors and edge costs are computed from the Base
+// as searches reach them, so memory use is bounded by the cells a search
+// visits rather than by the size of the base.
+//
+// Concurrency: FindPath and ShortestPathsFrom keep all search state in
+// per-call buffers (FindPath's come from a pool of PathSearchers) and only
+// read the Graph and its Base, so any number of queries may run
+// concurrently. Mutating the Base changes what queries see,
+// so it may not overlap with queries. Use Snapshot to query a layout while
+// the original keeps changing.
 type Graph struct {
 	Base      *types.Base
-	Nodes     map[string]*Node
-	Edges     map[string][]Edge
 	Heuristic HeuristicFunction
+
+	// ObstacleFalloff maps the distance to an occupied neighbor cell to the
+	// penalty it adds. Nil uses DefaultObstacleFalloff.
+	ObstacleFalloff FalloffFunction
+
+	// HeuristicWeight inflates the heuristic in FindPath, turning A* into
+	// weighted A*. At 1 paths are optimal; at a weight w > 1 fewer nodes are
+	// expanded and the path found costs at most w times the optimal one,
+	// which suits the optimizer's inner loop where a fast, near-optimal
+	// path is worth more than an exact slow one. Values below 1 are treated
+	// as 1.
+	HeuristicWeight float64
+
+	// Elevation gives the terrain height of each X/Z column, independent of
+	// the Y grid levels, for sloped build sites. Moving to a higher column
+	// adds ClimbCostPerUnit per unit of rise and moving to a lower one adds
+	// DescentCostPerUnit per unit of drop, so flat ground is cheapest. Nil
+	// treats the site as flat.
+	Elevation ElevationFunction
+
+	// MaxExpansions caps how many nodes FindPath and CheckReachable expand
+	// before giving up with ErrSearchLimitExceeded; 0 means no limit. When
+	// the end is walled off a search otherwise visits every free cell
+	// before failing, which is costly inside the optimizer's loop. The
+	// price of a limit is that a reachable end whose path needs more
+	// expansions than allowed is reported as unreachable.
+	MaxExpansions int
+
+	// CellWeight multiplies the distance cost of every step into a cell,
+	// so that Pals keep away from areas such as a defense perimeter even
+	// where they could walk. Cells missing from the map weigh 1. Weights
+	// below 1 are treated as 1, which keeps FindPath's heuristic from
+	// overestimating. AvoidCategory fills it around structures.
+	CellWeight map[types.Position]float64
+
+	// MovementPlane restricts the directions Pals move in. The zero value,
+	// Full3D, allows all six; a flat single-story base can use XZPlane to
+	// drop the vertical moves and halve the branching of every search.
+	MovementPlane MovementPlane
+
+	// fields caches DistanceField results
+	fields *distanceCache
+}
+
+// MovementPlane selects the directions GetNeighbors emits
+type MovementPlane int
+
+const (
+	// Full3D moves along all three axes
+	Full3D MovementPlane = iota
+
+	// XZPlane moves only horizontally, never changing Y
+	XZPlane
+
+	// XYPlane moves only along X and Y, never changing Z
+	XYPlane
+)
+
+// String returns the plane's name
+func (p MovementPlane) String() string {
+	switch p {
+	case Full3D:
+		return "full_3d"
+	case XZPlane:
+		return "xz_plane"
+	case XYPlane:
+		return "xy_plane"
+	}
+	return "unknown"
 }
 
-// Edge represents a connection between two nodes
-type Edge struct {
-	From   types.Position
-	To     types.Position
-	Cost   float64
-	Weight float64
+// allows reports whether the plane permits a move in direction dir
+func (p MovementPlane) allows(dir types.Position) bool {
+	switch p {
+	case XZPlane:
+		return dir.Y == 0
+	case XYPlane:
+		return dir.Z == 0
+	}
+	return true
 }
 
+// ElevationFunction returns the terrain height of the column at x, z
+type ElevationFunction func(x, z int) float64
+
+// ElevationMap returns an ElevationFunction backed by a table of heights
+// keyed by {x, z}. Columns missing from the table are at height 0.
+func ElevationMap(heights map[[2]int]int) ElevationFunction {
+	return func(x, z int) float64 {
+		return float64(heights[[2]int{x, z}])
+	}
+}
+
+// Slope costs added per unit of elevation change between columns
+const (
+	ClimbCostPerUnit   = 1.0
+	DescentCostPerUnit = 0.25
+)
+
 // HeuristicFunction defines the heuristic function for A* pathfinding
 type HeuristicFunction func(from, to types.Position) float64
 
+// FalloffFunction maps a distance to an obstacle to a movement penalty
+type FalloffFunction func(distance float64) float64
+
+// DefaultObstacleFalloff applies an inverse-distance penalty of 0.1/distance
+func DefaultObstacleFalloff(distance float64) float64 {
+	return 0.1 / distance
+}
+
 // NewGraph creates a new pathfinding graph for the base
 func NewGraph(base *types.Base) *Graph {
 	return &Graph{
-		Base:      base,
-		Nodes:     make(map[string]*Node),
-		Edges:     make(map[string][]Edge),
-		Heuristic: ManhattanDistance,
+		Base:            base,
+		Heuristic:       ManhattanDistance,
+		ObstacleFalloff: DefaultObstacleFalloff,
+		HeuristicWeight: 1,
+		fields:          newDistanceCache(),
 	}
 }
 
+// Snapshot returns a graph over a deep copy of the base that shares the
+// heuristic and cost settings but none of the mutable state. The snapshot
+// is safe for concurrent queries while the original graph or base changes.
+func (g *Graph) Snapshot() *Graph {
+	snapshot := *g
+	snapshot.Base = g.Base.Clone()
+	snapshot.CellWeight = maps.Clone(g.CellWeight)
+	snapshot.fields = newDistanceCache()
+	return &snapshot
+}
+
 // GetNodeKey returns a unique key for a position
 func GetNodeKey(pos types.Position) string {
 	return fmt.Sprintf("%d,%d,%d", pos.X, pos.Y, pos.Z)
 }
 
-// AddNode adds a node to the graph
-func (g *Graph) AddNode(pos types.Position) {
-	key := GetNodeKey(pos)
-	if _, exists := g.Nodes[key]; !exists {
-		g.Nodes[key] = &Node{
-			Position: pos,
-			Cost:     math.Inf(1),
-		}
-	}
+// GetNeighbors returns all valid neighbors of a position within the graph's
+// MovementPlane. Cells of passable items such as doors count as walkable.
+func (g *Graph) GetNeighbors(pos types.Position) []types.Position {
+	return g.appendNeighbors(nil, pos)
 }
 
-// AddEdge adds an edge between two positions
-func (g *Graph) AddEdge(from, to types.Position, cost float64) {
-	fromKey := GetNodeKey(from)
-
-	// Add nodes if they don't exist
-	g.AddNode(from)
-	g.AddNode(to)
-
-	// Add edge
-	edge := Edge{
-		From:   from,
-		To:     to,
-		Cost:   cost,
-		Weight: cost,
+// AccessDegree counts the walkable cells beside pos on its own level, the
+// directions a Pal can walk up to it from. pos itself may be occupied, so
+// summing the degree over an item's bottom cells counts the approaches to
+// the item. A cell reached only through one narrow corridor has degree 1.
+func (g *Graph) AccessDegree(pos types.Position) int {
+	degree := 0
+	for _, dir := range neighborDirections {
+		if dir.Y != 0 {
+			continue
+		}
+		neighbor := types.Position{X: pos.X + dir.X, Y: pos.Y, Z: pos.Z + dir.Z}
+		if g.Base.IsTraversable(neighbor) {
+			degree++
+		}
 	}
-
-	g.Edges[fromKey] = append(g.Edges[fromKey], edge)
+	return degree
 }
 
-// GetNeighbors returns all valid neighbors of a position
-func (g *Graph) GetNeighbors(pos types.Position) []types.Position {
-	var neighbors []types.Position
-
-	// Define the 6 possible directions (up, down, left, right, forward, backward)
-	directions := []types.Position{
-		{0, 1, 0},  // up
-		{0, -1, 0}, // down
-		{-1, 0, 0}, // left
-		{1, 0, 0},  // right
-		{0, 0, -1}, // forward
-		{0, 0, 1},  // backward
-	}
+// neighborDirections are the 6 possible moves (up, down, left, right,
+// forward, backward)
+var neighborDirections = [...]types.Position{
+	{X: 0, Y: 1, Z: 0},  // up
+	{X: 0, Y: -1, Z: 0}, // down
+	{X: -1, Y: 0, Z: 0}, // left
+	{X: 1, Y: 0, Z: 0},  // right
+	{X: 0, Y: 0, Z: -1}, // forward
+	{X: 0, Y: 0, Z: 1},  // backward
+}
 
-	for _, dir := range directions {
+// appendNeighbors appends the valid neighbors of pos to neighbors and
+// returns the result, like GetNeighbors but reusing the caller's storage
+func (g *Graph) appendNeighbors(neighbors []types.Position, pos types.Position) []types.Position {
+	for _, dir := range neighborDirections {
+		if !g.MovementPlane.allows(dir) {
+			continue
+		}
 		neighbor := types.Position{
 			X: pos.X + dir.X,
 			Y: pos.Y + dir.Y,
 			Z: pos.Z + dir.Z,
 		}
 
-		// Check if neighbor is valid and not occupied
-		if g.Base.IsPositionValid(neighbor) && !g.Base.IsPositionOccupied(neighbor) {
+		// Pals can only step onto traversable cells
+		if g.Base.IsTraversable(neighbor) {
 			neighbors = append(neighbors, neighbor)
 		}
 	}
@@ -117,27 +257,16 @@ func (g *Graph) GetNeighbors(pos types.Position) []types.Position {
 	return neighbors
 }
 
-// BuildGraph builds the complete graph from the base
-func (g *Graph) BuildGraph() {
-	// Clear existing graph
-	g.Nodes = make(map[string]*Node)
-	g.Edges = make(map[string][]Edge)
-
-	// Add all free positions as nodes
-	freePositions := g.Base.GetFreePositions()
-	for _, pos := range freePositions {
-		g.AddNode(pos)
-	}
-
-	// Add edges between adjacent free positions
-	for _, pos := range freePositions {
-		neighbors := g.GetNeighbors(pos)
-		for _, neighbor := range neighbors {
-			// Calculate edge cost based on distance and terrain
-			cost := g.CalculateEdgeCost(pos, neighbor)
-			g.AddEdge(pos, neighbor, cost)
-		}
+// BuildGraph checks that the walkable space of the base forms a single
+// connected region, returning an error naming the number of regions if it
+// does not. Searches compute neighbors on demand, so no graph needs to be
+// built before calling FindPath.
+func (g *Graph) BuildGraph() error {
+	regions := g.Base.ConnectedTraversableRegions()
+	if len(regions) > 1 {
+		return fmt.Errorf("walkable space is split into %d disconnected regions", len(regions))
 	}
+	return nil
 }
 
 // CalculateEdgeCost calculates the cost of moving between two positions
@@ -149,16 +278,61 @@ func (g *Graph) CalculateEdgeCost(from, to types.Position) float64 {
 		baseCost *= 1.5 // Vertical movement is more expensive
 	}
 
+	// Make avoided cells more expensive to enter
+	if weight, ok := g.CellWeight[to]; ok && weight > 1 {
+		baseCost *= weight
+	}
+
+	// Add penalties for walking up or down sloped terrain
+	if g.Elevation != nil {
+		rise := g.Elevation(to.X, to.Z) - g.Elevation(from.X, from.Z)
+		if rise > 0 {
+			baseCost += rise * ClimbCostPerUnit
+		} else {
+			baseCost -= rise * DescentCostPerUnit
+		}
+	}
+
 	// Add penalties for proximity to walls or other obstacles
 	obstaclePenalty := g.CalculateObstaclePenalty(to)
 
 	return baseCost + obstaclePenalty
 }
 
+// AvoidCategory weights every free cell within radius steps (Chebyshev
+// distance in X and Z, on the same levels) of an item of the category by
+// weight in CellWeight, keeping the higher weight where cells were already
+// weighted
+func (g *Graph) AvoidCategory(category types.StructureCategory, radius int, weight float64) {
+	if g.CellWeight == nil {
+		g.CellWeight = make(map[types.Position]float64)
+	}
+
+	for _, item := range g.Base.ItemsByCategory(category) {
+		bounds := item.EffectiveBounds()
+		for x := item.Position.X - radius; x < item.Position.X+bounds.Width+radius; x++ {
+			for y := item.Position.Y; y < item.Position.Y+bounds.Height; y++ {
+				for z := item.Position.Z - radius; z < item.Position.Z+bounds.Depth+radius; z++ {
+					pos := types.Position{X: x, Y: y, Z: z}
+					if !g.Base.IsTraversable(pos) {
+						continue
+					}
+					g.CellWeight[pos] = math.Max(g.CellWeight[pos], weight)
+				}
+			}
+		}
+	}
+}
+
 // CalculateObstaclePenalty calculates penalty for being near obstacles
 func (g *Graph) CalculateObstaclePenalty(pos types.Position) float64 {
 	penalty := 0.0
 
+	falloff := g.ObstacleFalloff
+	if falloff == nil {
+		falloff = DefaultObstacleFalloff
+	}
+
 	// Check in a 3x3x3 area around the position
 	for dx := -1; dx <= 1; dx++ {
 		for dy := -1; dy <= 1; dy++ {
@@ -170,10 +344,11 @@ func (g *Graph) CalculateObstaclePenalty(pos types.Position) float64 {
 				}
 
 				if g.Base.IsPositionValid(checkPos) && g.Base.IsPositionOccupied(checkPos) {
-					// Calculate distance-based penalty
+					// Calculate distance-based penalty, skipping the query
+					// cell itself so falloffs never see a zero distance
 					distance := math.Sqrt(float64(dx*dx + dy*dy + dz*dz))
 					if distance > 0 {
-						penalty += 0.1 / distance
+						penalty += falloff(distance)
 					}
 				}
 			}
@@ -183,91 +358,202 @@ func (g *Graph) CalculateObstaclePenalty(pos types.Position) float64 {
 	return penalty
 }
 
-// FindPath finds the shortest path between two positions using A* algorithm
+// FindPath finds the shortest path between two positions using A* algorithm.
+// With a HeuristicWeight above 1 the path may be longer than the shortest
+// one, by at most that factor.
+// It fails with ErrOutOfBounds, ErrStartOccupied, ErrEndOccupied or ErrNoPath,
+// or with ErrSearchLimitExceeded once MaxExpansions nodes were expanded.
+// The search borrows its scratch buffers from a shared pool of
+// PathSearchers, so repeated calls allocate little beyond the Path.
 func (g *Graph) FindPath(start, end types.Position) (*Path, error) {
-	if !g.Base.IsPositionValid(start) || !g.Base.IsPositionValid(end) {
-		return nil, fmt.Errorf("invalid start or end position")
+	searcher := searcherPool.Get().(*PathSearcher)
+	defer searcherPool.Put(searcher)
+
+	searcher.graph = g
+	defer func() { searcher.graph = nil }()
+	return searcher.FindPath(start, end)
+}
+
+// searchLimitReached reports whether a search that has expanded the given
+// number of nodes must stop because of MaxExpansions
+func (g *Graph) searchLimitReached(expansions int) bool {
+	return g.MaxExpansions > 0 && expansions >= g.MaxExpansions
+}
+
+// checkEndpoints reports why a path between start and end cannot even be
+// searched for: ErrOutOfBounds if either lies outside the base, otherwise
+// ErrStartOccupied or ErrEndOccupied if one of them is blocked
+func (g *Graph) checkEndpoints(start, end types.Position) error {
+	for _, pos := range []types.Position{start, end} {
+		if !g.Base.IsPositionValid(pos) {
+			return fmt.Errorf("%w: %s", ErrOutOfBounds, pos)
+		}
+	}
+	if !g.Base.IsTraversable(start) {
+		return fmt.Errorf("%w: %s", ErrStartOccupied, start)
 	}
+	if !g.Base.IsTraversable(end) {
+		return fmt.Errorf("%w: %s", ErrEndOccupied, end)
+	}
+	return nil
+}
 
-	if g.Base.IsPositionOccupied(start) || g.Base.IsPositionOccupied(end) {
-		return nil, fmt.Errorf("start or end position is occupied")
+// IsReachable reports whether any path connects start and end. It is
+// shorthand for CheckReachable returning nil.
+func (g *Graph) IsReachable(start, end types.Position) bool {
+	return g.CheckReachable(start, end) == nil
+}
+
+// CheckReachable reports whether any path connects start and end, returning
+// nil if one does and otherwise the same errors as FindPath, including
+// ErrSearchLimitExceeded when MaxExpansions is set. It runs a
+// breadth-first search that stops as soon as end is reached and never
+// builds a Path, which makes it cheaper than FindPath when the route itself
+// is not needed.
+func (g *Graph) CheckReachable(start, end types.Position) error {
+	if err := g.checkEndpoints(start, end); err != nil {
+		return err
 	}
 
-	// Initialize open and closed sets
-	openSet := &PriorityQueue{}
-	heap.Init(openSet)
-	closedSet := make(map[string]bool)
-
-	// Initialize start node
-	startKey := GetNodeKey(start)
-	startNode := &Node{
-		Position: start,
-		Cost:     0,
-		Priority: g.Heuristic(start, end),
+	visited := map[types.Position]bool{start: true}
+	queue := []types.Position{start}
+	expansions := 0
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == end {
+			return nil
+		}
+
+		if g.searchLimitReached(expansions) {
+			return fmt.Errorf("%w after %d expansions between %s and %s", ErrSearchLimitExceeded, expansions, start, end)
+		}
+		expansions++
+
+		for _, neighbor := range g.GetNeighbors(current) {
+			if !visited[neighbor] {
+				visited[neighbor] = true
+				queue = append(queue, neighbor)
+			}
+		}
 	}
 
-	heap.Push(openSet, startNode)
+	return fmt.Errorf("%w between %s and %s", ErrNoPath, start, end)
+}
 
-	// Keep track of all nodes for path reconstruction
-	allNodes := make(map[string]*Node)
-	allNodes[startKey] = startNode
+// ShortestPathsFrom runs a single Dijkstra search from start and returns the
+// shortest path to each reachable target, keyed by GetNodeKey of the target.
+// Unreachable targets are absent from the result. The search stops as soon
+// as every target has been settled.
+func (g *Graph) ShortestPathsFrom(start types.Position, targets []types.Position) map[string]*Path {
+	return g.ShortestPathsFromAny([]types.Position{start}, targets)
+}
 
-	for openSet.Len() > 0 {
-		current := heap.Pop(openSet).(*Node)
-		currentKey := GetNodeKey(current.Position)
+// ShortestPathsFromAny is ShortestPathsFrom with several starts searched at
+// once: each target's path begins at whichever start is cheapest to reach
+// it from, which Nodes[0] of the path records. Starts outside the base or
+// blocked are ignored.
+func (g *Graph) ShortestPathsFromAny(starts []types.Position, targets []types.Position) map[string]*Path {
+	paths := make(map[string]*Path)
+
+	// The search may visit most of the base, so like computeDistanceField
+	// it keeps its nodes in slices indexed by cell rather than in maps
+	base := g.Base
+	cell := func(pos types.Position) int {
+		return (pos.Y*base.Depth+pos.Z)*base.Width + pos.X
+	}
+	remaining := make(map[int]bool)
+	for _, target := range targets {
+		if base.IsPositionValid(target) {
+			remaining[cell(target)] = true
+		}
+	}
 
-		// Check if we reached the goal
-		if current.Position == end {
-			return g.ReconstructPath(current), nil
+	nodes := make([]Node, base.Width*base.Height*base.Depth)
+	queued := make([]bool, len(nodes))
+	settled := make([]bool, len(nodes))
+	openSet := &PriorityQueue{}
+
+	for _, start := range starts {
+		if !base.IsTraversable(start) || queued[cell(start)] {
+			continue
 		}
+		i := cell(start)
+		nodes[i] = Node{Position: start}
+		queued[i] = true
+		heap.Push(openSet, &nodes[i])
+	}
 
-		closedSet[currentKey] = true
+	var neighbors []types.Position
+	for openSet.Len() > 0 && len(remaining) > 0 {
+		current := heap.Pop(openSet).(*Node)
+		currentCell := cell(current.Position)
+		settled[currentCell] = true
 
-		// Check neighbors
-		neighbors := g.GetNeighbors(current.Position)
-		for _, neighborPos := range neighbors {
-			neighborKey := GetNodeKey(neighborPos)
+		if remaining[currentCell] {
+			if path, err := g.ReconstructPath(current); err == nil {
+				paths[GetNodeKey(current.Position)] = path
+			}
+			delete(remaining, currentCell)
+		}
 
-			if closedSet[neighborKey] {
+		neighbors = g.appendNeighbors(neighbors[:0], current.Position)
+		for _, neighborPos := range neighbors {
+			i := cell(neighborPos)
+			if settled[i] {
 				continue
 			}
 
-			// Calculate tentative cost
 			tentativeCost := current.Cost + g.CalculateEdgeCost(current.Position, neighborPos)
-
-			// Get or create neighbor node
-			neighbor, exists := allNodes[neighborKey]
-			if !exists {
-				neighbor = &Node{
-					Position: neighborPos,
-					Cost:     math.Inf(1),
-				}
-				allNodes[neighborKey] = neighbor
+			neighbor := &nodes[i]
+			if queued[i] && tentativeCost >= neighbor.Cost {
+				continue
 			}
 
-			if tentativeCost < neighbor.Cost {
-				neighbor.Parent = current
-				neighbor.Cost = tentativeCost
-				neighbor.Priority = tentativeCost + g.Heuristic(neighborPos, end)
-
-				if !exists {
-					heap.Push(openSet, neighbor)
-				} else {
-					heap.Fix(openSet, neighbor.Index)
-				}
+			neighbor.Position = neighborPos
+			neighbor.Parent = current
+			neighbor.Cost = tentativeCost
+			neighbor.Priority = tentativeCost
+			if queued[i] {
+				heap.Fix(openSet, neighbor.Index)
+			} else {
+				queued[i] = true
+				heap.Push(openSet, neighbor)
 			}
 		}
 	}
 
-	return nil, fmt.Errorf("no path found between %s and %s", start, end)
+	return paths
 }
 
-// ReconstructPath reconstructs the path from the goal node
-func (g *Graph) ReconstructPath(goalNode *Node) *Path {
+// NearestSourceDistance returns, for each reachable target, the cost of the
+// cheapest path to it from whichever source is nearest, keyed by
+// GetNodeKey of the target. All sources are searched at once, as one
+// Dijkstra search seeded with every source at cost 0. Unreachable targets
+// are absent from the result.
+func (g *Graph) NearestSourceDistance(targets []types.Position, sources []types.Position) map[string]float64 {
+	paths := g.ShortestPathsFromAny(sources, targets)
+	distances := make(map[string]float64, len(paths))
+	for key, path := range paths {
+		distances[key] = path.Cost
+	}
+	return distances
+}
+
+// ReconstructPath reconstructs the path from the goal node by following
+// parent links. A parent chain that revisits a position is reported as an
+// error instead of looping forever.
+func (g *Graph) ReconstructPath(goalNode *Node) (*Path, error) {
 	var positions []types.Position
+	visited := make(map[types.Position]bool)
 	current := goalNode
 
 	for current != nil {
+		if visited[current.Position] {
+			return nil, fmt.Errorf("cycle in parent chain at %s", current.Position)
+		}
+		visited[current.Position] = true
+
 		positions = append([]types.Position{current.Position}, positions...)
 		current = current.Parent
 	}
@@ -285,7 +571,7 @@ func (g *Graph) ReconstructPath(goalNode *Node) *Path {
 		Nodes:    positions,
 		Distance: distance,
 		Cost:     cost,
-	}
+	}, nil
 }
 
 // FindOptimalPath finds the optimal path considering multiple factors
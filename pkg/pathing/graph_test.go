@@ -0,0 +1,528 @@
+package pathing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"palbaseiq/pkg/types"
+	"sync"
+	"testing"
+)
+
+// place puts a new item of the named structure at pos, failing the test if
+// it does not fit
+func place(t testing.TB, base *types.Base, id string, name types.StructureName, pos types.Position) *types.Item {
+	t.Helper()
+	item := types.NewItem(id, name)
+	item.Position = pos
+	if err := base.PlaceItem(item); err != nil {
+		t.Fatalf("placing %s: %v", id, err)
+	}
+	return item
+}
+
+func TestPathEstimatedTime(t *testing.T) {
+	tests := []struct {
+		name       string
+		distance   float64
+		cellMeters float64
+		speed      float64
+		want       float64
+	}{
+		{"one cell per second", 10, 1, 1, 10},
+		{"large cells", 10, 2.5, 5, 5},
+		{"empty path", 0, 4, 2, 0},
+		{"zero speed", 10, 1, 0, math.Inf(1)},
+		{"negative speed", 10, 1, -3, math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := &Path{Distance: tt.distance}
+			if got := path.EstimatedTime(tt.cellMeters, tt.speed); got != tt.want {
+				t.Errorf("EstimatedTime(%v, %v) = %v, want %v", tt.cellMeters, tt.speed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateObstaclePenaltyFalloff(t *testing.T) {
+	tests := []struct {
+		name    string
+		falloff FalloffFunction
+		want    float64
+	}{
+		{"default", DefaultObstacleFalloff, 0.1 + 0.1/math.Sqrt2},
+		{"nil falls back to default", nil, 0.1 + 0.1/math.Sqrt2},
+		{"constant", func(float64) float64 { return 1 }, 2},
+		{"squared", func(d float64) float64 { return 1 / (d * d) }, 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(3, 1, 3)
+			place(t, base, "side", types.StructureNameWoodenBarrel, types.Position{X: 2, Z: 1})
+			place(t, base, "corner", types.StructureNameWoodenBarrel, types.Position{X: 0, Z: 0})
+
+			graph := NewGraph(base)
+			graph.ObstacleFalloff = tt.falloff
+			got := graph.CalculateObstaclePenalty(types.Position{X: 1, Z: 1})
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CalculateObstaclePenalty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortestPathsFromMatchesFindPath(t *testing.T) {
+	base := types.NewBase(8, 1, 8)
+	for z := 0; z < 6; z++ {
+		place(t, base, string(rune('a'+z)), types.StructureNameWoodenBarrel, types.Position{X: 3, Z: z})
+	}
+	// Wall off the corner cell (7,0,7)
+	place(t, base, "x", types.StructureNameWoodenBarrel, types.Position{X: 6, Z: 7})
+	place(t, base, "y", types.StructureNameWoodenBarrel, types.Position{X: 7, Z: 6})
+
+	graph := NewGraph(base)
+	start := types.Position{X: 0, Z: 0}
+	targets := []types.Position{{X: 7, Z: 0}, {X: 0, Z: 7}, {X: 5, Z: 3}, {X: 7, Z: 7}, start}
+	paths := graph.ShortestPathsFrom(start, targets)
+
+	for _, target := range targets {
+		path, found := paths[GetNodeKey(target)]
+		want, err := graph.FindPath(start, target)
+		if err != nil {
+			if found {
+				t.Errorf("target %s: got a path, but FindPath failed with %v", target, err)
+			}
+			continue
+		}
+		if !found {
+			t.Errorf("target %s: no path, but FindPath found one", target)
+			continue
+		}
+		if math.Abs(path.Cost-want.Cost) > 1e-9 {
+			t.Errorf("target %s: cost %v, FindPath cost %v", target, path.Cost, want.Cost)
+		}
+		if path.Nodes[0] != start || path.Nodes[len(path.Nodes)-1] != target {
+			t.Errorf("target %s: path runs from %s to %s", target, path.Nodes[0], path.Nodes[len(path.Nodes)-1])
+		}
+	}
+	if _, found := paths[GetNodeKey(types.Position{X: 7, Z: 7})]; found {
+		t.Error("found a path to a walled-off cell")
+	}
+}
+
+// benchmarkLayout returns the default 20x16x20 base with a Palbox in the
+// middle and a grid of single-cell items on the ground, the free cells
+// beside the Palbox that walks start from, and the free cells beside each
+// item that walks may end on
+func benchmarkLayout(b testing.TB) (*types.Base, []types.Position, [][]types.Position) {
+	base := types.NewBase(20, 16, 20)
+	palbox := place(b, base, "palbox", types.StructureNamePalbox, types.Position{X: 9, Z: 9})
+
+	var access [][]types.Position
+	for x := 1; x < 20; x += 3 {
+		for z := 1; z < 20; z += 3 {
+			pos := types.Position{X: x, Z: z}
+			if base.IsPositionOccupied(pos) {
+				continue
+			}
+			item := place(b, base, GetNodeKey(pos), types.StructureNameWoodenBarrel, pos)
+			access = append(access, base.AdjacentFreePositions(item))
+		}
+	}
+	return base, base.AdjacentFreePositions(palbox), access
+}
+
+// BenchmarkPalboxPathsPerItem finds each item's cheapest walk from the
+// Palbox with a FindPath call per start and access cell, as scoring did
+// before ShortestPathsFromAny
+func BenchmarkPalboxPathsPerItem(b *testing.B) {
+	base, starts, access := benchmarkLayout(b)
+	graph := NewGraph(base)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, cells := range access {
+			for _, start := range starts {
+				for _, cell := range cells {
+					graph.FindPath(start, cell)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkPalboxPathsSingleSearch finds the same walks with one search
+func BenchmarkPalboxPathsSingleSearch(b *testing.B) {
+	base, starts, access := benchmarkLayout(b)
+	graph := NewGraph(base)
+	var targets []types.Position
+	for _, cells := range access {
+		targets = append(targets, cells...)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		graph.ShortestPathsFromAny(starts, targets)
+	}
+}
+
+// TestConcurrentQueries runs many searches on one Graph at once. Run it
+// with -race to check the concurrency contract documented on Graph.
+func TestConcurrentQueries(t *testing.T) {
+	base := types.NewBase(12, 2, 12)
+	for z := 0; z < 10; z++ {
+		place(t, base, string(rune('a'+z)), types.StructureNameWoodenBarrel, types.Position{X: 6, Z: z})
+	}
+	graph := NewGraph(base)
+	start := types.Position{X: 0, Z: 0}
+	ends := []types.Position{{X: 11, Z: 0}, {X: 11, Z: 11}, {X: 3, Y: 1, Z: 9}, {X: 8, Z: 4}}
+
+	want := make([]float64, len(ends))
+	for i, end := range ends {
+		path, err := graph.FindPath(start, end)
+		if err != nil {
+			t.Fatalf("FindPath(%s): %v", end, err)
+		}
+		want[i] = path.Cost
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64*len(ends))
+	for worker := 0; worker < 64; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i, end := range ends {
+				path, err := graph.FindPath(start, end)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				if path.Cost != want[i] {
+					errs <- fmt.Errorf("worker %d: path to %s costs %v, want %v", worker, end, path.Cost, want[i])
+				}
+			}
+			graph.ShortestPathsFrom(start, ends)
+			graph.DistanceField(start)
+		}(worker)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestSnapshotIgnoresLaterChanges(t *testing.T) {
+	base := types.NewBase(3, 1, 1)
+	graph := NewGraph(base)
+	snapshot := graph.Snapshot()
+
+	place(t, base, "wall", types.StructureNameWoodenBarrel, types.Position{X: 1})
+	start, end := types.Position{X: 0}, types.Position{X: 2}
+	if _, err := graph.FindPath(start, end); err == nil {
+		t.Error("the original graph still finds a path through the new wall")
+	}
+	if _, err := snapshot.FindPath(start, end); err != nil {
+		t.Errorf("the snapshot lost its path: %v", err)
+	}
+}
+
+// TestSnapshotCellWeight changes the original graph's cell weights while
+// the snapshot is queried. Run it with -race to check they share no map.
+func TestSnapshotCellWeight(t *testing.T) {
+	base := types.NewBase(8, 1, 8)
+	place(t, base, "wall", types.StructureNameGlassFence, types.Position{X: 4, Z: 4})
+	graph := NewGraph(base)
+	graph.CellWeight = map[types.Position]float64{{X: 2, Z: 0}: 3}
+	snapshot := graph.Snapshot()
+
+	start, end := types.Position{X: 0, Z: 0}, types.Position{X: 7, Z: 7}
+	want, err := snapshot.FindPath(start, end)
+	if err != nil {
+		t.Fatalf("FindPath: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for radius := 1; radius <= 20; radius++ {
+			graph.AvoidCategory(types.StructureCategoryFoundation, radius, float64(radius))
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		path, err := snapshot.FindPath(start, end)
+		if err != nil {
+			t.Fatalf("FindPath: %v", err)
+		}
+		if path.Cost != want.Cost {
+			t.Errorf("snapshot path costs %v after the original was reweighted, want %v", path.Cost, want.Cost)
+		}
+	}
+	wg.Wait()
+
+	if len(snapshot.CellWeight) != 1 || snapshot.CellWeight[types.Position{X: 2, Z: 0}] != 3 {
+		t.Errorf("snapshot weights changed to %v", snapshot.CellWeight)
+	}
+	if len(graph.CellWeight) == 1 {
+		t.Error("AvoidCategory left the original's weights unchanged")
+	}
+}
+
+func TestReconstructPath(t *testing.T) {
+	chain := func(positions ...types.Position) []*Node {
+		nodes := make([]*Node, len(positions))
+		for i, pos := range positions {
+			nodes[i] = &Node{Position: pos}
+			if i > 0 {
+				nodes[i].Parent = nodes[i-1]
+			}
+		}
+		return nodes
+	}
+
+	straight := chain(types.Position{X: 0}, types.Position{X: 1}, types.Position{X: 2})
+	selfLoop := chain(types.Position{X: 0})
+	selfLoop[0].Parent = selfLoop[0]
+	cycle := chain(types.Position{X: 0}, types.Position{X: 1}, types.Position{X: 2})
+	cycle[0].Parent = cycle[2]
+	revisit := chain(types.Position{X: 0}, types.Position{X: 1}, types.Position{X: 0})
+
+	tests := []struct {
+		name    string
+		goal    *Node
+		wantErr bool
+		length  int
+	}{
+		{"single node", chain(types.Position{})[0], false, 1},
+		{"straight", straight[2], false, 3},
+		{"self loop", selfLoop[0], true, 0},
+		{"cycle", cycle[2], true, 0},
+		{"position revisited", revisit[2], true, 0},
+	}
+
+	graph := NewGraph(types.NewBase(3, 1, 1))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := graph.ReconstructPath(tt.goal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReconstructPath() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && len(path.Nodes) != tt.length {
+				t.Errorf("got %d nodes, want %d", len(path.Nodes), tt.length)
+			}
+		})
+	}
+}
+
+func TestHeuristicWeightBoundsCost(t *testing.T) {
+	base, _, _ := benchmarkLayout(t)
+	routes := [][2]types.Position{
+		{{X: 0, Z: 0}, {X: 18, Z: 18}},
+		{{X: 0, Z: 19}, {X: 19, Z: 0}},
+		{{X: 0, Z: 0}, {X: 12, Y: 3, Z: 2}},
+		{{X: 8, Z: 0}, {X: 11, Z: 19}},
+	}
+
+	for _, weight := range []float64{1, 1.5, 2, 5} {
+		t.Run(fmt.Sprintf("weight %v", weight), func(t *testing.T) {
+			graph := NewGraph(base)
+			graph.HeuristicWeight = weight
+			for _, route := range routes {
+				start, end := route[0], route[1]
+				optimal, ok := NewGraph(base).ShortestPathsFrom(start, []types.Position{end})[GetNodeKey(end)]
+				if !ok {
+					t.Fatalf("no path from %s to %s", start, end)
+				}
+				path, err := graph.FindPath(start, end)
+				if err != nil {
+					t.Fatalf("FindPath(%s, %s): %v", start, end, err)
+				}
+				if path.Cost > weight*optimal.Cost+1e-9 {
+					t.Errorf("%s to %s cost %v, over %v times the optimal %v", start, end, path.Cost, weight, optimal.Cost)
+				}
+				if weight == 1 && math.Abs(path.Cost-optimal.Cost) > 1e-9 {
+					t.Errorf("%s to %s cost %v at weight 1, want the optimal %v", start, end, path.Cost, optimal.Cost)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFindPathHeuristicWeight compares exact and weighted A* across
+// the default base, reporting the nodes each search expands
+func BenchmarkFindPathHeuristicWeight(b *testing.B) {
+	base, _, _ := benchmarkLayout(b)
+	start, end := types.Position{X: 0, Z: 0}, types.Position{X: 18, Z: 18}
+
+	for _, weight := range []float64{1, 1.5} {
+		b.Run(fmt.Sprintf("weight %v", weight), func(b *testing.B) {
+			graph := NewGraph(base)
+			graph.HeuristicWeight = weight
+			searcher := NewPathSearcher(graph)
+			for b.Loop() {
+				if _, err := searcher.FindPath(start, end); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(searcher.closed)), "expansions/op")
+		})
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	tests := []struct {
+		name    string
+		wall    bool
+		wantErr string
+	}{
+		{"open base", false, ""},
+		{"split by a wall", true, "walkable space is split into 2 disconnected regions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(5, 2, 3)
+			if tt.wall {
+				for z := 0; z < 3; z++ {
+					place(t, base, fmt.Sprintf("wall_%d", z), types.StructureNameOuterWall, types.Position{X: 2, Z: z})
+				}
+			}
+
+			err := NewGraph(base).BuildGraph()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("BuildGraph() = %v, want nil", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("BuildGraph() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// BenchmarkFindPathLargeBase searches across a 64x64x64 base. Neighbors are
+// computed on demand, so memory grows with the cells the search visits, not
+// with the 262144 cells of the base.
+func BenchmarkFindPathLargeBase(b *testing.B) {
+	base := types.NewBase(64, 64, 64)
+	start, end := types.Position{X: 0, Z: 0}, types.Position{X: 63, Z: 63}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := NewGraph(base).FindPath(start, end); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMaxExpansions(t *testing.T) {
+	open := types.NewBase(30, 1, 3)
+	walled := types.NewBase(30, 1, 3)
+	for z := 0; z < 3; z++ {
+		place(t, walled, fmt.Sprintf("wall_%d", z), types.StructureNameWoodenBarrel, types.Position{X: 15, Z: z})
+	}
+	start, end := types.Position{X: 0, Z: 1}, types.Position{X: 29, Z: 1}
+
+	tests := []struct {
+		name    string
+		base    *types.Base
+		limit   int
+		wantErr error
+	}{
+		{"far but within the limit", open, 200, nil},
+		{"far without a limit", open, 0, nil},
+		{"far beyond the limit", open, 10, ErrSearchLimitExceeded},
+		{"unreachable aborts at the limit", walled, 20, ErrSearchLimitExceeded},
+		{"unreachable without a limit", walled, 0, ErrNoPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewGraph(tt.base)
+			graph.MaxExpansions = tt.limit
+			searcher := NewPathSearcher(graph)
+
+			_, err := searcher.FindPath(start, end)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("FindPath() error = %v, want a path", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrNoPath) {
+				t.Fatalf("FindPath() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.limit > 0 && len(searcher.closed) > tt.limit {
+				t.Errorf("search expanded %d nodes, over the limit of %d", len(searcher.closed), tt.limit)
+			}
+			if tt.limit == 0 && errors.Is(err, ErrSearchLimitExceeded) {
+				t.Errorf("unlimited search reported %v", err)
+			}
+		})
+	}
+}
+
+func TestNearestSourceDistance(t *testing.T) {
+	base := types.NewBase(10, 1, 1)
+	place(t, base, "barrel", types.StructureNameWoodenBarrel, types.Position{X: 5})
+	graph := NewGraph(base)
+
+	tests := []struct {
+		name    string
+		sources []types.Position
+		target  types.Position
+		want    float64
+		found   bool
+	}{
+		{"single source", []types.Position{{X: 0}}, types.Position{X: 3}, 3, true},
+		{"nearer of two", []types.Position{{X: 0}, {X: 4}}, types.Position{X: 3}, 1, true},
+		{"target is a source", []types.Position{{X: 0}, {X: 4}}, types.Position{X: 4}, 0, true},
+		{"only the far side reaches", []types.Position{{X: 0}, {X: 9}}, types.Position{X: 7}, 2, true},
+		{"walled off", []types.Position{{X: 0}}, types.Position{X: 7}, 0, false},
+		{"no sources", nil, types.Position{X: 3}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distances := graph.NearestSourceDistance([]types.Position{tt.target}, tt.sources)
+			got, found := distances[GetNodeKey(tt.target)]
+			if found != tt.found || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("distance to %s = %v, %v, want %v, %v", tt.target, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
+
+func TestAccessDegree(t *testing.T) {
+	base := types.NewBase(5, 2, 5)
+	// A dead-end corridor along z=4: (3,0,4) is walled in on three sides
+	place(t, base, "a", types.StructureNameWoodenBarrel, types.Position{X: 3, Z: 3})
+	place(t, base, "b", types.StructureNameWoodenBarrel, types.Position{X: 4, Z: 4})
+	graph := NewGraph(base)
+
+	tests := []struct {
+		name string
+		pos  types.Position
+		want int
+	}{
+		{"open middle", types.Position{X: 1, Z: 1}, 4},
+		{"corner", types.Position{X: 0, Z: 0}, 2},
+		{"edge", types.Position{X: 2, Z: 0}, 3},
+		{"dead end", types.Position{X: 3, Z: 4}, 1},
+		{"occupied cell", types.Position{X: 3, Z: 3}, 4},
+		{"between two items", types.Position{X: 4, Z: 3}, 1},
+		{"upper level", types.Position{X: 1, Y: 1, Z: 1}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graph.AccessDegree(tt.pos); got != tt.want {
+				t.Errorf("AccessDegree(%s) = %d, want %d", tt.pos, got, tt.want)
+			}
+		})
+	}
+}
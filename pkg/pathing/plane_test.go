@@ -0,0 +1,94 @@
+package pathing
+
+import (
+	"fmt"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestMovementPlaneNeighbors(t *testing.T) {
+	graph := NewGraph(types.NewBase(3, 3, 3))
+	center := types.Position{X: 1, Y: 1, Z: 1}
+
+	tests := []struct {
+		plane MovementPlane
+		want  int
+		fixed func(types.Position) bool
+	}{
+		{Full3D, 6, func(types.Position) bool { return true }},
+		{XZPlane, 4, func(pos types.Position) bool { return pos.Y == center.Y }},
+		{XYPlane, 4, func(pos types.Position) bool { return pos.Z == center.Z }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.plane.String(), func(t *testing.T) {
+			graph.MovementPlane = tt.plane
+			neighbors := graph.GetNeighbors(center)
+			if len(neighbors) != tt.want {
+				t.Errorf("got %d neighbors, want %d", len(neighbors), tt.want)
+			}
+			for _, neighbor := range neighbors {
+				if !tt.fixed(neighbor) {
+					t.Errorf("neighbor %s leaves the plane", neighbor)
+				}
+			}
+		})
+	}
+}
+
+// TestXZPlaneNeverChangesY walls off most of the ground level, so that
+// climbing over the wall is shorter than walking round it
+func TestXZPlaneNeverChangesY(t *testing.T) {
+	base := types.NewBase(8, 2, 8)
+	for z := 0; z < 7; z++ {
+		place(t, base, fmt.Sprintf("wall_%d", z), types.StructureNameWoodenBarrel, types.Position{X: 3, Z: z})
+	}
+	start, end := types.Position{X: 0, Z: 0}, types.Position{X: 6, Z: 0}
+
+	graph := NewGraph(base)
+	climb, err := graph.FindPath(start, end)
+	if err != nil {
+		t.Fatalf("FindPath in 3D: %v", err)
+	}
+
+	graph.MovementPlane = XZPlane
+	flat, err := graph.FindPath(start, end)
+	if err != nil {
+		t.Fatalf("FindPath in the XZ plane: %v", err)
+	}
+	for _, node := range flat.Nodes {
+		if node.Y != start.Y {
+			t.Fatalf("XZ path %v leaves level %d", flat.Nodes, start.Y)
+		}
+	}
+	if flat.Distance <= climb.Distance {
+		t.Errorf("XZ path of %v is no longer than the 3D path of %v over the wall", flat.Distance, climb.Distance)
+	}
+
+	// Shut the gap, and only climbing remains
+	place(t, base, "gap", types.StructureNameWoodenBarrel, types.Position{X: 3, Z: 7})
+	if _, err := NewGraph(base).FindPath(start, end); err != nil {
+		t.Errorf("FindPath in 3D with the gap shut: %v", err)
+	}
+	graph = NewGraph(base)
+	graph.MovementPlane = XZPlane
+	if path, err := graph.FindPath(start, end); err == nil {
+		t.Errorf("XZ path %v crosses a closed wall", path.Nodes)
+	}
+}
+
+func TestMovementPlaneString(t *testing.T) {
+	tests := []struct {
+		plane MovementPlane
+		want  string
+	}{
+		{Full3D, "full_3d"},
+		{XZPlane, "xz_plane"},
+		{XYPlane, "xy_plane"},
+		{MovementPlane(7), "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.plane.String(); got != tt.want {
+			t.Errorf("MovementPlane(%d).String() = %q, want %q", int(tt.plane), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package pathing
+
+import (
+	"errors"
+	"fmt"
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestIsReachable(t *testing.T) {
+	// A 10x2x3 base with a full-height wall at x = 5, a barrel at (1,0,1)
+	// and a gap in the wall at z = 2 only on the upper level
+	base := types.NewBase(10, 2, 3)
+	for z := 0; z < 3; z++ {
+		place(t, base, fmt.Sprintf("low_%d", z), types.StructureNameWoodenBarrel, types.Position{X: 5, Z: z})
+		if z < 2 {
+			place(t, base, fmt.Sprintf("high_%d", z), types.StructureNameWoodenBarrel, types.Position{X: 5, Y: 1, Z: z})
+		}
+	}
+	place(t, base, "barrel", types.StructureNameWoodenBarrel, types.Position{X: 1, Z: 1})
+
+	tests := []struct {
+		name       string
+		plane      MovementPlane
+		start, end types.Position
+		want       bool
+	}{
+		{"same side", Full3D, types.Position{X: 0}, types.Position{X: 4, Z: 2}, true},
+		{"same cell", Full3D, types.Position{X: 2}, types.Position{X: 2}, true},
+		{"over the wall", Full3D, types.Position{X: 0}, types.Position{X: 9}, true},
+		{"walled off on one level", XZPlane, types.Position{X: 0}, types.Position{X: 9}, false},
+		{"occupied end", Full3D, types.Position{X: 0}, types.Position{X: 1, Z: 1}, false},
+		{"occupied start", Full3D, types.Position{X: 1, Z: 1}, types.Position{X: 0}, false},
+		{"out of bounds", Full3D, types.Position{X: 0}, types.Position{X: 10}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewGraph(base)
+			graph.MovementPlane = tt.plane
+			if got := graph.IsReachable(tt.start, tt.end); got != tt.want {
+				t.Errorf("IsReachable() = %v, want %v", got, tt.want)
+			}
+
+			// FindPath agrees
+			_, err := graph.FindPath(tt.start, tt.end)
+			if (err == nil) != tt.want {
+				t.Errorf("FindPath() error = %v, but IsReachable() = %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckReachableMaxExpansions(t *testing.T) {
+	base := types.NewBase(30, 1, 1)
+	start := types.Position{X: 0}
+
+	tests := []struct {
+		name    string
+		end     types.Position
+		limit   int
+		wantErr error
+	}{
+		{"near within the limit", types.Position{X: 3}, 5, nil},
+		{"end at the limit", types.Position{X: 5}, 5, nil},
+		{"end beyond the limit", types.Position{X: 6}, 5, ErrSearchLimitExceeded},
+		{"far without a limit", types.Position{X: 29}, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewGraph(base)
+			graph.MaxExpansions = tt.limit
+			err := graph.CheckReachable(start, tt.end)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("CheckReachable() error = %v, want none", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrNoPath) {
+				t.Fatalf("CheckReachable() error = %v, want %v", err, tt.wantErr)
+			}
+			if graph.IsReachable(start, tt.end) {
+				t.Error("IsReachable() is true beyond the limit")
+			}
+		})
+	}
+}
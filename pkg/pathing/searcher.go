@@ -0,0 +1,143 @@
+package pathing
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"palbaseiq/pkg/types"
+	"sync"
+)
+
+// nodeChunkSize is how many nodes a PathSearcher allocates at a time
+const nodeChunkSize = 256
+
+// searcherPool holds idle PathSearchers for Graph.FindPath
+var searcherPool = sync.Pool{
+	New: func() any { return &PathSearcher{} },
+}
+
+// PathSearcher runs A* searches on one graph with scratch buffers that are
+// kept between queries: the open queue, the closed set, the node table and
+// the nodes themselves. Reusing one searcher for many queries on the same
+// graph avoids reallocating them every time. A PathSearcher must not be
+// used by several goroutines at once; Graph.FindPath draws one from a
+// shared pool for each call instead.
+type PathSearcher struct {
+	graph *Graph
+
+	open      PriorityQueue
+	closed    map[types.Position]bool
+	nodes     map[types.Position]*Node
+	neighbors []types.Position
+
+	// chunks hold every node handed out since the last Reset; used counts
+	// the nodes taken from the last chunk in use
+	chunks [][]Node
+	chunk  int
+	used   int
+}
+
+// NewPathSearcher creates a searcher for the graph
+func NewPathSearcher(graph *Graph) *PathSearcher {
+	return &PathSearcher{graph: graph}
+}
+
+// Reset clears the searcher's buffers for the next query while keeping
+// their storage. FindPath resets before every search, so calling it is only
+// needed to drop references to the last search's nodes early.
+func (s *PathSearcher) Reset() {
+	s.open = s.open[:0]
+	clear(s.closed)
+	clear(s.nodes)
+	s.neighbors = s.neighbors[:0]
+	s.chunk, s.used = 0, 0
+}
+
+// newNode hands out a zeroed node from the searcher's chunks, adding a
+// chunk when all of them are in use. Nodes never move once handed out, so
+// pointers to them stay valid until the next Reset.
+func (s *PathSearcher) newNode(pos types.Position) *Node {
+	if s.chunk < len(s.chunks) && s.used == nodeChunkSize {
+		s.chunk++
+		s.used = 0
+	}
+	if s.chunk == len(s.chunks) {
+		s.chunks = append(s.chunks, make([]Node, nodeChunkSize))
+	}
+
+	node := &s.chunks[s.chunk][s.used]
+	s.used++
+	*node = Node{Position: pos}
+	return node
+}
+
+// FindPath is Graph.FindPath run with the searcher's buffers
+func (s *PathSearcher) FindPath(start, end types.Position) (*Path, error) {
+	g := s.graph
+	if err := g.checkEndpoints(start, end); err != nil {
+		return nil, err
+	}
+
+	s.Reset()
+	if s.closed == nil {
+		s.closed = make(map[types.Position]bool)
+		s.nodes = make(map[types.Position]*Node)
+	}
+
+	weight := math.Max(g.HeuristicWeight, 1)
+
+	startNode := s.newNode(start)
+	startNode.Priority = weight * g.Heuristic(start, end)
+	heap.Push(&s.open, startNode)
+	s.nodes[start] = startNode
+
+	expansions := 0
+	for s.open.Len() > 0 {
+		current := heap.Pop(&s.open).(*Node)
+
+		// Check if we reached the goal
+		if current.Position == end {
+			return g.ReconstructPath(current)
+		}
+
+		if g.searchLimitReached(expansions) {
+			return nil, fmt.Errorf("%w after %d expansions between %s and %s", ErrSearchLimitExceeded, expansions, start, end)
+		}
+		expansions++
+
+		s.closed[current.Position] = true
+
+		// Check neighbors
+		s.neighbors = g.appendNeighbors(s.neighbors[:0], current.Position)
+		for _, neighborPos := range s.neighbors {
+			if s.closed[neighborPos] {
+				continue
+			}
+
+			// Calculate tentative cost
+			tentativeCost := current.Cost + g.CalculateEdgeCost(current.Position, neighborPos)
+
+			// Get or create neighbor node
+			neighbor, exists := s.nodes[neighborPos]
+			if !exists {
+				neighbor = s.newNode(neighborPos)
+				neighbor.Cost = math.Inf(1)
+				s.nodes[neighborPos] = neighbor
+			}
+
+			if tentativeCost < neighbor.Cost {
+				neighbor.Parent = current
+				neighbor.Cost = tentativeCost
+				neighbor.Priority = tentativeCost + weight*g.Heuristic(neighborPos, end)
+
+				if !exists {
+					heap.Push(&s.open, neighbor)
+				} else {
+					heap.Fix(&s.open, neighbor.Index)
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w between %s and %s", ErrNoPath, start, end)
+}
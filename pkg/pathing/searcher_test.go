@@ -0,0 +1,78 @@
+package pathing
+
+import (
+	"palbaseiq/pkg/types"
+	"slices"
+	"testing"
+)
+
+func TestPathSearcherReuse(t *testing.T) {
+	base, _, _ := benchmarkLayout(t)
+	graph := NewGraph(base)
+	searcher := NewPathSearcher(graph)
+
+	tests := []struct {
+		name       string
+		start, end types.Position
+		wantErr    bool
+	}{
+		{"across the base", types.Position{X: 0, Z: 0}, types.Position{X: 18, Z: 18}, false},
+		{"short hop", types.Position{X: 0, Z: 0}, types.Position{X: 2, Z: 0}, false},
+		{"onto a barrel", types.Position{X: 0, Z: 0}, types.Position{X: 1, Z: 1}, true},
+		{"after a failed search", types.Position{X: 0, Z: 19}, types.Position{X: 19, Z: 0}, false},
+		{"up a level", types.Position{X: 0, Z: 0}, types.Position{X: 12, Y: 3, Z: 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := searcher.FindPath(tt.start, tt.end)
+			want, wantErr := NewPathSearcher(graph).FindPath(tt.start, tt.end)
+			if (err != nil) != tt.wantErr || (wantErr != nil) != tt.wantErr {
+				t.Fatalf("FindPath errors %v and %v with a fresh searcher, want error %v", err, wantErr, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Cost != want.Cost || !slices.Equal(got.Nodes, want.Nodes) {
+				t.Errorf("reused searcher found %v (cost %v), fresh searcher %v (cost %v)", got.Nodes, got.Cost, want.Nodes, want.Cost)
+			}
+		})
+	}
+}
+
+// BenchmarkFindPathFreshSearcher allocates new buffers for every search, as
+// FindPath did before PathSearcher, for comparison with the benchmarks below
+func BenchmarkFindPathFreshSearcher(b *testing.B) {
+	base, _, _ := benchmarkLayout(b)
+	graph := NewGraph(base)
+	start, end := types.Position{X: 0, Z: 0}, types.Position{X: 18, Z: 18}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := NewPathSearcher(graph).FindPath(start, end); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindPathReusedSearcher(b *testing.B) {
+	base, _, _ := benchmarkLayout(b)
+	searcher := NewPathSearcher(NewGraph(base))
+	start, end := types.Position{X: 0, Z: 0}, types.Position{X: 18, Z: 18}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := searcher.FindPath(start, end); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindPathPooled(b *testing.B) {
+	base, _, _ := benchmarkLayout(b)
+	graph := NewGraph(base)
+	start, end := types.Position{X: 0, Z: 0}, types.Position{X: 18, Z: 18}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := graph.FindPath(start, end); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
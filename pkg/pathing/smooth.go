@@ -0,0 +1,142 @@
+package pathing
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+)
+
+// Simplify returns a copy of the path reduced to its waypoints: the start,
+// the end and every cell where the direction of travel changes. Collinear
+// runs of cells collapse into a single leg, so the route, its Distance and
+// its Cost are unchanged.
+func (p *Path) Simplify() *Path {
+	simplified := &Path{Distance: p.Distance, Cost: p.Cost}
+	if len(p.Nodes) <= 2 {
+		simplified.Nodes = append([]types.Position(nil), p.Nodes...)
+		return simplified
+	}
+
+	simplified.Nodes = []types.Position{p.Nodes[0]}
+	for i := 1; i < len(p.Nodes)-1; i++ {
+		if step(p.Nodes[i-1], p.Nodes[i]) != step(p.Nodes[i], p.Nodes[i+1]) {
+			simplified.Nodes = append(simplified.Nodes, p.Nodes[i])
+		}
+	}
+	simplified.Nodes = append(simplified.Nodes, p.Nodes[len(p.Nodes)-1])
+
+	return simplified
+}
+
+// step returns the offset from one cell to the next
+func step(from, to types.Position) types.Position {
+	return types.Position{X: to.X - from.X, Y: to.Y - from.Y, Z: to.Z - from.Z}
+}
+
+// SmoothPath simplifies the path (see Path.Simplify) and then drops every
+// waypoint that its neighbors can see each other past: a straight line
+// between them on the same level crosses only free cells and never squeezes
+// diagonally between two blocked ones. Legs may then run at any angle, and
+// the Distance and Cost of the smoothed path are recomputed for them, each
+// leg costing CalculateEdgeCost between its ends plus the obstacle penalty
+// of every cell it crosses on the way.
+func (g *Graph) SmoothPath(path *Path) *Path {
+	waypoints := path.Simplify().Nodes
+	if len(waypoints) <= 2 {
+		return g.pathThrough(waypoints)
+	}
+
+	smoothed := []types.Position{waypoints[0]}
+	anchor := 0
+	for anchor < len(waypoints)-1 {
+		next := anchor + 1
+		for next+1 < len(waypoints) && g.lineOfSight(waypoints[anchor], waypoints[next+1]) {
+			next++
+		}
+		smoothed = append(smoothed, waypoints[next])
+		anchor = next
+	}
+
+	return g.pathThrough(smoothed)
+}
+
+// pathThrough builds a path along straight legs between the waypoints. A
+// leg that changes level is a vertical run, as smoothing never joins levels,
+// and is priced by climbing it a cell at a time as FindPath would.
+func (g *Graph) pathThrough(waypoints []types.Position) *Path {
+	path := &Path{Nodes: waypoints}
+	for i := 1; i < len(waypoints); i++ {
+		from, to := waypoints[i-1], waypoints[i]
+		path.Distance += from.Distance(to)
+
+		if from.Y != to.Y {
+			dy := 1
+			if to.Y < from.Y {
+				dy = -1
+			}
+			for cell := from; cell != to; {
+				next := types.Position{X: to.X, Y: cell.Y + dy, Z: to.Z}
+				path.Cost += g.CalculateEdgeCost(cell, next)
+				cell = next
+			}
+			continue
+		}
+
+		path.Cost += g.CalculateEdgeCost(from, to)
+		if cells := lineCells(from, to); len(cells) > 2 {
+			for _, cell := range cells[1 : len(cells)-1] {
+				path.Cost += g.CalculateObstaclePenalty(cell)
+			}
+		}
+	}
+	return path
+}
+
+// lineOfSight reports whether a Pal can walk straight from one cell to the
+// other without leaving free cells
+func (g *Graph) lineOfSight(from, to types.Position) bool {
+	if from.Y != to.Y {
+		return false
+	}
+
+	cells := lineCells(from, to)
+	for i, cell := range cells {
+		if !g.Base.IsTraversable(cell) {
+			return false
+		}
+
+		// Never cut a corner between two cells that are both blocked
+		if i > 0 {
+			prev := cells[i-1]
+			if prev.X != cell.X && prev.Z != cell.Z {
+				side1 := types.Position{X: cell.X, Y: cell.Y, Z: prev.Z}
+				side2 := types.Position{X: prev.X, Y: cell.Y, Z: cell.Z}
+				if !g.Base.IsTraversable(side1) || !g.Base.IsTraversable(side2) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// lineCells returns the cells a straight line between the centers of two
+// cells on the same level passes through, from the first to the last,
+// sampled at a quarter of a cell
+func lineCells(from, to types.Position) []types.Position {
+	dx, dz := float64(to.X-from.X), float64(to.Z-from.Z)
+	samples := int(math.Ceil(math.Max(math.Abs(dx), math.Abs(dz)) * 4))
+
+	cells := []types.Position{from}
+	for s := 1; s <= samples; s++ {
+		t := float64(s) / float64(samples)
+		cell := types.Position{
+			X: int(math.Round(float64(from.X) + t*dx)),
+			Y: from.Y,
+			Z: int(math.Round(float64(from.Z) + t*dz)),
+		}
+		if cell != cells[len(cells)-1] {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
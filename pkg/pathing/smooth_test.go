@@ -0,0 +1,129 @@
+package pathing
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+	"slices"
+	"testing"
+)
+
+// lPath walks from (0,0,0) along X to (3,0,0) and then along Z to (3,0,3)
+func lPath() *Path {
+	return &Path{
+		Nodes: []types.Position{
+			{X: 0}, {X: 1}, {X: 2}, {X: 3},
+			{X: 3, Z: 1}, {X: 3, Z: 2}, {X: 3, Z: 3},
+		},
+		Distance: 6,
+		Cost:     6.5,
+	}
+}
+
+func TestPathSimplify(t *testing.T) {
+	tests := []struct {
+		name string
+		path *Path
+		want []types.Position
+	}{
+		{"L-shaped", lPath(), []types.Position{{X: 0}, {X: 3}, {X: 3, Z: 3}}},
+		{"straight", &Path{Nodes: []types.Position{{Z: 0}, {Z: 1}, {Z: 2}, {Z: 3}}}, []types.Position{{Z: 0}, {Z: 3}}},
+		{"staircase", &Path{Nodes: []types.Position{{X: 0}, {X: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}}},
+			[]types.Position{{X: 0}, {X: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}}},
+		{"single cell", &Path{Nodes: []types.Position{{X: 2}}}, []types.Position{{X: 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := slices.Clone(tt.path.Nodes)
+			got := tt.path.Simplify()
+			if !slices.Equal(got.Nodes, tt.want) {
+				t.Errorf("Simplify() = %v, want %v", got.Nodes, tt.want)
+			}
+			if got.Distance != tt.path.Distance || got.Cost != tt.path.Cost {
+				t.Errorf("Simplify() changed distance %v and cost %v to %v and %v", tt.path.Distance, tt.path.Cost, got.Distance, got.Cost)
+			}
+			if !slices.Equal(tt.path.Nodes, original) {
+				t.Error("Simplify() modified the original path")
+			}
+		})
+	}
+}
+
+func TestSmoothPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		blocked []types.Position
+		want    []types.Position
+	}{
+		{"open ground cuts the corner", nil, []types.Position{{X: 0}, {X: 3, Z: 3}}},
+		{"obstacle keeps the corner", []types.Position{{X: 1, Z: 1}}, []types.Position{{X: 0}, {X: 3}, {X: 3, Z: 3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := types.NewBase(4, 1, 4)
+			for _, pos := range tt.blocked {
+				place(t, base, GetNodeKey(pos), types.StructureNameWoodenBarrel, pos)
+			}
+			graph := NewGraph(base)
+
+			got := graph.SmoothPath(lPath())
+			if !slices.Equal(got.Nodes, tt.want) {
+				t.Fatalf("SmoothPath() = %v, want %v", got.Nodes, tt.want)
+			}
+
+			distance := 0.0
+			for i := 1; i < len(tt.want); i++ {
+				distance += tt.want[i-1].Distance(tt.want[i])
+			}
+			if math.Abs(got.Distance-distance) > 1e-9 {
+				t.Errorf("smoothed distance = %v, want %v", got.Distance, distance)
+			}
+			if got.Cost < got.Distance {
+				t.Errorf("smoothed cost %v is below its distance %v", got.Cost, got.Distance)
+			}
+		})
+	}
+}
+
+func TestSmoothPathClimbs(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end types.Position
+	}{
+		{"straight up", types.Position{}, types.Position{Y: 2}},
+		{"straight down", types.Position{X: 1, Y: 2, Z: 1}, types.Position{X: 1, Z: 1}},
+		{"up and across", types.Position{}, types.Position{X: 2, Y: 2, Z: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewGraph(types.NewBase(3, 3, 3))
+			path, err := graph.FindPath(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("FindPath: %v", err)
+			}
+
+			got := graph.SmoothPath(path)
+			if got.Nodes[0] != tt.start || got.Nodes[len(got.Nodes)-1] != tt.end {
+				t.Fatalf("SmoothPath() = %v, want it to run from %s to %s", got.Nodes, tt.start, tt.end)
+			}
+			if got.Cost > path.Cost+1e-9 {
+				t.Errorf("smoothed cost %v exceeds the path's %v", got.Cost, path.Cost)
+			}
+			if got.Cost < got.Distance {
+				t.Errorf("smoothed cost %v is below its distance %v", got.Cost, got.Distance)
+			}
+		})
+	}
+
+	// A purely vertical path has nothing to smooth and keeps its cost
+	graph := NewGraph(types.NewBase(3, 3, 3))
+	path, err := graph.FindPath(types.Position{}, types.Position{Y: 2})
+	if err != nil {
+		t.Fatalf("FindPath: %v", err)
+	}
+	if got := graph.SmoothPath(path); math.Abs(got.Cost-path.Cost) > 1e-9 {
+		t.Errorf("smoothed cost = %v, want %v", got.Cost, path.Cost)
+	}
+}
@@ -0,0 +1,106 @@
+package pathing
+
+import (
+	"math"
+	"palbaseiq/pkg/types"
+	"strings"
+)
+
+// TrafficHeatmap tallies how many of the given paths cross each cell
+func (g *Graph) TrafficHeatmap(paths []*Path) map[types.Position]int {
+	heatmap := make(map[types.Position]int)
+	for _, path := range paths {
+		if path == nil {
+			continue
+		}
+		for _, pos := range path.Nodes {
+			heatmap[pos]++
+		}
+	}
+	return heatmap
+}
+
+// PalboxPaths computes the cheapest path from the nearest Palbox to every
+// other item in the base, in item ID order, with a single search from all
+// Palboxes at once. Paths run between the free cells adjacent to each item,
+// since the item cells themselves are occupied. Items that cannot be reached
+// are skipped, and nil is returned if the base has no reachable Palbox.
+func (g *Graph) PalboxPaths() []*Path {
+	palboxes := g.Base.ItemsByName(types.StructureNamePalbox)
+	isPalbox := make(map[string]bool, len(palboxes))
+	var starts []types.Position
+	for _, palbox := range palboxes {
+		isPalbox[palbox.ID] = true
+		starts = append(starts, g.Base.AdjacentFreePositions(palbox)...)
+	}
+	if len(starts) == 0 {
+		return nil
+	}
+
+	items := g.Base.SortedItems()
+	access := make(map[string][]types.Position, len(items))
+	var targets []types.Position
+	for _, item := range items {
+		if !isPalbox[item.ID] {
+			access[item.ID] = g.Base.AdjacentFreePositions(item)
+			targets = append(targets, access[item.ID]...)
+		}
+	}
+	reachable := g.ShortestPathsFromAny(starts, targets)
+
+	var paths []*Path
+	for _, item := range items {
+		var best *Path
+		for _, pos := range access[item.ID] {
+			if path, ok := reachable[GetNodeKey(pos)]; ok && (best == nil || path.Cost < best.Cost) {
+				best = path
+			}
+		}
+		if best != nil {
+			paths = append(paths, best)
+		}
+	}
+
+	return paths
+}
+
+// PalboxTrafficHeatmap returns the traffic heatmap of all Palbox-to-item
+// paths
+func (g *Graph) PalboxTrafficHeatmap() map[types.Position]int {
+	return g.TrafficHeatmap(g.PalboxPaths())
+}
+
+// RenderTraffic draws layer y of the heatmap as ASCII art, one row per Z
+// and one column per X. Occupied cells are '#', untravelled free cells '.',
+// and travelled cells a digit 1-9 scaled to the busiest cell.
+func (g *Graph) RenderTraffic(heatmap map[types.Position]int, y int) string {
+	busiest := 0
+	for pos, count := range heatmap {
+		if pos.Y == y && count > busiest {
+			busiest = count
+		}
+	}
+
+	var sb strings.Builder
+	for z := 0; z < g.Base.Depth; z++ {
+		for x := 0; x < g.Base.Width; x++ {
+			pos := types.Position{X: x, Y: y, Z: z}
+			count := heatmap[pos]
+			switch {
+			case g.Base.IsPositionOccupied(pos):
+				sb.WriteByte('#')
+			case count == 0:
+				sb.WriteByte('.')
+			default:
+				level := int(math.Ceil(9 * float64(count) / float64(busiest)))
+				sb.WriteByte(byte('0' + level))
+			}
+			if x < g.Base.Width-1 {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
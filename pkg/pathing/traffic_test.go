@@ -0,0 +1,133 @@
+package pathing
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestTrafficHeatmap(t *testing.T) {
+	a := types.Position{X: 0}
+	b := types.Position{X: 1}
+	c := types.Position{X: 2}
+
+	tests := []struct {
+		name  string
+		paths []*Path
+		want  map[types.Position]int
+	}{
+		{"no paths", nil, map[types.Position]int{}},
+		{"nil path skipped", []*Path{nil, {Nodes: []types.Position{a, b}}}, map[types.Position]int{a: 1, b: 1}},
+		{"shared cells add up", []*Path{
+			{Nodes: []types.Position{a, b, c}},
+			{Nodes: []types.Position{b, c}},
+			{Nodes: []types.Position{c}},
+		}, map[types.Position]int{a: 1, b: 2, c: 3}},
+	}
+
+	graph := NewGraph(types.NewBase(3, 1, 1))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := graph.TrafficHeatmap(tt.paths)
+			if len(got) != len(tt.want) {
+				t.Fatalf("TrafficHeatmap() = %v, want %v", got, tt.want)
+			}
+			for pos, count := range tt.want {
+				if got[pos] != count {
+					t.Errorf("TrafficHeatmap()[%s] = %d, want %d", pos, got[pos], count)
+				}
+			}
+		})
+	}
+}
+
+func TestPalboxPaths(t *testing.T) {
+	t.Run("no palbox", func(t *testing.T) {
+		base := types.NewBase(4, 2, 4)
+		place(t, base, "barrel", types.StructureNameWoodenBarrel, types.Position{X: 3, Z: 3})
+		if paths := NewGraph(base).PalboxPaths(); paths != nil {
+			t.Errorf("PalboxPaths() = %v, want nil", paths)
+		}
+	})
+
+	t.Run("one path per reachable item", func(t *testing.T) {
+		base := types.NewBase(8, 2, 8)
+		palbox := place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+		near := place(t, base, "near", types.StructureNameWoodenBarrel, types.Position{X: 3, Z: 0})
+		far := place(t, base, "far", types.StructureNameWoodenBarrel, types.Position{X: 7, Z: 7})
+
+		graph := NewGraph(base)
+		paths := graph.PalboxPaths()
+		if len(paths) != 2 {
+			t.Fatalf("PalboxPaths() returned %d paths, want 2", len(paths))
+		}
+
+		// Paths come in item ID order: far, then near
+		for i, item := range []*types.Item{far, near} {
+			nodes := paths[i].Nodes
+			if !contains(base.AdjacentFreePositions(palbox), nodes[0]) {
+				t.Errorf("path to %s starts at %s, not beside the palbox", item.ID, nodes[0])
+			}
+			if !contains(base.AdjacentFreePositions(item), nodes[len(nodes)-1]) {
+				t.Errorf("path to %s ends at %s, not beside it", item.ID, nodes[len(nodes)-1])
+			}
+		}
+		if paths[0].Cost <= paths[1].Cost {
+			t.Errorf("far path cost %v is not above near path cost %v", paths[0].Cost, paths[1].Cost)
+		}
+
+		heatmap := graph.PalboxTrafficHeatmap()
+		total := 0
+		for _, count := range heatmap {
+			total += count
+		}
+		if want := len(paths[0].Nodes) + len(paths[1].Nodes); total != want {
+			t.Errorf("PalboxTrafficHeatmap() tallies %d crossings, want %d", total, want)
+		}
+	})
+
+	t.Run("walled off item skipped", func(t *testing.T) {
+		base := types.NewBase(6, 2, 3)
+		place(t, base, "palbox", types.StructureNamePalbox, types.Position{X: 0, Z: 0})
+		for z := 0; z < 3; z++ {
+			place(t, base, "wall_"+string(rune('a'+z)), types.StructureNameOuterWall, types.Position{X: 3, Z: z})
+		}
+		place(t, base, "barrel", types.StructureNameWoodenBarrel, types.Position{X: 5, Z: 1})
+
+		// Only the walls are reached, from the palbox side
+		paths := NewGraph(base).PalboxPaths()
+		if len(paths) != 3 {
+			t.Fatalf("PalboxPaths() returned %d paths, want 3", len(paths))
+		}
+		for _, path := range paths {
+			if end := path.Nodes[len(path.Nodes)-1]; end.X > 3 {
+				t.Errorf("path reached %s past the wall", end)
+			}
+		}
+	})
+}
+
+func TestRenderTraffic(t *testing.T) {
+	base := types.NewBase(3, 1, 2)
+	place(t, base, "barrel", types.StructureNameWoodenBarrel, types.Position{X: 2, Z: 1})
+
+	heatmap := map[types.Position]int{
+		{X: 0, Z: 0}: 4,
+		{X: 1, Z: 0}: 2,
+		{X: 0, Z: 1}: 1,
+		{X: 0, Y: 1}: 100, // other layers do not set the scale
+	}
+	want := "9 5 .\n" +
+		"3 . #\n"
+	if got := NewGraph(base).RenderTraffic(heatmap, 0); got != want {
+		t.Errorf("RenderTraffic() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func contains(positions []types.Position, pos types.Position) bool {
+	for _, candidate := range positions {
+		if candidate == pos {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package pathing
+
+import (
+	"palbaseiq/pkg/types"
+	"testing"
+)
+
+func TestCellWeightDetour(t *testing.T) {
+	start, end := types.Position{X: 0, Z: 4}, types.Position{X: 8, Z: 4}
+
+	tests := []struct {
+		name   string
+		weight float64
+		detour bool
+	}{
+		{"unweighted", 0, false},
+		{"below one is ignored", 0.5, false},
+		{"light band is crossed", 1.5, false},
+		{"heavy band is avoided", 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := NewGraph(types.NewBase(9, 1, 9))
+			band := make(map[types.Position]bool)
+			for z := 1; z <= 7; z++ {
+				band[types.Position{X: 4, Z: z}] = true
+			}
+			if tt.weight != 0 {
+				graph.CellWeight = make(map[types.Position]float64)
+				for cell := range band {
+					graph.CellWeight[cell] = tt.weight
+				}
+			}
+
+			path, err := graph.FindPath(start, end)
+			if err != nil {
+				t.Fatalf("FindPath: %v", err)
+			}
+			crossed := false
+			for _, node := range path.Nodes {
+				crossed = crossed || band[node]
+			}
+			if crossed == tt.detour {
+				t.Errorf("path %v crosses the band = %v, want %v", path.Nodes, crossed, !tt.detour)
+			}
+			if !tt.detour && path.Distance != 8 {
+				t.Errorf("Distance = %v, want the straight 8", path.Distance)
+			}
+		})
+	}
+}
+
+func TestAvoidCategory(t *testing.T) {
+	base := types.NewBase(9, 2, 9)
+	place(t, base, "wall", types.StructureNameStoneDefensiveWall, types.Position{X: 4, Z: 4})
+	place(t, base, "barrel", types.StructureNameWoodenBarrel, types.Position{X: 3, Z: 3})
+
+	graph := NewGraph(base)
+	graph.CellWeight = map[types.Position]float64{{X: 5, Z: 5}: 5}
+	graph.AvoidCategory(types.StructureCategoryFoundation, 1, 3)
+
+	tests := []struct {
+		name string
+		pos  types.Position
+		want float64
+	}{
+		{"beside the wall", types.Position{X: 5, Z: 4}, 3},
+		{"diagonal, upper level", types.Position{X: 3, Y: 1, Z: 5}, 3},
+		{"heavier weight kept", types.Position{X: 5, Z: 5}, 5},
+		{"wall itself", types.Position{X: 4, Z: 4}, 0},
+		{"occupied by another item", types.Position{X: 3, Z: 3}, 0},
+		{"out of radius", types.Position{X: 6, Z: 4}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graph.CellWeight[tt.pos]; got != tt.want {
+				t.Errorf("CellWeight[%s] = %v, want %v", tt.pos, got, tt.want)
+			}
+		})
+	}
+}
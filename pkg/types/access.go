@@ -0,0 +1,155 @@
+package types
+
+import "fmt"
+
+// Side names a face of an item relative to the way it faces. At rotation 0
+// an item faces +X, and each further 90 degrees turns it towards +Z, so its
+// front follows the glyphs drawn by RenderLayer.
+type Side int
+
+const (
+	SideNone Side = iota // no side must stay open
+	SideFront
+	SideBack
+	SideLeft
+	SideRight
+)
+
+// sideNames are the names sides are written as in JSON
+var sideNames = map[Side]string{
+	SideNone:  "none",
+	SideFront: "front",
+	SideBack:  "back",
+	SideLeft:  "left",
+	SideRight: "right",
+}
+
+// String returns the side's name
+func (s Side) String() string {
+	if name, ok := sideNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Side(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler, writing the side's name
+func (s Side) MarshalText() ([]byte, error) {
+	name, ok := sideNames[s]
+	if !ok {
+		return nil, fmt.Errorf("unknown side %d", int(s))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, reading a side's name
+func (s *Side) UnmarshalText(text []byte) error {
+	for side, name := range sideNames {
+		if name == string(text) {
+			*s = side
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown side %q", text)
+}
+
+// sideTurns gives the quarter turns from an item's facing to each side
+var sideTurns = map[Side]int{
+	SideFront: 0,
+	SideRight: 1,
+	SideBack:  2,
+	SideLeft:  3,
+}
+
+// RequiredAccessSide returns the side a Pal must approach the item from:
+// the item's own AccessSide, or its structure definition's when that is
+// SideNone
+func (i Item) RequiredAccessSide() Side {
+	if i.AccessSide != SideNone {
+		return i.AccessSide
+	}
+	def, err := i.Type.Definition()
+	if err != nil {
+		return SideNone
+	}
+	return def.AccessSide
+}
+
+// AccessCell returns the cell a Pal must stand on to use the item: the cell
+// beside the middle of the item's access face (see RequiredAccessSide), on
+// the item's bottom layer, with rotation taken into account. It reports
+// false when the item has no access side or the cell lies outside the base.
+func (b *Base) AccessCell(item *Item) (Position, bool) {
+	side := item.RequiredAccessSide()
+	turns, ok := sideTurns[side]
+	if !ok {
+		return Position{}, false
+	}
+
+	rotation := ((item.Rotation%360)+360)%360 + turns*90
+	bounds := item.EffectiveBounds()
+	cell := item.Position
+
+	switch rotation % 360 {
+	case 0:
+		cell.X += bounds.Width
+		cell.Z += bounds.Depth / 2
+	case 90:
+		cell.X += bounds.Width / 2
+		cell.Z += bounds.Depth
+	case 180:
+		cell.X--
+		cell.Z += bounds.Depth / 2
+	default:
+		cell.X += bounds.Width / 2
+		cell.Z--
+	}
+
+	if !b.IsPositionValid(cell) {
+		return Position{}, false
+	}
+	return cell, true
+}
+
+// AccessFaceCells returns the cells beside the whole of the item's access
+// face, on the item's bottom layer, with rotation taken into account: every
+// cell a Pal could stand on to use the item. Cells outside the base are
+// left out, and an item without an access side has none.
+func (b *Base) AccessFaceCells(item *Item) []Position {
+	side := item.RequiredAccessSide()
+	turns, ok := sideTurns[side]
+	if !ok {
+		return nil
+	}
+
+	rotation := ((item.Rotation%360)+360)%360 + turns*90
+	bounds := item.EffectiveBounds()
+	pos := item.Position
+
+	var cells []Position
+	switch rotation % 360 {
+	case 0:
+		for z := 0; z < bounds.Depth; z++ {
+			cells = append(cells, Position{X: pos.X + bounds.Width, Y: pos.Y, Z: pos.Z + z})
+		}
+	case 90:
+		for x := 0; x < bounds.Width; x++ {
+			cells = append(cells, Position{X: pos.X + x, Y: pos.Y, Z: pos.Z + bounds.Depth})
+		}
+	case 180:
+		for z := 0; z < bounds.Depth; z++ {
+			cells = append(cells, Position{X: pos.X - 1, Y: pos.Y, Z: pos.Z + z})
+		}
+	default:
+		for x := 0; x < bounds.Width; x++ {
+			cells = append(cells, Position{X: pos.X + x, Y: pos.Y, Z: pos.Z - 1})
+		}
+	}
+
+	inside := cells[:0]
+	for _, cell := range cells {
+		if b.IsPositionValid(cell) {
+			inside = append(inside, cell)
+		}
+	}
+	return inside
+}
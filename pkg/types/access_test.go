@@ -0,0 +1,46 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAccessCell(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     StructureName
+		pos      Position
+		rotation int
+		want     Position
+		wantOK   bool
+	}{
+		{"front at 0 degrees", StructureNameWorkbench, Position{X: 2, Z: 2}, 0, Position{X: 4, Z: 2}, true},
+		{"front at 90 degrees", StructureNameWorkbench, Position{X: 2, Z: 2}, 90, Position{X: 2, Z: 4}, true},
+		{"front at 180 degrees", StructureNameWorkbench, Position{X: 2, Z: 2}, 180, Position{X: 1, Z: 2}, true},
+		{"front at 270 degrees", StructureNameWorkbench, Position{X: 2, Z: 2}, 270, Position{X: 2, Z: 1}, true},
+		{"front outside the base", StructureNameWorkbench, Position{X: 0, Z: 0}, 180, Position{}, false},
+		{"no access side", StructureNameWoodenBarrel, Position{X: 2, Z: 2}, 0, Position{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(6, 1, 6)
+			item := NewItem("item", tt.item)
+			item.Position, item.Rotation = tt.pos, tt.rotation
+
+			got, ok := base.AccessCell(item)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("AccessCell() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+
+			// The access cell is one of the face cells
+			face := base.AccessFaceCells(item)
+			if ok && !slices.Contains(face, got) {
+				t.Errorf("AccessFaceCells() = %v does not contain the access cell", face)
+			}
+			if !ok && len(face) != 0 {
+				t.Errorf("AccessFaceCells() = %v, want none", face)
+			}
+		})
+	}
+}
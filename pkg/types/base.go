@@ -3,11 +3,15 @@ package types
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 )
 
 // Position represents a 3D coordinate in the base
 type Position struct {
-	X, Y, Z int
+	X int `json:"x"`
+	Y int `json:"y"`
+	Z int `json:"z"`
 }
 
 // String returns a string representation of the position
@@ -28,9 +32,50 @@ func (p Position) ManhattanDistance(other Position) int {
 	return abs(p.X-other.X) + abs(p.Y-other.Y) + abs(p.Z-other.Z)
 }
 
+// ChebyshevDistance calculates the Chebyshev distance between two positions,
+// the number of steps needed when diagonal moves cost the same as straight
+// ones
+func (p Position) ChebyshevDistance(other Position) int {
+	return max(abs(p.X-other.X), abs(p.Y-other.Y), abs(p.Z-other.Z))
+}
+
+// OctileDistance calculates the octile distance between two positions, the
+// shortest path length when straight moves cost 1, planar diagonals cost
+// sqrt(2) and full 3D diagonals cost sqrt(3)
+func (p Position) OctileDistance(other Position) float64 {
+	d := []int{abs(p.X - other.X), abs(p.Y - other.Y), abs(p.Z - other.Z)}
+	sort.Sort(sort.Reverse(sort.IntSlice(d)))
+
+	// Move diagonally in 3D while all axes differ, then in 2D, then straight
+	return float64(d[2])*math.Sqrt(3) +
+		float64(d[1]-d[2])*math.Sqrt2 +
+		float64(d[0]-d[1])
+}
+
+// sortPositions orders positions by X, then Y, then Z, matching the order
+// in which the grid is scanned
+func sortPositions(positions []Position) {
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].Less(positions[j])
+	})
+}
+
+// Less orders positions by X, then Y, then Z
+func (p Position) Less(other Position) bool {
+	if p.X != other.X {
+		return p.X < other.X
+	}
+	if p.Y != other.Y {
+		return p.Y < other.Y
+	}
+	return p.Z < other.Z
+}
+
 // BoundingBox represents the dimensions of an item
 type BoundingBox struct {
-	Width, Height, Depth int
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	Depth  int `json:"depth"`
 }
 
 // Volume returns the total volume of the bounding box
@@ -57,8 +102,23 @@ const (
 	ItemTypeBreedingFarm       ItemType = "breeding_farm"
 	ItemTypeIncubator          ItemType = "incubator"
 	ItemTypePalSphereWorkbench ItemType = "pal_sphere_workbench"
+
+	// Environmental features are part of the terrain rather than player
+	// structures. They are never moved by the optimizer.
+	ItemTypeTree  ItemType = "tree"
+	ItemTypeRock  ItemType = "rock"
+	ItemTypeWater ItemType = "water"
 )
 
+// IsEnvironmental reports whether the item type is a natural terrain feature
+func (t ItemType) IsEnvironmental() bool {
+	switch t {
+	case ItemTypeTree, ItemTypeRock, ItemTypeWater:
+		return true
+	}
+	return false
+}
+
 // Item represents a placeable item in the base
 type Item struct {
 	ID       string
@@ -67,6 +127,43 @@ type Item struct {
 	Bounds   BoundingBox
 	Rotation int // 0, 90, 180, 270 degrees
 	Priority int // Higher priority items are placed first
+
+	// Environmental marks a fixed obstacle (tree, rock, water) that stays
+	// at its Position. It blocks placement and pathing like any other item
+	// but is never relocated by the optimizer.
+	Environmental bool
+
+	// CandidatePositions restricts placement to a fixed set of anchor
+	// points. When empty, any free position is considered.
+	CandidatePositions []Position
+
+	// AccessSide is the face a Pal must approach the item from, such as a
+	// workbench's front, which has to stay open. SideNone falls back to the
+	// structure definition's AccessSide.
+	AccessSide Side
+
+	// Exposure requires the item to stand indoors or outdoors. ExposureAny
+	// falls back to the structure definition's Exposure.
+	Exposure Exposure
+
+	// Passable lets Pals walk through the item's cells, like a door, even
+	// though nothing else can be placed there. Structures whose definition
+	// is Passable are passable regardless.
+	Passable bool
+}
+
+// IsFixed reports whether the item must stay at its current position
+func (i Item) IsFixed() bool {
+	return i.Environmental || i.Type.IsEnvironmental()
+}
+
+// IsPassable reports whether the item can be walked through
+func (i Item) IsPassable() bool {
+	if i.Passable {
+		return true
+	}
+	def, err := i.Type.Definition()
+	return err == nil && def.Passable
 }
 
 // String returns a string representation of the item
@@ -74,34 +171,85 @@ func (i Item) String() string {
 	return fmt.Sprintf("%s[%s] at %s", i.Type, i.ID, i.Position)
 }
 
+// EffectiveBounds returns the item's bounds after rotation. Rotating by 90
+// or 270 degrees about the vertical axis swaps Width and Depth; 0 and 180
+// leave the bounds unchanged.
+func (i Item) EffectiveBounds() BoundingBox {
+	return i.boundsAt(i.Rotation)
+}
+
+// boundsAt returns the item's bounds as if it were turned to rotation
+func (i Item) boundsAt(rotation int) BoundingBox {
+	if ((rotation%360)+360)%180 == 90 {
+		return BoundingBox{Width: i.Bounds.Depth, Height: i.Bounds.Height, Depth: i.Bounds.Width}
+	}
+	return i.Bounds
+}
+
 // GetOccupiedPositions returns all positions occupied by this item
 func (i Item) GetOccupiedPositions() []Position {
-	positions := make([]Position, 0, i.Bounds.Volume())
-
-	for x := 0; x < i.Bounds.Width; x++ {
-		for y := 0; y < i.Bounds.Height; y++ {
-			for z := 0; z < i.Bounds.Depth; z++ {
-				positions = append(positions, Position{
-					X: i.Position.X + x,
-					Y: i.Position.Y + y,
-					Z: i.Position.Z + z,
-				})
+	return i.OccupiedAt(i.Position, i.Rotation)
+}
+
+// OccupiedAt returns the cells the item would occupy anchored at pos and
+// turned to rotation, without changing the item. It suits previews such as
+// highlighting an item's footprint while it is dragged.
+func (i Item) OccupiedAt(pos Position, rotation int) []Position {
+	positions := make([]Position, 0, i.boundsAt(rotation).Volume())
+	i.eachOccupiedAt(pos, rotation, func(cell Position) bool {
+		positions = append(positions, cell)
+		return true
+	})
+	return positions
+}
+
+// ForEachOccupied calls fn with every position the item occupies, in the
+// order of GetOccupiedPositions, without allocating a slice. Hot paths such
+// as placement checks use it; GetOccupiedPositions remains for callers that
+// want the cells at hand.
+func (i Item) ForEachOccupied(fn func(Position)) {
+	i.eachOccupiedAt(i.Position, i.Rotation, func(cell Position) bool {
+		fn(cell)
+		return true
+	})
+}
+
+// eachOccupiedAt calls fn with every cell the item would occupy at pos and
+// rotation until fn returns false, and reports whether it visited them all
+func (i Item) eachOccupiedAt(pos Position, rotation int, fn func(Position) bool) bool {
+	bounds := i.boundsAt(rotation)
+	for x := 0; x < bounds.Width; x++ {
+		for y := 0; y < bounds.Height; y++ {
+			for z := 0; z < bounds.Depth; z++ {
+				if !fn(Position{X: pos.X + x, Y: pos.Y + y, Z: pos.Z + z}) {
+					return false
+				}
 			}
 		}
 	}
+	return true
+}
 
-	return positions
+// occupies reports whether the item, as placed, covers the cell
+func (i Item) occupies(cell Position) bool {
+	bounds := i.EffectiveBounds()
+	return cell.X >= i.Position.X && cell.X < i.Position.X+bounds.Width &&
+		cell.Y >= i.Position.Y && cell.Y < i.Position.Y+bounds.Height &&
+		cell.Z >= i.Position.Z && cell.Z < i.Position.Z+bounds.Depth
 }
 
-// Intersects checks if this item intersects with another item
+// Intersects checks if this item intersects with another item, taking each
+// item's rotation into account
 func (i Item) Intersects(other Item) bool {
+	mine, theirs := i.EffectiveBounds(), other.EffectiveBounds()
+
 	// Check if bounding boxes overlap
-	return i.Position.X < other.Position.X+other.Bounds.Width &&
-		i.Position.X+i.Bounds.Width > other.Position.X &&
-		i.Position.Y < other.Position.Y+other.Bounds.Height &&
-		i.Position.Y+i.Bounds.Height > other.Position.Y &&
-		i.Position.Z < other.Position.Z+other.Bounds.Depth &&
-		i.Position.Z+i.Bounds.Depth > other.Position.Z
+	return i.Position.X < other.Position.X+theirs.Width &&
+		i.Position.X+mine.Width > other.Position.X &&
+		i.Position.Y < other.Position.Y+theirs.Height &&
+		i.Position.Y+mine.Height > other.Position.Y &&
+		i.Position.Z < other.Position.Z+theirs.Depth &&
+		i.Position.Z+mine.Depth > other.Position.Z
 }
 
 // Base represents the entire base layout
@@ -110,26 +258,51 @@ type Base struct {
 	Height int
 	Depth  int
 	Items  map[string]*Item
-	Grid   [][][]bool // 3D grid representing occupied spaces
+
+	// ClearanceCells requires items of a type to keep a ring of free cells
+	// around their footprint in the X/Z plane when they are placed
+	ClearanceCells map[ItemType]int
+
+	// unbuildable marks in-bounds cells that lie outside the usable base
+	// area. They are never free for placement or pathing.
+	unbuildable map[Position]bool
+
+	// reserved marks free cells kept clear for walkways. They stay walkable
+	// but reject placement.
+	reserved map[Position]bool
+
+	// passable marks occupied cells whose item can be walked through, such
+	// as doors. They still block placement.
+	passable map[Position]bool
+
+	// grid has a bit per cell, set while an item occupies it. Use
+	// IsPositionOccupied to read it.
+	grid bitset
+
+	// index buckets placed items for ItemsNear queries
+	index *spatialIndex
+
+	// history records edits for Undo and Redo once EnableHistory is called
+	history *history
+
+	// version counts changes to which cells are occupied, passable or
+	// buildable. Use Version to read it.
+	version uint64
 }
 
 // NewBase creates a new base with the specified dimensions
 func NewBase(width, height, depth int) *Base {
-	// Initialize 3D grid
-	grid := make([][][]bool, width)
-	for x := range grid {
-		grid[x] = make([][]bool, height)
-		for y := range grid[x] {
-			grid[x][y] = make([]bool, depth)
-		}
-	}
-
 	return &Base{
-		Width:  width,
-		Height: height,
-		Depth:  depth,
-		Items:  make(map[string]*Item),
-		Grid:   grid,
+		Width:          width,
+		Height:         height,
+		Depth:          depth,
+		Items:          make(map[string]*Item),
+		grid:           newBitset(width * height * depth),
+		ClearanceCells: make(map[ItemType]int),
+		unbuildable:    make(map[Position]bool),
+		passable:       make(map[Position]bool),
+		reserved:       make(map[Position]bool),
+		index:          newSpatialIndex(),
 	}
 }
 
@@ -140,37 +313,228 @@ func (b *Base) IsPositionValid(pos Position) bool {
 		pos.Z >= 0 && pos.Z < b.Depth
 }
 
-// IsPositionOccupied checks if a position is occupied by any item
+// SetBuildable marks an in-bounds cell as part of the usable base area or
+// not. Non-buildable cells behave as occupied for placement and pathing.
+func (b *Base) SetBuildable(pos Position, buildable bool) {
+	if !b.IsPositionValid(pos) {
+		return
+	}
+	if buildable {
+		delete(b.unbuildable, pos)
+	} else {
+		b.unbuildable[pos] = true
+	}
+	b.version++
+}
+
+// IsBuildable checks if a position is inside the base and inside the usable
+// base area, regardless of whether an item occupies it
+func (b *Base) IsBuildable(pos Position) bool {
+	return b.IsPositionValid(pos) && !b.unbuildable[pos]
+}
+
+// Reserve marks the cell as reserved for a walkway. Reserved cells remain
+// walkable but CanPlaceItem rejects any item covering them.
+func (b *Base) Reserve(pos Position) {
+	if b.IsPositionValid(pos) {
+		b.reserved[pos] = true
+	}
+}
+
+// IsReserved reports whether the cell is reserved for a walkway
+func (b *Base) IsReserved(pos Position) bool {
+	return b.reserved[pos]
+}
+
+// ClearReservations releases every reserved cell
+func (b *Base) ClearReservations() {
+	b.reserved = make(map[Position]bool)
+}
+
+// IsPositionBlocked reports whether movement through the position is
+// impossible. It matches IsPositionOccupied except that cells of passable
+// items, such as doors, can be walked through.
+func (b *Base) IsPositionBlocked(pos Position) bool {
+	return b.IsPositionOccupied(pos) && !b.passable[pos]
+}
+
+// IsTraversable reports whether a Pal can stand on or move through the
+// position. It is the single walkability test pathing consults: cells
+// outside the base or the buildable area and cells of solid items are not
+// traversable, while free cells, reserved walkways and cells of passable
+// items such as doors are.
+func (b *Base) IsTraversable(pos Position) bool {
+	return !b.IsPositionBlocked(pos)
+}
+
+// setCells marks the item's cells as occupied or free, tracking which
+// occupied cells are passable
+func (b *Base) setCells(item *Item, occupied bool) {
+	b.version++
+	passable := occupied && item.IsPassable()
+	item.ForEachOccupied(func(pos Position) {
+		if !b.IsPositionValid(pos) {
+			return
+		}
+		b.grid.set(b.cellIndex(pos), occupied)
+		if passable {
+			b.passable[pos] = true
+		} else {
+			delete(b.passable, pos)
+		}
+	})
+}
+
+// Version returns a counter that grows whenever the cells of the base
+// change, by placing, moving or removing items or changing the buildable
+// area, so callers can tell whether something derived from the cells, such
+// as a distance field, is still current. Reservations, which leave cells
+// walkable, do not count as changes.
+func (b *Base) Version() uint64 {
+	return b.version
+}
+
+// IsPositionOccupied checks if a position is occupied by any item or lies
+// outside the buildable area
 func (b *Base) IsPositionOccupied(pos Position) bool {
 	if !b.IsPositionValid(pos) {
 		return true // Invalid positions are considered occupied
 	}
-	return b.Grid[pos.X][pos.Y][pos.Z]
+	return b.grid.get(b.cellIndex(pos)) || b.unbuildable[pos]
 }
 
 // CanPlaceItem checks if an item can be placed at the given position
 func (b *Base) CanPlaceItem(item *Item) bool {
-	// Check if all positions the item would occupy are valid and unoccupied
-	for _, pos := range item.GetOccupiedPositions() {
-		if b.IsPositionOccupied(pos) {
+	// Check if all positions the item would occupy are valid, unoccupied
+	// and not reserved for walkways
+	free := item.eachOccupiedAt(item.Position, item.Rotation, func(pos Position) bool {
+		return !b.IsPositionOccupied(pos) && !b.reserved[pos]
+	})
+	if !free {
+		return false
+	}
+
+	// Border cells inside the clearance distance must be free as well.
+	// Cells outside the base do not count against the item.
+	return b.eachClearanceCellAt(item, item.Position, item.Rotation, func(pos Position) bool {
+		return !b.IsPositionValid(pos) || !b.IsPositionOccupied(pos)
+	})
+}
+
+// CanPlaceAt reports whether the item could be placed anchored at pos and
+// turned to rotation, without changing the item or the base. The cells of
+// the item itself, when it is already placed, count as free, so an item
+// being dragged can be checked against its own new pose.
+func (b *Base) CanPlaceAt(item *Item, pos Position, rotation int) bool {
+	placed, isPlaced := b.Items[item.ID]
+	own := func(cell Position) bool {
+		return isPlaced && placed.occupies(cell)
+	}
+
+	free := item.eachOccupiedAt(pos, rotation, func(cell Position) bool {
+		if !b.IsPositionValid(cell) || b.unbuildable[cell] || b.reserved[cell] {
 			return false
 		}
+		return !b.IsPositionOccupied(cell) || own(cell)
+	})
+	if !free {
+		return false
+	}
+
+	return b.eachClearanceCellAt(item, pos, rotation, func(cell Position) bool {
+		return !b.IsPositionValid(cell) || !b.IsPositionOccupied(cell) || own(cell)
+	})
+}
+
+// ClearanceBorder returns the cells surrounding the item's footprint within
+// its type's clearance distance. The border extends along X and Z at every
+// level the item occupies.
+func (b *Base) ClearanceBorder(item *Item) []Position {
+	return b.clearanceBorderAt(item, item.Position, item.Rotation)
+}
+
+// clearanceBorderAt is ClearanceBorder for the item anchored at pos and
+// turned to rotation
+func (b *Base) clearanceBorderAt(item *Item, pos Position, rotation int) []Position {
+	var border []Position
+	b.eachClearanceCellAt(item, pos, rotation, func(cell Position) bool {
+		border = append(border, cell)
+		return true
+	})
+	return border
+}
+
+// eachClearanceCellAt calls fn with every cell of clearanceBorderAt, in the
+// same order, until fn returns false, and reports whether it visited them
+// all
+func (b *Base) eachClearanceCellAt(item *Item, pos Position, rotation int, fn func(Position) bool) bool {
+	clearance := b.ClearanceCells[item.Type]
+	if clearance <= 0 {
+		return true
+	}
+
+	bounds := item.boundsAt(rotation)
+	for x := -clearance; x < bounds.Width+clearance; x++ {
+		for z := -clearance; z < bounds.Depth+clearance; z++ {
+			if x >= 0 && x < bounds.Width && z >= 0 && z < bounds.Depth {
+				continue // inside the footprint
+			}
+			for y := 0; y < bounds.Height; y++ {
+				if !fn(Position{X: pos.X + x, Y: pos.Y + y, Z: pos.Z + z}) {
+					return false
+				}
+			}
+		}
 	}
 	return true
 }
 
+// CheckFits returns an error if the item, at its current rotation, is
+// larger than the base along any axis, meaning it can never be placed no
+// matter what else is in the base
+func (b *Base) CheckFits(item *Item) error {
+	bounds := item.EffectiveBounds()
+	axes := []struct {
+		name       string
+		size, base int
+	}{
+		{"X", bounds.Width, b.Width},
+		{"Y", bounds.Height, b.Height},
+		{"Z", bounds.Depth, b.Depth},
+	}
+
+	for _, axis := range axes {
+		if axis.size > axis.base {
+			return fmt.Errorf("item %s (%dx%dx%d) cannot fit in base %dx%dx%d along axis %s",
+				item.ID, bounds.Width, bounds.Height, bounds.Depth,
+				b.Width, b.Height, b.Depth, axis.name)
+		}
+	}
+
+	return nil
+}
+
 // PlaceItem places an item in the base
 func (b *Base) PlaceItem(item *Item) error {
+	if item.Bounds.Width <= 0 || item.Bounds.Height <= 0 || item.Bounds.Depth <= 0 {
+		return fmt.Errorf("item %s has non-positive bounds %dx%dx%d", item.ID,
+			item.Bounds.Width, item.Bounds.Height, item.Bounds.Depth)
+	}
+
+	if err := b.CheckFits(item); err != nil {
+		return err
+	}
+
 	if !b.CanPlaceItem(item) {
 		return fmt.Errorf("cannot place item %s at position %s", item.ID, item.Position)
 	}
 
 	// Mark all occupied positions as occupied
-	for _, pos := range item.GetOccupiedPositions() {
-		b.Grid[pos.X][pos.Y][pos.Z] = true
-	}
+	b.setCells(item, true)
 
 	b.Items[item.ID] = item
+	b.index.insert(item)
+	b.record(operation{kind: operationPlace, item: item})
 	return nil
 }
 
@@ -182,11 +546,73 @@ func (b *Base) RemoveItem(itemID string) error {
 	}
 
 	// Mark all occupied positions as unoccupied
-	for _, pos := range item.GetOccupiedPositions() {
-		b.Grid[pos.X][pos.Y][pos.Z] = false
-	}
+	b.setCells(item, false)
 
 	delete(b.Items, itemID)
+	b.index.remove(item, item.Position)
+	b.record(operation{kind: operationRemove, item: item})
+	return nil
+}
+
+// RemoveByCategory removes every placed item whose structure belongs to the
+// category, freeing its cells, and returns how many items were removed.
+// Fixed features are never removed.
+func (b *Base) RemoveByCategory(category StructureCategory) int {
+	removed := 0
+	for _, item := range b.ItemsByCategory(category) {
+		if item.IsFixed() {
+			continue
+		}
+		if err := b.RemoveItem(item.ID); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
+
+// MoveItem moves an item to a new position and rotation as one operation.
+// If the item does not fit at the new pose the base is left exactly as it
+// was and an error is returned.
+func (b *Base) MoveItem(id string, newPos Position, newRotation int) error {
+	item, exists := b.Items[id]
+	if !exists {
+		return fmt.Errorf("item %s not found", id)
+	}
+	if newRotation%90 != 0 {
+		return fmt.Errorf("invalid rotation %d for item %s", newRotation, id)
+	}
+
+	// Free the old cells so the item does not collide with itself
+	b.setCells(item, false)
+
+	moved := *item
+	moved.Position = newPos
+	moved.Rotation = newRotation
+	if !b.CanPlaceItem(&moved) {
+		// Roll back to the original cells
+		b.setCells(item, true)
+		return fmt.Errorf("cannot move item %s to position %s", id, newPos)
+	}
+
+	op := operation{
+		kind:         operationMove,
+		item:         item,
+		fromPosition: item.Position,
+		fromRotation: item.Rotation,
+		toPosition:   newPos,
+		toRotation:   newRotation,
+	}
+
+	b.index.remove(item, item.Position)
+	item.Position = newPos
+	item.Rotation = newRotation
+	b.index.insert(item)
+	b.setCells(item, true)
+
+	// Moving an item onto its own pose changes nothing worth undoing
+	if op.fromPosition != newPos || op.fromRotation != newRotation {
+		b.record(op)
+	}
 	return nil
 }
 
@@ -202,14 +628,91 @@ func (b *Base) GetItemAtPosition(pos Position) *Item {
 	return nil
 }
 
+// SortedItems returns the placed items in a fixed order, highest priority
+// first and then by ID. Ranging over Items visits them in random order, so
+// anything that sums floating-point values per item should use this to get
+// the same result on every run.
+func (b *Base) SortedItems() []*Item {
+	items := make([]*Item, 0, len(b.Items))
+	for _, item := range b.Items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Priority != items[j].Priority {
+			return items[i].Priority > items[j].Priority
+		}
+		return items[i].ID < items[j].ID
+	})
+	return items
+}
+
+// CoincidentItems groups items that share exactly the same Position, which
+// usually points to a data-entry mistake in an imported layout. Only groups
+// of two or more items are returned, each sorted by ID, and the groups are
+// ordered by their first ID.
+func (b *Base) CoincidentItems() [][]*Item {
+	byPosition := make(map[Position][]*Item)
+	for _, item := range b.Items {
+		byPosition[item.Position] = append(byPosition[item.Position], item)
+	}
+
+	var groups [][]*Item
+	for _, group := range byPosition {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].ID < group[j].ID
+		})
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i][0].ID < groups[j][0].ID
+	})
+
+	return groups
+}
+
+// FootprintBounds returns the inclusive corners of the box enclosing every
+// cell occupied by a movable item. Fixed terrain is left out, since it is
+// not part of what gets built. Without movable items min is the origin and
+// max lies one below it on every axis, so the extent max-min+1 is zero.
+func (b *Base) FootprintBounds() (Position, Position) {
+	low, high := Position{}, Position{X: -1, Y: -1, Z: -1}
+	empty := true
+	for _, item := range b.Items {
+		if item.IsFixed() {
+			continue
+		}
+
+		bounds := item.EffectiveBounds()
+		itemLow := item.Position
+		itemHigh := Position{
+			X: itemLow.X + bounds.Width - 1,
+			Y: itemLow.Y + bounds.Height - 1,
+			Z: itemLow.Z + bounds.Depth - 1,
+		}
+		if empty {
+			low, high, empty = itemLow, itemHigh, false
+			continue
+		}
+		low = Position{X: min(low.X, itemLow.X), Y: min(low.Y, itemLow.Y), Z: min(low.Z, itemLow.Z)}
+		high = Position{X: max(high.X, itemHigh.X), Y: max(high.Y, itemHigh.Y), Z: max(high.Z, itemHigh.Z)}
+	}
+
+	return low, high
+}
+
 // GetOccupiedPositions returns all occupied positions in the base
 func (b *Base) GetOccupiedPositions() []Position {
 	var positions []Position
 	for x := 0; x < b.Width; x++ {
 		for y := 0; y < b.Height; y++ {
 			for z := 0; z < b.Depth; z++ {
-				if b.Grid[x][y][z] {
-					positions = append(positions, Position{X: x, Y: y, Z: z})
+				pos := Position{X: x, Y: y, Z: z}
+				if b.grid.get(b.cellIndex(pos)) {
+					positions = append(positions, pos)
 				}
 			}
 		}
@@ -217,19 +720,38 @@ func (b *Base) GetOccupiedPositions() []Position {
 	return positions
 }
 
-// GetFreePositions returns all free positions in the base
+// GetFreePositions returns all free, buildable positions in the base
 func (b *Base) GetFreePositions() []Position {
-	var positions []Position
+	return b.FreePositionsInto(nil)
+}
+
+// FreePositionsInto appends the free, buildable positions to buf[:0] and
+// returns the result, reusing buf's storage when it is large enough
+func (b *Base) FreePositionsInto(buf []Position) []Position {
+	positions := buf[:0]
+	b.EachFreePosition(func(pos Position) bool {
+		positions = append(positions, pos)
+		return true
+	})
+	return positions
+}
+
+// EachFreePosition calls fn for every free, buildable position, in the same
+// order as GetFreePositions, without allocating a slice. It stops early
+// when fn returns false.
+func (b *Base) EachFreePosition(fn func(Position) bool) {
 	for x := 0; x < b.Width; x++ {
 		for y := 0; y < b.Height; y++ {
 			for z := 0; z < b.Depth; z++ {
-				if !b.Grid[x][y][z] {
-					positions = append(positions, Position{X: x, Y: y, Z: z})
+				pos := Position{X: x, Y: y, Z: z}
+				if !b.grid.get(b.cellIndex(pos)) && !b.unbuildable[pos] {
+					if !fn(pos) {
+						return
+					}
 				}
 			}
 		}
 	}
-	return positions
 }
 
 // GetOccupancyPercentage returns the percentage of occupied space
@@ -239,35 +761,122 @@ func (b *Base) GetOccupancyPercentage() float64 {
 	return float64(occupied) / float64(total) * 100
 }
 
-// Clone creates a deep copy of the base
-func (b *Base) Clone() *Base {
-	clone := NewBase(b.Width, b.Height, b.Depth)
+// SpaceStats summarizes how the cells of a base are used. Every cell is
+// counted in exactly one of Occupied, Walkable or KeepOut.
+type SpaceStats struct {
+	Total    int // all cells in the base
+	Occupied int // cells covered by an item
+	Walkable int // free cells Pals can move through
+	KeepOut  int // free cells that are not buildable or reserved as clearance
+}
 
-	// Copy items
-	for id, item := range b.Items {
-		cloneItem := &Item{
-			ID:       item.ID,
-			Type:     item.Type,
-			Position: item.Position,
-			Bounds:   item.Bounds,
-			Rotation: item.Rotation,
-			Priority: item.Priority,
+// SpaceStats returns the cell breakdown of the base in a single grid pass
+func (b *Base) SpaceStats() SpaceStats {
+	keepOut := make(map[Position]bool)
+	for _, item := range b.Items {
+		for _, pos := range b.ClearanceBorder(item) {
+			if b.IsPositionValid(pos) {
+				keepOut[pos] = true
+			}
 		}
-		clone.Items[id] = cloneItem
 	}
 
-	// Copy grid
+	stats := SpaceStats{Total: b.Width * b.Height * b.Depth}
 	for x := 0; x < b.Width; x++ {
 		for y := 0; y < b.Height; y++ {
 			for z := 0; z < b.Depth; z++ {
-				clone.Grid[x][y][z] = b.Grid[x][y][z]
+				pos := Position{X: x, Y: y, Z: z}
+				switch {
+				case b.grid.get(b.cellIndex(pos)):
+					stats.Occupied++
+				case b.unbuildable[pos] || keepOut[pos]:
+					stats.KeepOut++
+				default:
+					stats.Walkable++
+				}
 			}
 		}
 	}
 
+	return stats
+}
+
+// Clone creates a deep copy of the base
+func (b *Base) Clone() *Base {
+	clone := NewBase(b.Width, b.Height, b.Depth)
+
+	for itemType, clearance := range b.ClearanceCells {
+		clone.ClearanceCells[itemType] = clearance
+	}
+	for pos := range b.unbuildable {
+		clone.unbuildable[pos] = true
+	}
+	for pos := range b.passable {
+		clone.passable[pos] = true
+	}
+	for pos := range b.reserved {
+		clone.reserved[pos] = true
+	}
+
+	// Copy items
+	for id, item := range b.Items {
+		cloneItem := *item
+		clone.Items[id] = &cloneItem
+		clone.index.insert(&cloneItem)
+	}
+
+	copy(clone.grid, b.grid)
+
 	return clone
 }
 
+// Resize changes the base's dimensions in place, keeping every item at its
+// position. Growing always succeeds. Shrinking succeeds only while every
+// item still lies wholly inside the new bounds; otherwise an error lists
+// the items that would not fit, by ID, and the base is left unchanged.
+// Unbuildable and reserved cells outside the new bounds are dropped.
+func (b *Base) Resize(width, height, depth int) error {
+	if width <= 0 || height <= 0 || depth <= 0 {
+		return fmt.Errorf("invalid base dimensions %dx%dx%d", width, height, depth)
+	}
+
+	resized := &Base{Width: width, Height: height, Depth: depth}
+	var outside []string
+	for id, item := range b.Items {
+		for _, pos := range item.GetOccupiedPositions() {
+			if !resized.IsPositionValid(pos) {
+				outside = append(outside, id)
+				break
+			}
+		}
+	}
+	if len(outside) > 0 {
+		sort.Strings(outside)
+		return fmt.Errorf("cannot resize base to %dx%dx%d: items outside the new bounds: %s",
+			width, height, depth, strings.Join(outside, ", "))
+	}
+
+	b.Width, b.Height, b.Depth = width, height, depth
+	b.version++
+	b.grid = newBitset(width * height * depth)
+	b.passable = make(map[Position]bool)
+	for _, item := range b.Items {
+		b.setCells(item, true)
+	}
+	for pos := range b.unbuildable {
+		if !b.IsPositionValid(pos) {
+			delete(b.unbuildable, pos)
+		}
+	}
+	for pos := range b.reserved {
+		if !b.IsPositionValid(pos) {
+			delete(b.reserved, pos)
+		}
+	}
+
+	return nil
+}
+
 // Helper function for absolute value
 func abs(x int) int {
 	if x < 0 {
@@ -0,0 +1,604 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// place puts a new item of the named structure at pos, failing the test if
+// it does not fit
+func place(t testing.TB, base *Base, id string, name StructureName, pos Position) *Item {
+	t.Helper()
+	item := NewItem(id, name)
+	item.Position = pos
+	if err := base.PlaceItem(item); err != nil {
+		t.Fatalf("placing %s: %v", id, err)
+	}
+	return item
+}
+
+func TestCanPlaceItemClearance(t *testing.T) {
+	tests := []struct {
+		name     string
+		neighbor Position
+		want     bool
+	}{
+		{"side neighbor", Position{X: 3, Z: 2}, false},
+		{"diagonal neighbor", Position{X: 1, Z: 1}, false},
+		{"two cells away", Position{X: 4, Z: 2}, true},
+		{"neighbor on another level", Position{X: 3, Y: 1, Z: 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(5, 2, 5)
+			base.ClearanceCells[ItemType(StructureNameFurnace)] = 1
+			place(t, base, "barrel", StructureNameWoodenBarrel, tt.neighbor)
+
+			furnace := NewItem("furnace", StructureNameFurnace)
+			furnace.Position = Position{X: 2, Z: 2}
+			if got := base.CanPlaceItem(furnace); got != tt.want {
+				t.Errorf("CanPlaceItem() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanPlaceItemClearanceAtEdge(t *testing.T) {
+	base := NewBase(3, 1, 3)
+	base.ClearanceCells[ItemType(StructureNameFurnace)] = 1
+
+	furnace := NewItem("furnace", StructureNameFurnace)
+	if !base.CanPlaceItem(furnace) {
+		t.Error("clearance cells outside the base blocked a corner placement")
+	}
+}
+
+func TestSpaceStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, base *Base)
+		want  SpaceStats
+	}{
+		{
+			name:  "empty",
+			setup: func(t *testing.T, base *Base) {},
+			want:  SpaceStats{Total: 16, Walkable: 16},
+		},
+		{
+			name: "workbench",
+			setup: func(t *testing.T, base *Base) {
+				place(t, base, "workbench", StructureNameWorkbench, Position{X: 1, Z: 1})
+			},
+			want: SpaceStats{Total: 16, Occupied: 2, Walkable: 14},
+		},
+		{
+			name: "furnace with clearance in a corner",
+			setup: func(t *testing.T, base *Base) {
+				base.ClearanceCells[ItemType(StructureNameFurnace)] = 1
+				place(t, base, "furnace", StructureNameFurnace, Position{})
+			},
+			want: SpaceStats{Total: 16, Occupied: 1, Walkable: 12, KeepOut: 3},
+		},
+		{
+			name: "unbuildable cell",
+			setup: func(t *testing.T, base *Base) {
+				base.SetBuildable(Position{X: 3, Z: 3}, false)
+			},
+			want: SpaceStats{Total: 16, Walkable: 15, KeepOut: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(4, 1, 4)
+			tt.setup(t, base)
+
+			got := base.SpaceStats()
+			if got != tt.want {
+				t.Errorf("SpaceStats() = %+v, want %+v", got, tt.want)
+			}
+			if sum := got.Occupied + got.Walkable + got.KeepOut; sum != got.Total {
+				t.Errorf("counts sum to %d, want Total %d", sum, got.Total)
+			}
+			if occupied := len(base.GetOccupiedPositions()); got.Occupied != occupied {
+				t.Errorf("Occupied = %d, but %d cells are occupied", got.Occupied, occupied)
+			}
+		})
+	}
+}
+
+func TestBuildableMaskLShapedBase(t *testing.T) {
+	// A 6x6 base missing its far quadrant forms an L
+	base := NewBase(6, 1, 6)
+	for x := 3; x < 6; x++ {
+		for z := 3; z < 6; z++ {
+			base.SetBuildable(Position{X: x, Z: z}, false)
+		}
+	}
+
+	tests := []struct {
+		name string
+		pos  Position
+		want bool
+	}{
+		{"inside the near arm", Position{X: 1, Z: 4}, true},
+		{"inside the far arm", Position{X: 3, Z: 1}, true},
+		{"straddling the boundary", Position{X: 2, Z: 4}, false},
+		{"inside the cut-out", Position{X: 4, Z: 4}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workbench := NewItem("workbench", StructureNameWorkbench)
+			workbench.Position = tt.pos
+			if got := base.CanPlaceItem(workbench); got != tt.want {
+				t.Errorf("CanPlaceItem() = %v, want %v", got, tt.want)
+			}
+			if err := base.Clone().PlaceItem(workbench); (err == nil) != tt.want {
+				t.Errorf("PlaceItem() error = %v, want success %v", err, tt.want)
+			}
+		})
+	}
+
+	if base.IsTraversable(Position{X: 4, Z: 4}) {
+		t.Error("a cell outside the usable area is traversable")
+	}
+}
+
+func TestCoincidentItems(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions map[string]Position
+		want      [][]string
+	}{
+		{"distinct", map[string]Position{"a": {X: 0}, "b": {X: 1}}, nil},
+		{"pair", map[string]Position{"a": {X: 2}, "b": {X: 2}, "c": {X: 3}}, [][]string{{"a", "b"}}},
+		{
+			"two groups",
+			map[string]Position{"d": {Z: 1}, "a": {Z: 1}, "c": {X: 4}, "b": {X: 4}, "e": {X: 4}},
+			[][]string{{"a", "d"}, {"b", "c", "e"}},
+		},
+		{"same X and Z, other level", map[string]Position{"a": {X: 1}, "b": {X: 1, Y: 1}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Overlapping items cannot be placed, as in an imported layout
+			base := NewBase(5, 2, 5)
+			for id, pos := range tt.positions {
+				item := NewItem(id, StructureNameWoodenBarrel)
+				item.Position = pos
+				base.Items[id] = item
+			}
+
+			var got [][]string
+			for _, group := range base.CoincidentItems() {
+				var ids []string
+				for _, item := range group {
+					ids = append(ids, item.ID)
+				}
+				got = append(got, ids)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CoincidentItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChebyshevAndOctileDistance(t *testing.T) {
+	tests := []struct {
+		name      string
+		offset    Position
+		chebyshev int
+		octile    float64
+	}{
+		{"same cell", Position{}, 0, 0},
+		{"+X", Position{X: 3}, 3, 3},
+		{"-Y", Position{Y: -2}, 2, 2},
+		{"+Z", Position{Z: 5}, 5, 5},
+		{"planar diagonal", Position{X: 2, Z: 2}, 2, 2 * math.Sqrt2},
+		{"full diagonal", Position{X: 2, Y: 2, Z: 2}, 2, 2 * math.Sqrt(3)},
+		{"mixed", Position{X: 4, Y: 1, Z: 2}, 4, math.Sqrt(3) + math.Sqrt2 + 2},
+	}
+
+	// Every octant, by the sign applied to each axis
+	signs := []int{1, -1}
+	for _, tt := range tests {
+		for _, sx := range signs {
+			for _, sy := range signs {
+				for _, sz := range signs {
+					from := Position{X: 10, Y: 10, Z: 10}
+					to := Position{X: from.X + sx*tt.offset.X, Y: from.Y + sy*tt.offset.Y, Z: from.Z + sz*tt.offset.Z}
+					if got := from.ChebyshevDistance(to); got != tt.chebyshev {
+						t.Errorf("%s %s->%s: ChebyshevDistance = %d, want %d", tt.name, from, to, got, tt.chebyshev)
+					}
+					if got := from.OctileDistance(to); math.Abs(got-tt.octile) > 1e-9 {
+						t.Errorf("%s %s->%s: OctileDistance = %v, want %v", tt.name, from, to, got, tt.octile)
+					}
+					if to.OctileDistance(from) != from.OctileDistance(to) {
+						t.Errorf("%s %s->%s: OctileDistance is not symmetric", tt.name, from, to)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPlaceItemRejectsNonPositiveBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds BoundingBox
+	}{
+		{"zero width", BoundingBox{Width: 0, Height: 1, Depth: 1}},
+		{"zero height", BoundingBox{Width: 1, Height: 0, Depth: 1}},
+		{"negative depth", BoundingBox{Width: 1, Height: 1, Depth: -2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(4, 2, 4)
+			item := NewItem("flat", StructureNameWoodenBarrel)
+			item.Bounds = tt.bounds
+
+			err := base.PlaceItem(item)
+			if err == nil || !strings.Contains(err.Error(), "non-positive bounds") {
+				t.Fatalf("PlaceItem() error = %v, want a non-positive bounds error", err)
+			}
+			if len(base.Items) != 0 {
+				t.Errorf("base holds %d items after the rejected placement", len(base.Items))
+			}
+			if occupancy := base.GetOccupancyPercentage(); occupancy != 0 {
+				t.Errorf("occupancy = %v%% after the rejected placement", occupancy)
+			}
+		})
+	}
+}
+
+func TestMoveItem(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		to       Position
+		rotation int
+		wantErr  bool
+	}{
+		{"free cell", "bench", Position{X: 0, Z: 3}, 0, false},
+		{"rotate in place", "bench", Position{X: 0, Z: 0}, 90, false},
+		{"into an occupied cell", "bench", Position{X: 2, Z: 0}, 0, true},
+		{"overlapping its own cells", "bench", Position{X: 1, Z: 0}, 0, false},
+		{"out of bounds", "bench", Position{X: 3, Z: 0}, 0, true},
+		{"invalid rotation", "bench", Position{X: 0, Z: 3}, 45, true},
+		{"unknown item", "missing", Position{X: 0, Z: 3}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(4, 1, 4)
+			bench := place(t, base, "bench", StructureNameWorkbench, Position{X: 0, Z: 0})
+			barrel := place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 3, Z: 0})
+			place(t, base, "blocker", StructureNameWoodenBarrel, Position{X: 2, Z: 1})
+			before := bench.GetOccupiedPositions()
+
+			err := base.MoveItem(tt.id, tt.to, tt.rotation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoveItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			want := before
+			if !tt.wantErr {
+				if bench.Position != tt.to || bench.Rotation != tt.rotation {
+					t.Errorf("bench at %s rotated %d, want %s rotated %d", bench.Position, bench.Rotation, tt.to, tt.rotation)
+				}
+				want = bench.GetOccupiedPositions()
+				for _, pos := range before {
+					if !slices.Contains(want, pos) && base.IsPositionOccupied(pos) {
+						t.Errorf("old cell %s is still occupied", pos)
+					}
+				}
+			} else if bench.Position != (Position{}) || bench.Rotation != 0 {
+				t.Errorf("failed move left the bench at %s rotated %d", bench.Position, bench.Rotation)
+			}
+			for _, pos := range want {
+				if !base.IsPositionOccupied(pos) {
+					t.Errorf("bench cell %s is free", pos)
+				}
+			}
+			if !base.IsPositionOccupied(barrel.Position) {
+				t.Errorf("barrel cell %s was freed", barrel.Position)
+			}
+		})
+	}
+}
+
+func TestCheckFits(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     StructureName
+		rotation int
+		base     BoundingBox
+		wantErr  string
+	}{
+		{"palbox fits", StructureNamePalbox, 0, BoundingBox{Width: 2, Height: 2, Depth: 2}, ""},
+		{"palbox too wide", StructureNamePalbox, 0, BoundingBox{Width: 1, Height: 16, Depth: 2},
+			"item palbox (2x2x2) cannot fit in base 1x16x2 along axis X"},
+		{"palbox too tall", StructureNamePalbox, 0, BoundingBox{Width: 4, Height: 1, Depth: 4},
+			"item palbox (2x2x2) cannot fit in base 4x1x4 along axis Y"},
+		{"palbox too deep", StructureNamePalbox, 0, BoundingBox{Width: 4, Height: 4, Depth: 1},
+			"item palbox (2x2x2) cannot fit in base 4x4x1 along axis Z"},
+		{"rotated workbench fits a narrow base", StructureNameWorkbench, 90, BoundingBox{Width: 1, Height: 1, Depth: 2}, ""},
+		{"rotated workbench too deep", StructureNameWorkbench, 90, BoundingBox{Width: 2, Height: 1, Depth: 1},
+			"item workbench (1x1x2) cannot fit in base 2x1x1 along axis Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(tt.base.Width, tt.base.Height, tt.base.Depth)
+			item := NewItem(string(tt.item), tt.item)
+			item.Rotation = tt.rotation
+
+			err := base.CheckFits(item)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CheckFits() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("CheckFits() = %v, want %q", err, tt.wantErr)
+			}
+
+			// PlaceItem reports the same reason rather than "no room"
+			if err := base.PlaceItem(item); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("PlaceItem() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIntersectsHonorsRotation(t *testing.T) {
+	tests := []struct {
+		name       string
+		a, b       Position
+		aRot, bRot int
+		want       bool
+	}{
+		{"side by side, unrotated", Position{X: 0}, Position{X: 2}, 0, 0, false},
+		{"overlapping, unrotated", Position{X: 0}, Position{X: 1}, 0, 0, true},
+		{"rotated into the other", Position{X: 1, Z: 1}, Position{X: 2}, 0, 90, true},
+		{"rotated out of the other", Position{X: 1}, Position{X: 0}, 0, 90, false},
+		{"both rotated, stacked along Z", Position{}, Position{Z: 1}, 90, 90, true},
+		{"both rotated, side by side", Position{}, Position{X: 1}, 90, 90, false},
+		{"180 and 270 match 0 and 90", Position{X: 1, Z: 1}, Position{X: 2}, 180, 270, true},
+		{"different levels", Position{X: 1, Z: 1}, Position{X: 2, Y: 1}, 0, 90, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewItem("a", StructureNameWorkbench)
+			a.Position, a.Rotation = tt.a, tt.aRot
+			b := NewItem("b", StructureNameWorkbench)
+			b.Position, b.Rotation = tt.b, tt.bRot
+
+			if got := a.Intersects(*b); got != tt.want {
+				t.Errorf("a.Intersects(b) = %v, want %v", got, tt.want)
+			}
+			if got := b.Intersects(*a); got != tt.want {
+				t.Errorf("b.Intersects(a) = %v, want %v", got, tt.want)
+			}
+
+			// The occupied cells agree
+			shared := false
+			for _, pos := range a.GetOccupiedPositions() {
+				shared = shared || slices.Contains(b.GetOccupiedPositions(), pos)
+			}
+			if shared != tt.want {
+				t.Errorf("occupied cells overlap = %v, want %v", shared, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortedItems(t *testing.T) {
+	tests := []struct {
+		name  string
+		items map[string]StructureName
+		want  []string
+	}{
+		{"empty", nil, []string{}},
+		{
+			"priority first",
+			map[string]StructureName{"a_barrel": StructureNameWoodenBarrel, "z_palbox": StructureNamePalbox},
+			[]string{"z_palbox", "a_barrel"},
+		},
+		{
+			"then ID",
+			map[string]StructureName{"c": StructureNameWoodenBarrel, "a": StructureNameWoodenBarrel, "b": StructureNameWoodenBarrel},
+			[]string{"a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(10, 4, 10)
+			x := 0
+			for id, name := range tt.items {
+				place(t, base, id, name, Position{X: x})
+				x += 3
+			}
+			got := []string{}
+			for _, item := range base.SortedItems() {
+				got = append(got, item.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SortedItems() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOccupiedAt(t *testing.T) {
+	pos := Position{X: 3, Y: 1, Z: 3}
+	alongX := []Position{{X: 3, Y: 1, Z: 3}, {X: 4, Y: 1, Z: 3}}
+	alongZ := []Position{{X: 3, Y: 1, Z: 3}, {X: 3, Y: 1, Z: 4}}
+
+	tests := []struct {
+		rotation int
+		want     []Position
+	}{
+		{0, alongX},
+		{90, alongZ},
+		{180, alongX},
+		{270, alongZ},
+		{-90, alongZ},
+		{450, alongZ},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.rotation), func(t *testing.T) {
+			workbench := NewItem("workbench", StructureNameWorkbench)
+			got := workbench.OccupiedAt(pos, tt.rotation)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("OccupiedAt(%s, %d) = %v, want %v", pos, tt.rotation, got, tt.want)
+			}
+			if workbench.Position != (Position{}) || workbench.Rotation != 0 {
+				t.Errorf("OccupiedAt changed the item to %s rotated %d", workbench.Position, workbench.Rotation)
+			}
+		})
+	}
+}
+
+func TestCanPlaceAt(t *testing.T) {
+	base := NewBase(6, 4, 6)
+	place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 2, Z: 0})
+	placed := place(t, base, "workbench", StructureNameWorkbench, Position{X: 0, Z: 4})
+
+	tests := []struct {
+		name     string
+		item     *Item
+		pos      Position
+		rotation int
+		want     bool
+	}{
+		{"free", NewItem("new", StructureNameWorkbench), Position{X: 3, Z: 2}, 0, true},
+		{"onto another item", NewItem("new", StructureNameWorkbench), Position{X: 1, Z: 0}, 0, false},
+		{"rotated clear of it", NewItem("new", StructureNameWorkbench), Position{X: 1, Z: 0}, 90, true},
+		{"rotated out of bounds", NewItem("new", StructureNameWorkbench), Position{X: 3, Z: 5}, 90, false},
+		{"over its own cells", placed, Position{X: 1, Z: 4}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pose, rotation := tt.item.Position, tt.item.Rotation
+			if got := base.CanPlaceAt(tt.item, tt.pos, tt.rotation); got != tt.want {
+				t.Errorf("CanPlaceAt(%s, %d) = %v, want %v", tt.pos, tt.rotation, got, tt.want)
+			}
+			if tt.item.Position != pose || tt.item.Rotation != rotation {
+				t.Error("CanPlaceAt moved the item")
+			}
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	tests := []struct {
+		name                 string
+		width, height, depth int
+		wantErr              string
+	}{
+		{"grow", 12, 6, 12, ""},
+		{"lossless shrink", 6, 2, 5, ""},
+		{"lossy shrink", 4, 2, 4, "items outside the new bounds: bench, palbox"},
+		{"too short for the Palbox", 8, 1, 8, "palbox"},
+		{"invalid", 0, 2, 5, "invalid base dimensions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(8, 4, 8)
+			place(t, base, "palbox", StructureNamePalbox, Position{X: 3, Z: 3})
+			place(t, base, "bench", StructureNameWorkbench, Position{X: 4, Z: 0})
+			place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 0, Z: 0})
+			base.SetBuildable(Position{X: 7, Z: 7}, false)
+			base.Reserve(Position{X: 0, Z: 7})
+			wantW, wantH, wantD := tt.width, tt.height, tt.depth
+			if tt.wantErr != "" {
+				wantW, wantH, wantD = 8, 4, 8
+			}
+
+			err := base.Resize(tt.width, tt.height, tt.depth)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Resize: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Resize error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+
+			if base.Width != wantW || base.Height != wantH || base.Depth != wantD {
+				t.Errorf("base is %dx%dx%d, want %dx%dx%d", base.Width, base.Height, base.Depth, wantW, wantH, wantD)
+			}
+			if len(base.Items) != 3 {
+				t.Errorf("base holds %d items, want 3", len(base.Items))
+			}
+			for _, item := range base.Items {
+				item.ForEachOccupied(func(pos Position) {
+					if !base.IsPositionOccupied(pos) {
+						t.Errorf("%s cell %s is not occupied after resizing", item.ID, pos)
+					}
+				})
+			}
+			probe := NewItem("probe", StructureNameWoodenBarrel)
+			probe.Position = Position{X: 2, Z: 2}
+			if !base.CanPlaceItem(probe) {
+				t.Errorf("free cell %s is not placeable after resizing", probe.Position)
+			}
+			if base.unbuildable[Position{X: 7, Z: 7}] != base.IsPositionValid(Position{X: 7, Z: 7}) {
+				t.Error("unbuildable cell kept outside the base or dropped inside it")
+			}
+			if base.IsReserved(Position{X: 0, Z: 7}) != base.IsPositionValid(Position{X: 0, Z: 7}) {
+				t.Error("reserved cell kept outside the base or dropped inside it")
+			}
+		})
+	}
+}
+
+func TestRemoveByCategory(t *testing.T) {
+	base := NewBase(4, 1, 2)
+	place(t, base, "red", StructureNameRedMetalBarrel, Position{X: 0})
+	place(t, base, "blue", StructureNameBlueMetalBarrel, Position{X: 1})
+	place(t, base, "bed", StructureNamePalBed, Position{X: 2})
+	rock := NewItem("rock", StructureNameGreenMetalBarrel)
+	rock.Position = Position{X: 3}
+	rock.Environmental = true
+	if err := base.PlaceItem(rock); err != nil {
+		t.Fatalf("placing rock: %v", err)
+	}
+
+	if got := base.RemoveByCategory(StructureCategoryFurniture); got != 2 {
+		t.Errorf("RemoveByCategory() = %d, want 2", got)
+	}
+	for _, id := range []string{"red", "blue"} {
+		if _, ok := base.Items[id]; ok {
+			t.Errorf("%s is still in the base", id)
+		}
+	}
+	for _, pos := range []Position{{X: 0}, {X: 1}} {
+		if base.IsPositionOccupied(pos) {
+			t.Errorf("cell %s is still occupied", pos)
+		}
+	}
+
+	// Other categories and environmental features stay
+	for _, id := range []string{"bed", "rock"} {
+		if _, ok := base.Items[id]; !ok {
+			t.Errorf("%s was removed", id)
+		}
+	}
+	if got := base.RemoveByCategory(StructureCategoryFurniture); got != 0 {
+		t.Errorf("second RemoveByCategory() = %d, want 0", got)
+	}
+
+	// The freed cells take new items
+	place(t, base, "reused", StructureNamePalBed, Position{X: 0})
+}
@@ -0,0 +1,30 @@
+package types
+
+// bitset packs one bit per cell into words, so that copying a whole grid
+// is a single copy of a contiguous slice
+type bitset []uint64
+
+// newBitset creates a cleared bitset holding n bits
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+// get reports whether bit i is set
+func (bs bitset) get(i int) bool {
+	return bs[i/64]&(1<<(uint(i)%64)) != 0
+}
+
+// set sets or clears bit i
+func (bs bitset) set(i int, on bool) {
+	if on {
+		bs[i/64] |= 1 << (uint(i) % 64)
+	} else {
+		bs[i/64] &^= 1 << (uint(i) % 64)
+	}
+}
+
+// cellIndex returns the bit of an in-bounds cell in the occupancy grid. Cells
+// are laid out by X, then Y, then Z, matching the order the grid is walked in.
+func (b *Base) cellIndex(pos Position) int {
+	return (pos.X*b.Height+pos.Y)*b.Depth + pos.Z
+}
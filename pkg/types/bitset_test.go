@@ -0,0 +1,115 @@
+package types
+
+import "testing"
+
+func TestBitset(t *testing.T) {
+	tests := []struct {
+		name string
+		bit  int
+	}{
+		{"first bit", 0},
+		{"last bit of a word", 63},
+		{"first bit of the next word", 64},
+		{"last bit", 199},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := newBitset(200)
+			if len(bs) != 4 {
+				t.Fatalf("expected 4 words for 200 bits, got %d", len(bs))
+			}
+
+			bs.set(tt.bit, true)
+			for i := 0; i < 200; i++ {
+				if bs.get(i) != (i == tt.bit) {
+					t.Fatalf("bit %d: got %v after setting only bit %d", i, bs.get(i), tt.bit)
+				}
+			}
+
+			bs.set(tt.bit, false)
+			if bs.get(tt.bit) {
+				t.Errorf("bit %d still set after clearing", tt.bit)
+			}
+		})
+	}
+}
+
+func TestCloneOccupancy(t *testing.T) {
+	base, _ := randomBase(t, 400)
+	clone := base.Clone()
+
+	for x := 0; x < base.Width; x++ {
+		for y := 0; y < base.Height; y++ {
+			for z := 0; z < base.Depth; z++ {
+				pos := Position{X: x, Y: y, Z: z}
+				if clone.IsPositionOccupied(pos) != base.IsPositionOccupied(pos) {
+					t.Fatalf("clone occupancy differs at %v", pos)
+				}
+			}
+		}
+	}
+
+	// Placing into the clone must leave the original untouched
+	var free Position
+	found := false
+	clone.EachFreePosition(func(pos Position) bool {
+		free, found = pos, true
+		return false
+	})
+	if !found {
+		t.Fatal("expected a free cell")
+	}
+	item := NewItem("extra", StructureNameWoodenBarrel)
+	item.Position = free
+	if err := clone.PlaceItem(item); err != nil {
+		t.Fatalf("placing into clone: %v", err)
+	}
+	if base.IsPositionOccupied(free) {
+		t.Error("placing into the clone occupied the original")
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	b.ReportAllocs()
+	for b.Loop() {
+		base.Clone()
+	}
+}
+
+func BenchmarkCloneGrid(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	b.ReportAllocs()
+	for b.Loop() {
+		grid := make(bitset, len(base.grid))
+		copy(grid, base.grid)
+	}
+}
+
+// BenchmarkCloneNestedGrid copies a [][][]bool grid of the same size, the
+// layout the bitset replaces, for comparison with BenchmarkCloneGrid
+func BenchmarkCloneNestedGrid(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	grid := make([][][]bool, base.Width)
+	for x := range grid {
+		grid[x] = make([][]bool, base.Height)
+		for y := range grid[x] {
+			grid[x][y] = make([]bool, base.Depth)
+			for z := range grid[x][y] {
+				grid[x][y][z] = base.IsPositionOccupied(Position{X: x, Y: y, Z: z})
+			}
+		}
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		clone := make([][][]bool, len(grid))
+		for x := range grid {
+			clone[x] = make([][]bool, len(grid[x]))
+			for y := range grid[x] {
+				clone[x][y] = make([]bool, len(grid[x][y]))
+				copy(clone[x][y], grid[x][y])
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package types
+
+import "fmt"
+
+// BuildStep is one structure in a build order, with what it adds to the
+// bill and the bill so far
+type BuildStep struct {
+	Item *Item
+
+	// Materials and Work are what this step costs on its own
+	Materials map[string]int
+	Work      int
+
+	// TotalMaterials and TotalWork are the running totals including this
+	// step
+	TotalMaterials map[string]int
+	TotalWork      int
+
+	// Warning explains why the step's costs are unknown, for items without
+	// a structure definition. It is empty otherwise.
+	Warning string
+}
+
+// buildOrder returns the base's buildable items, skipping fixed features,
+// in build order: highest priority first, then by ID
+func (b *Base) buildOrder() []*Item {
+	var items []*Item
+	for _, item := range b.SortedItems() {
+		if !item.IsFixed() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// TotalMaterialCost sums the material costs of every buildable item in the
+// base. Fixed features and items without a structure definition add
+// nothing.
+func (b *Base) TotalMaterialCost() map[string]int {
+	total := make(map[string]int)
+	for _, item := range b.buildOrder() {
+		if def, err := item.Type.Definition(); err == nil {
+			for material, amount := range def.MaterialCost {
+				total[material] += amount
+			}
+		}
+	}
+	return total
+}
+
+// BuildPlan lists the base's buildable items in build order (highest
+// priority first, then by ID) with each item's material and work cost and
+// the cumulative totals after building it. Items without a structure
+// definition still get a step, costing nothing and carrying a Warning.
+// Material maps stay empty unless the structure table sets MaterialCost,
+// which the built-in one does not.
+func (b *Base) BuildPlan() []BuildStep {
+	items := b.buildOrder()
+	plan := make([]BuildStep, 0, len(items))
+
+	totalMaterials := make(map[string]int)
+	totalWork := 0
+	for _, item := range items {
+		step := BuildStep{Item: item, Materials: make(map[string]int)}
+
+		def, err := item.Type.Definition()
+		if err != nil {
+			step.Warning = fmt.Sprintf("cost of %s unknown: %v", item.ID, err)
+		} else {
+			for material, amount := range def.MaterialCost {
+				step.Materials[material] = amount
+				totalMaterials[material] += amount
+			}
+			step.Work = def.BuildWork
+			totalWork += def.BuildWork
+		}
+
+		step.TotalMaterials = make(map[string]int, len(totalMaterials))
+		for material, amount := range totalMaterials {
+			step.TotalMaterials[material] = amount
+		}
+		step.TotalWork = totalWork
+
+		plan = append(plan, step)
+	}
+
+	return plan
+}
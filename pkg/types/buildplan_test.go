@@ -0,0 +1,102 @@
+package types
+
+import (
+	"maps"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildPlan(t *testing.T) {
+	builtin := StructureDefinitions
+	t.Cleanup(func() { SetStructureDefinitions(builtin) })
+
+	defs := maps.Clone(builtin)
+	bench := defs[StructureNameWorkbench]
+	bench.BuildWork, bench.DefaultPriority = 100, 90
+	bench.MaterialCost = map[string]int{"wood": 5}
+	defs[StructureNameWorkbench] = bench
+	bed := defs[StructureNamePalBed]
+	bed.BuildWork, bed.DefaultPriority = 40, 50
+	bed.MaterialCost = map[string]int{"wood": 10, "fiber": 5}
+	defs[StructureNamePalBed] = bed
+	SetStructureDefinitions(defs)
+
+	base := NewBase(10, 2, 10)
+	place(t, base, "bench", StructureNameWorkbench, Position{X: 0, Z: 0})
+	place(t, base, "bed_b", StructureNamePalBed, Position{X: 5, Z: 0})
+	place(t, base, "bed_a", StructureNamePalBed, Position{X: 5, Z: 5})
+	mystery := &Item{ID: "mystery", Type: ItemType("shrine"), Bounds: BoundingBox{Width: 1, Height: 1, Depth: 1}, Position: Position{X: 8, Z: 8}, Priority: 70}
+	tree := &Item{ID: "tree", Type: ItemTypeTree, Bounds: BoundingBox{Width: 1, Height: 2, Depth: 1}, Position: Position{X: 9, Z: 0}, Priority: 100}
+	for _, item := range []*Item{mystery, tree} {
+		if err := base.PlaceItem(item); err != nil {
+			t.Fatalf("placing %s: %v", item.ID, err)
+		}
+	}
+
+	tests := []struct {
+		id             string
+		materials      map[string]int
+		work           int
+		totalMaterials map[string]int
+		totalWork      int
+		warning        bool
+	}{
+		{"bench", map[string]int{"wood": 5}, 100, map[string]int{"wood": 5}, 100, false},
+		{"mystery", map[string]int{}, 0, map[string]int{"wood": 5}, 100, true},
+		{"bed_a", map[string]int{"wood": 10, "fiber": 5}, 40, map[string]int{"wood": 15, "fiber": 5}, 140, false},
+		{"bed_b", map[string]int{"wood": 10, "fiber": 5}, 40, map[string]int{"wood": 25, "fiber": 10}, 180, false},
+	}
+
+	plan := base.BuildPlan()
+	if len(plan) != len(tests) {
+		t.Fatalf("plan has %d steps, want %d without the tree", len(plan), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			step := plan[i]
+			if step.Item.ID != tt.id {
+				t.Fatalf("step %d builds %s, want %s", i, step.Item.ID, tt.id)
+			}
+			if !reflect.DeepEqual(step.Materials, tt.materials) || step.Work != tt.work {
+				t.Errorf("step costs %v and %d work, want %v and %d", step.Materials, step.Work, tt.materials, tt.work)
+			}
+			if !reflect.DeepEqual(step.TotalMaterials, tt.totalMaterials) || step.TotalWork != tt.totalWork {
+				t.Errorf("totals are %v and %d work, want %v and %d", step.TotalMaterials, step.TotalWork, tt.totalMaterials, tt.totalWork)
+			}
+			if tt.warning != (step.Warning != "") || (tt.warning && !strings.Contains(step.Warning, tt.id)) {
+				t.Errorf("Warning = %q", step.Warning)
+			}
+		})
+	}
+
+	// Each step's totals are its own copy
+	plan[0].TotalMaterials["wood"] = 1000
+	if plan[1].TotalMaterials["wood"] != 5 {
+		t.Error("steps share their running totals")
+	}
+
+	if got, want := base.TotalMaterialCost(), map[string]int{"wood": 25, "fiber": 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TotalMaterialCost() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildPlanBuiltinTracksWorkOnly(t *testing.T) {
+	base := NewBase(6, 2, 6)
+	place(t, base, "bench", StructureNameWorkbench, Position{X: 0, Z: 0})
+	place(t, base, "bed", StructureNamePalBed, Position{X: 3, Z: 3})
+
+	plan := base.BuildPlan()
+	last := plan[len(plan)-1]
+	if len(last.TotalMaterials) != 0 {
+		t.Errorf("built-in table produced materials %v", last.TotalMaterials)
+	}
+	want := 0
+	for _, name := range []StructureName{StructureNameWorkbench, StructureNamePalBed} {
+		def, _ := ItemType(name).Definition()
+		want += def.BuildWork
+	}
+	if last.TotalWork != want || want == 0 {
+		t.Errorf("TotalWork = %d, want %d", last.TotalWork, want)
+	}
+}
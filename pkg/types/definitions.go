@@ -0,0 +1,79 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// knownCategories lists every valid StructureCategory
+var knownCategories = map[StructureCategory]bool{
+	StructureCategoryFood:           true,
+	StructureCategoryFoundation:     true,
+	StructureCategoryDefense:        true,
+	StructureCategoryInfrastructure: true,
+	StructureCategoryStorage:        true,
+	StructureCategoryPals:           true,
+	StructureCategoryLight:          true,
+	StructureCategoryProduction:     true,
+	StructureCategoryFurniture:      true,
+	StructureCategoryOther:          true,
+}
+
+// LoadStructureDefinitions reads a structure table from JSON: an object
+// mapping each structure name to its definition, in the form written by
+// WriteStructureDefinitions. Unknown fields are rejected, as are entries
+// whose category is not a known StructureCategory, whose name disagrees
+// with their key, whose default bounds are not positive, or that depend on
+// a structure missing from the table. An entry without a name takes its
+// key.
+func LoadStructureDefinitions(r io.Reader) (map[StructureName]StructureDefinition, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var defs map[StructureName]StructureDefinition
+	if err := decoder.Decode(&defs); err != nil {
+		return nil, fmt.Errorf("decoding structure definitions: %w", err)
+	}
+
+	for name, def := range defs {
+		if def.Name == "" {
+			def.Name = name
+		}
+		if def.Name != name {
+			return nil, fmt.Errorf("structure %q: name %q does not match its key", name, def.Name)
+		}
+		if !knownCategories[def.Category] {
+			return nil, fmt.Errorf("structure %q: unknown category %q", name, def.Category)
+		}
+		bounds := def.DefaultBounds
+		if bounds.Width <= 0 || bounds.Height <= 0 || bounds.Depth <= 0 {
+			return nil, fmt.Errorf("structure %q: default bounds %dx%dx%d must be positive", name, bounds.Width, bounds.Height, bounds.Depth)
+		}
+		for _, needs := range def.DependsOn {
+			if _, ok := defs[needs]; !ok {
+				return nil, fmt.Errorf("structure %q: depends on unknown structure %q", name, needs)
+			}
+		}
+		defs[name] = def
+	}
+
+	return defs, nil
+}
+
+// WriteStructureDefinitions writes a structure table as indented JSON that
+// LoadStructureDefinitions reads back unchanged. Writing the built-in
+// StructureDefinitions gives a starting point for an edited table.
+func WriteStructureDefinitions(w io.Writer, defs map[StructureName]StructureDefinition) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(defs)
+}
+
+// SetStructureDefinitions replaces the structure table every lookup uses,
+// such as ItemType.Definition and NewItem, with defs. The table is read
+// without locking, so replace it only while nothing is placing or scoring
+// items, for example at startup or between optimization runs.
+func SetStructureDefinitions(defs map[StructureName]StructureDefinition) {
+	StructureDefinitions = defs
+}
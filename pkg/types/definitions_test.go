@@ -0,0 +1,94 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStructureDefinitionsRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteStructureDefinitions(&buf, StructureDefinitions); err != nil {
+		t.Fatalf("WriteStructureDefinitions: %v", err)
+	}
+	defs, err := LoadStructureDefinitions(&buf)
+	if err != nil {
+		t.Fatalf("LoadStructureDefinitions: %v", err)
+	}
+	if !reflect.DeepEqual(defs, StructureDefinitions) {
+		t.Error("definitions changed in a round trip through JSON")
+	}
+}
+
+func TestLoadStructureDefinitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"valid", `{"hut": {"category": "Other", "default_bounds": {"width": 2, "height": 1, "depth": 3}}}`, ""},
+		{"name matches key", `{"hut": {"name": "hut", "category": "Other", "default_bounds": {"width": 1, "height": 1, "depth": 1}}}`, ""},
+		{"unknown field", `{"hut": {"category": "Other", "colour": "red", "default_bounds": {"width": 1, "height": 1, "depth": 1}}}`, "unknown field"},
+		{"unknown category", `{"hut": {"category": "Spaceship", "default_bounds": {"width": 1, "height": 1, "depth": 1}}}`, "unknown category"},
+		{"mismatched name", `{"hut": {"name": "shed", "category": "Other", "default_bounds": {"width": 1, "height": 1, "depth": 1}}}`, "does not match"},
+		{"empty bounds", `{"hut": {"category": "Other"}}`, "must be positive"},
+		{"missing dependency", `{"hut": {"category": "Other", "default_bounds": {"width": 1, "height": 1, "depth": 1}, "depends_on": ["shed"]}}`, "unknown structure"},
+		{"not JSON", `hut`, "decoding"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defs, err := LoadStructureDefinitions(strings.NewReader(tt.json))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadStructureDefinitions: %v", err)
+				}
+				if defs["hut"].Name != "hut" {
+					t.Errorf("Name = %q, want the key", defs["hut"].Name)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one mentioning %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetStructureDefinitions(t *testing.T) {
+	builtin := StructureDefinitions
+	t.Cleanup(func() { SetStructureDefinitions(builtin) })
+
+	SetStructureDefinitions(map[StructureName]StructureDefinition{
+		"hut": {Name: "hut", Category: StructureCategoryOther, DefaultBounds: BoundingBox{Width: 3, Height: 2, Depth: 1}, DefaultPriority: 7},
+	})
+
+	item := NewItem("hut_1", "hut")
+	if item.Bounds != (BoundingBox{Width: 3, Height: 2, Depth: 1}) || item.Priority != 7 {
+		t.Errorf("NewItem with the replaced table gave bounds %v, priority %d", item.Bounds, item.Priority)
+	}
+	if palbox := NewItem("palbox", StructureNamePalbox); palbox.Bounds != (BoundingBox{Width: 1, Height: 1, Depth: 1}) {
+		t.Errorf("Palbox kept its built-in bounds %v after replacing the table", palbox.Bounds)
+	}
+}
+
+func TestSideText(t *testing.T) {
+	for side, name := range sideNames {
+		t.Run(name, func(t *testing.T) {
+			text, err := json.Marshal(side)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var got Side
+			if err := json.Unmarshal(text, &got); err != nil || got != side {
+				t.Errorf("%s read back as %v, %v", text, got, err)
+			}
+		})
+	}
+
+	var side Side
+	if err := json.Unmarshal([]byte(`"upside"`), &side); err == nil {
+		t.Error("unknown side name accepted")
+	}
+}
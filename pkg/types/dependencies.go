@@ -0,0 +1,108 @@
+package types
+
+import "sort"
+
+// MissingDependency reports an item whose structure depends on one that
+// none of the other items provide, such as an accumulator without a power
+// generator
+type MissingDependency struct {
+	Item  *Item
+	Needs StructureName
+}
+
+// dependencies returns the structures the item's definition depends on
+func dependencies(item *Item) []StructureName {
+	def, err := item.Type.Definition()
+	if err != nil {
+		return nil
+	}
+	return def.DependsOn
+}
+
+// OrderByDependencies returns the items reordered so that every item comes
+// after the items of the structures it depends on (see
+// StructureDefinition.DependsOn). Otherwise the input order is kept: of the
+// items whose dependencies are all listed, the earliest goes next, so items
+// sorted by priority stay in priority order wherever no dependency says
+// otherwise. Items caught in a dependency cycle are taken in input order.
+func OrderByDependencies(items []*Item) []*Item {
+	names := make([]StructureName, len(items))
+	for i, item := range items {
+		names[i], _ = item.Type.StructureName()
+	}
+
+	// waiting[i] counts the items i still waits for, and dependents[j] the
+	// items waiting for j
+	waiting := make([]int, len(items))
+	dependents := make([][]int, len(items))
+	for i, item := range items {
+		for _, needs := range dependencies(item) {
+			for j := range items {
+				if j != i && names[j] == needs {
+					waiting[i]++
+					dependents[j] = append(dependents[j], i)
+				}
+			}
+		}
+	}
+
+	ordered := make([]*Item, 0, len(items))
+	done := make([]bool, len(items))
+	for len(ordered) < len(items) {
+		next := -1
+		for i := range items {
+			if done[i] {
+				continue
+			}
+			if waiting[i] == 0 {
+				next = i
+				break
+			}
+			if next < 0 {
+				next = i // fallback for a cycle
+			}
+		}
+
+		done[next] = true
+		ordered = append(ordered, items[next])
+		for _, dependent := range dependents[next] {
+			waiting[dependent]--
+		}
+	}
+
+	return ordered
+}
+
+// CheckDependencies returns, for each item, the structures it depends on
+// that no other item in the list provides, sorted by item ID and then
+// structure name. A list with every dependency present returns nil.
+func CheckDependencies(items []*Item) []MissingDependency {
+	have := make(map[StructureName]int)
+	for _, item := range items {
+		if name, err := item.Type.StructureName(); err == nil {
+			have[name]++
+		}
+	}
+
+	var missing []MissingDependency
+	for _, item := range items {
+		own, _ := item.Type.StructureName()
+		for _, needs := range dependencies(item) {
+			count := have[needs]
+			if needs == own {
+				count-- // an item cannot satisfy its own dependency
+			}
+			if count <= 0 {
+				missing = append(missing, MissingDependency{Item: item, Needs: needs})
+			}
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].Item.ID != missing[j].Item.ID {
+			return missing[i].Item.ID < missing[j].Item.ID
+		}
+		return missing[i].Needs < missing[j].Needs
+	})
+
+	return missing
+}
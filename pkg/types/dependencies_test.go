@@ -0,0 +1,88 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+// itemsOf returns an item per name, with IDs id0, id1, ...
+func itemsOf(names ...StructureName) []*Item {
+	items := make([]*Item, len(names))
+	for i, name := range names {
+		items[i] = NewItem("id"+string(rune('0'+i)), name)
+	}
+	return items
+}
+
+func idsOf(items []*Item) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestOrderByDependencies(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []*Item
+		want  []string
+	}{
+		{"no dependencies keep their order",
+			itemsOf(StructureNameWorkbench, StructureNamePalBed, StructureNameWoodenBarrel),
+			[]string{"id0", "id1", "id2"}},
+		{"dependency moves ahead",
+			itemsOf(StructureNameAccumulator, StructureNamePalBed, StructureNamePowerGenerator),
+			[]string{"id1", "id2", "id0"}},
+		{"dependency already ahead",
+			itemsOf(StructureNamePowerGenerator, StructureNameAccumulator),
+			[]string{"id0", "id1"}},
+		{"waits for every provider",
+			itemsOf(StructureNameAccumulator, StructureNamePowerGenerator, StructureNamePowerGenerator),
+			[]string{"id1", "id2", "id0"}},
+		{"independent chains",
+			itemsOf(StructureNameIncubator, StructureNameAccumulator, StructureNameBreedingFarm, StructureNamePowerGenerator),
+			[]string{"id2", "id0", "id3", "id1"}},
+		{"missing dependency keeps its place",
+			itemsOf(StructureNameAccumulator, StructureNamePalBed),
+			[]string{"id0", "id1"}},
+		{"empty", nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idsOf(OrderByDependencies(tt.items)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OrderByDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	type missing struct {
+		id    string
+		needs StructureName
+	}
+	tests := []struct {
+		name  string
+		items []*Item
+		want  []missing
+	}{
+		{"all present", itemsOf(StructureNameAccumulator, StructureNamePowerGenerator), nil},
+		{"no dependencies", itemsOf(StructureNamePalBed, StructureNameWorkbench), nil},
+		{"generator missing", itemsOf(StructureNameAccumulator, StructureNamePalBed),
+			[]missing{{"id0", StructureNamePowerGenerator}}},
+		{"each dependent reported", itemsOf(StructureNameIncubator, StructureNameAccumulator, StructureNameIncubator),
+			[]missing{{"id0", StructureNameBreedingFarm}, {"id1", StructureNamePowerGenerator}, {"id2", StructureNameBreedingFarm}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []missing
+			for _, m := range CheckDependencies(tt.items) {
+				got = append(got, missing{m.Item.ID, m.Needs})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckDependencies() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
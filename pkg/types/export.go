@@ -0,0 +1,65 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// StructurePlacement is a single placed structure in a Palworld.gg
+// compatible structure list
+type StructurePlacement struct {
+	ID       string            `json:"id"`
+	Name     StructureName     `json:"name"`
+	Category StructureCategory `json:"category"`
+	Position Position          `json:"position"`
+	Rotation int               `json:"rotation"`
+}
+
+// ToStructureList converts the placed items into a structure list using
+// canonical Palworld.gg names and categories. Environmental features are
+// terrain rather than structures and are left out. Entries are ordered by
+// item ID so the output is stable between runs.
+func (b *Base) ToStructureList() ([]StructurePlacement, error) {
+	ids := make([]string, 0, len(b.Items))
+	for id := range b.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	placements := make([]StructurePlacement, 0, len(ids))
+	for _, id := range ids {
+		item := b.Items[id]
+		if item.IsFixed() {
+			continue
+		}
+
+		def, err := item.Type.Definition()
+		if err != nil {
+			return nil, fmt.Errorf("item %s: %w", item.ID, err)
+		}
+
+		placements = append(placements, StructurePlacement{
+			ID:       item.ID,
+			Name:     def.Name,
+			Category: def.Category,
+			Position: item.Position,
+			Rotation: item.Rotation,
+		})
+	}
+
+	return placements, nil
+}
+
+// WriteStructureListJSON writes the base's structure list as indented JSON
+func (b *Base) WriteStructureListJSON(w io.Writer) error {
+	placements, err := b.ToStructureList()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(placements)
+}
@@ -0,0 +1,119 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// exportBase holds a workbench, a legacy-typed medicine workbench turned
+// 90 degrees, and a rock
+func exportBase(t *testing.T) *Base {
+	base := NewBase(8, 2, 8)
+	place(t, base, "wb", StructureNameWorkbench, Position{X: 0, Z: 0})
+	for _, item := range []*Item{
+		{ID: "medicine", Type: ItemTypeMedicineWorkbench, Position: Position{X: 4, Y: 1, Z: 2}, Rotation: 90},
+		{ID: "rock", Type: ItemTypeRock, Position: Position{X: 7, Z: 7}},
+	} {
+		item.Bounds = BoundingBox{Width: 1, Height: 1, Depth: 1}
+		if err := base.PlaceItem(item); err != nil {
+			t.Fatalf("placing %s: %v", item.ID, err)
+		}
+	}
+	return base
+}
+
+func TestToStructureList(t *testing.T) {
+	tests := []struct {
+		name    string
+		extra   *Item
+		want    []StructurePlacement
+		wantErr string
+	}{
+		{"legacy types translated, features skipped", nil, []StructurePlacement{
+			{ID: "medicine", Name: StructureNameMedievalMedicineWorkbench, Category: StructureCategoryProduction, Position: Position{X: 4, Y: 1, Z: 2}, Rotation: 90},
+			{ID: "wb", Name: StructureNameWorkbench, Category: StructureCategoryProduction, Position: Position{X: 0, Z: 0}},
+		}, ""},
+		{"unmapped type", &Item{ID: "shrine", Type: ItemType("shrine"), Position: Position{X: 6, Z: 0}}, nil, `item shrine: item type "shrine"`},
+		{"unmapped environmental item", &Item{ID: "bush", Type: ItemType("bush"), Position: Position{X: 6, Z: 0}, Environmental: true}, []StructurePlacement{
+			{ID: "medicine", Name: StructureNameMedievalMedicineWorkbench, Category: StructureCategoryProduction, Position: Position{X: 4, Y: 1, Z: 2}, Rotation: 90},
+			{ID: "wb", Name: StructureNameWorkbench, Category: StructureCategoryProduction, Position: Position{X: 0, Z: 0}},
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := exportBase(t)
+			if tt.extra != nil {
+				tt.extra.Bounds = BoundingBox{Width: 1, Height: 1, Depth: 1}
+				if err := base.PlaceItem(tt.extra); err != nil {
+					t.Fatalf("placing %s: %v", tt.extra.ID, err)
+				}
+			}
+
+			got, err := base.ToStructureList()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				if err := base.WriteStructureListJSON(&bytes.Buffer{}); err == nil {
+					t.Error("WriteStructureListJSON succeeded with an unmapped type")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ToStructureList: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToStructureList() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteStructureListJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportBase(t).WriteStructureListJSON(&buf); err != nil {
+		t.Fatalf("WriteStructureListJSON: %v", err)
+	}
+
+	want := `[
+  {
+    "id": "medicine",
+    "name": "medieval_medicine_workbench",
+    "category": "Production",
+    "position": {
+      "x": 4,
+      "y": 1,
+      "z": 2
+    },
+    "rotation": 90
+  },
+  {
+    "id": "wb",
+    "name": "workbench",
+    "category": "Production",
+    "position": {
+      "x": 0,
+      "y": 0,
+      "z": 0
+    },
+    "rotation": 0
+  }
+]
+`
+	if buf.String() != want {
+		t.Errorf("JSON = %s, want %s", buf.String(), want)
+	}
+
+	// The output reads back into the same list
+	var decoded []StructurePlacement
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].Name != StructureNameMedievalMedicineWorkbench {
+		t.Errorf("decoded %+v", decoded)
+	}
+}
@@ -0,0 +1,110 @@
+package types
+
+import "fmt"
+
+// Exposure says whether a structure needs open sky or a roof over it, such
+// as crops that need sunlight
+type Exposure int
+
+const (
+	ExposureAny      Exposure = iota // indoors and outdoors will both do
+	ExposureIndoors                  // must stand indoors (see IsIndoors)
+	ExposureOutdoors                 // must stand outdoors
+)
+
+// exposureNames are the names exposures are written as in JSON
+var exposureNames = map[Exposure]string{
+	ExposureAny:      "any",
+	ExposureIndoors:  "indoors",
+	ExposureOutdoors: "outdoors",
+}
+
+// String returns the exposure's name
+func (e Exposure) String() string {
+	if name, ok := exposureNames[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("Exposure(%d)", int(e))
+}
+
+// MarshalText implements encoding.TextMarshaler, writing the exposure's
+// name
+func (e Exposure) MarshalText() ([]byte, error) {
+	name, ok := exposureNames[e]
+	if !ok {
+		return nil, fmt.Errorf("unknown exposure %d", int(e))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, reading an exposure's
+// name
+func (e *Exposure) UnmarshalText(text []byte) error {
+	for exposure, name := range exposureNames {
+		if name == string(text) {
+			*e = exposure
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown exposure %q", text)
+}
+
+// RequiredExposure returns where the item must stand: the item's own
+// Exposure, or its structure definition's when that is ExposureAny
+func (i Item) RequiredExposure() Exposure {
+	if i.Exposure != ExposureAny {
+		return i.Exposure
+	}
+	def, err := i.Type.Definition()
+	if err != nil {
+		return ExposureAny
+	}
+	return def.Exposure
+}
+
+// IsIndoors reports whether pos lies in an enclosed room: flood filling its
+// level from pos through cells no structure occupies never reaches the edge
+// of the base, and every cell of the fill has a structure somewhere above
+// it. pos itself may be occupied. Doors count as walls, so a room with a
+// door is still indoors.
+func (b *Base) IsIndoors(pos Position) bool {
+	if !b.IsPositionValid(pos) {
+		return false
+	}
+
+	// Open sky over the starting cell settles most queries before any fill
+	visited := map[Position]bool{pos: true}
+	room := []Position{pos}
+	for i := 0; i < len(room); i++ {
+		current := room[i]
+		if !b.hasRoof(current) {
+			return false
+		}
+		for _, offset := range neighborOffsets {
+			if offset.Y != 0 {
+				continue
+			}
+			next := Position{X: current.X + offset.X, Y: current.Y, Z: current.Z + offset.Z}
+			if !b.IsPositionValid(next) {
+				return false
+			}
+			if visited[next] || b.grid.get(b.cellIndex(next)) {
+				continue
+			}
+			visited[next] = true
+			room = append(room, next)
+		}
+	}
+
+	return true
+}
+
+// hasRoof reports whether a structure occupies any cell above pos
+func (b *Base) hasRoof(pos Position) bool {
+	for y := pos.Y + 1; y < b.Height; y++ {
+		if b.grid.get(b.cellIndex(Position{X: pos.X, Y: y, Z: pos.Z})) {
+			return true
+		}
+	}
+	return false
+}
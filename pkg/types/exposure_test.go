@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// buildRoom walls in the 3x3 cells from (2,0,2) to (4,0,4) on a 7x3x7 base
+// and, when roofed, covers them at y=2. The wall cell at (3,0,1) is left
+// out when gap is set, and is a door when door is set.
+func buildRoom(t *testing.T, roofed, gap, door bool) *Base {
+	t.Helper()
+	base := NewBase(7, 3, 7)
+	for x := 1; x <= 5; x++ {
+		for z := 1; z <= 5; z++ {
+			pos := Position{X: x, Z: z}
+			inside := x > 1 && x < 5 && z > 1 && z < 5
+			switch {
+			case inside:
+				if roofed {
+					place(t, base, fmt.Sprintf("roof_%d_%d", x, z), StructureNameGlassSlantedRoof, Position{X: x, Y: 2, Z: z})
+				}
+			case pos == Position{X: 3, Z: 1} && gap:
+			case pos == Position{X: 3, Z: 1} && door:
+				place(t, base, "door", StructureNameGlassWallAndDoor, pos)
+			default:
+				place(t, base, fmt.Sprintf("wall_%d_%d", x, z), StructureNameOuterWall, pos)
+			}
+		}
+	}
+	return base
+}
+
+func TestIsIndoors(t *testing.T) {
+	tests := []struct {
+		name              string
+		roofed, gap, door bool
+		pos               Position
+		want              bool
+	}{
+		{"roofed room", true, false, false, Position{X: 3, Z: 3}, true},
+		{"roofed room corner", true, false, false, Position{X: 2, Z: 4}, true},
+		{"roofed room with a door", true, false, true, Position{X: 3, Z: 3}, true},
+		{"open ground", true, false, false, Position{X: 0, Z: 0}, false},
+		{"walls without a roof", false, false, false, Position{X: 3, Z: 3}, false},
+		{"roofed room with a gap", true, true, false, Position{X: 3, Z: 3}, false},
+		{"outside the base", true, false, false, Position{X: -1, Z: 3}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := buildRoom(t, tt.roofed, tt.gap, tt.door)
+			if got := base.IsIndoors(tt.pos); got != tt.want {
+				t.Errorf("IsIndoors(%s) = %v, want %v", tt.pos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredExposure(t *testing.T) {
+	plot := NewItem("plot", StructureNameFoodPlot)
+	if got := plot.RequiredExposure(); got != ExposureOutdoors {
+		t.Errorf("food plot RequiredExposure() = %s, want outdoors", got)
+	}
+	plot.Exposure = ExposureIndoors
+	if got := plot.RequiredExposure(); got != ExposureIndoors {
+		t.Errorf("RequiredExposure() = %s with an indoors override", got)
+	}
+	if got := NewItem("bed", StructureNamePalBed).RequiredExposure(); got != ExposureAny {
+		t.Errorf("bed RequiredExposure() = %s, want any", got)
+	}
+
+	for exposure, name := range exposureNames {
+		text, err := json.Marshal(exposure)
+		var back Exposure
+		if err != nil || json.Unmarshal(text, &back) != nil || back != exposure {
+			t.Errorf("%s did not survive a JSON round trip: %s, %v", name, text, err)
+		}
+	}
+}
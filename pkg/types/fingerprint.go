@@ -0,0 +1,29 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// Fingerprint returns a short stable hash of the layout: the ID, type,
+// position and rotation of every item, taken in ID order. Two bases holding
+// the same items in the same poses share a fingerprint whatever order the
+// items were placed in, so a test can pin a known-good layout in a single
+// string.
+func (b *Base) Fingerprint() string {
+	ids := make([]string, 0, len(b.Items))
+	for id := range b.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	hash := sha256.New()
+	for _, id := range ids {
+		item := b.Items[id]
+		fmt.Fprintf(hash, "%q %q %d %d %d %d\n", id, item.Type,
+			item.Position.X, item.Position.Y, item.Position.Z, item.Rotation)
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
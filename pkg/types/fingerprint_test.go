@@ -0,0 +1,67 @@
+package types
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	layout := func(t *testing.T, reverse bool, change func(*Base)) string {
+		base := NewBase(8, 3, 8)
+		items := []struct {
+			id   string
+			name StructureName
+			pos  Position
+		}{
+			{"palbox", StructureNamePalbox, Position{X: 3, Z: 3}},
+			{"bench", StructureNameWorkbench, Position{X: 0, Z: 0}},
+			{"barrel", StructureNameWoodenBarrel, Position{X: 6, Z: 6}},
+		}
+		for i := range items {
+			if reverse {
+				i = len(items) - 1 - i
+			}
+			place(t, base, items[i].id, items[i].name, items[i].pos)
+		}
+		if change != nil {
+			change(base)
+		}
+		return base.Fingerprint()
+	}
+	want := layout(t, false, nil)
+
+	tests := []struct {
+		name    string
+		reverse bool
+		change  func(*Base)
+		same    bool
+	}{
+		{"same layout", false, nil, true},
+		{"placed in another order", true, nil, true},
+		{"item moved", false, func(b *Base) {
+			if err := b.MoveItem("barrel", Position{X: 7, Z: 6}, 0); err != nil {
+				t.Fatal(err)
+			}
+		}, false},
+		{"item rotated", false, func(b *Base) {
+			if err := b.MoveItem("barrel", Position{X: 6, Z: 6}, 90); err != nil {
+				t.Fatal(err)
+			}
+		}, false},
+		{"item retyped", false, func(b *Base) { b.Items["barrel"].Type = ItemType(StructureNameFoodBox) }, false},
+		{"item removed", false, func(b *Base) {
+			if err := b.RemoveItem("barrel"); err != nil {
+				t.Fatal(err)
+			}
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := layout(t, tt.reverse, tt.change)
+			if (got == want) != tt.same {
+				t.Errorf("Fingerprint() = %s, original %s; want equal = %v", got, want, tt.same)
+			}
+		})
+	}
+
+	if empty := NewBase(8, 3, 8).Fingerprint(); len(empty) != 16 {
+		t.Errorf("Fingerprint() = %q, want 16 hex digits", empty)
+	}
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFreePositions(t *testing.T) {
+	base := NewBase(3, 1, 2)
+	place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 1, Z: 0})
+	base.SetBuildable(Position{X: 2, Z: 1}, false)
+	want := []Position{{X: 0, Z: 0}, {X: 0, Z: 1}, {X: 1, Z: 1}, {X: 2, Z: 0}}
+
+	if got := base.GetFreePositions(); !slices.Equal(got, want) {
+		t.Errorf("GetFreePositions() = %v, want %v", got, want)
+	}
+
+	var each []Position
+	base.EachFreePosition(func(pos Position) bool {
+		each = append(each, pos)
+		return true
+	})
+	if !slices.Equal(each, want) {
+		t.Errorf("EachFreePosition visited %v, want %v", each, want)
+	}
+
+	// Returning false stops the walk
+	var first []Position
+	base.EachFreePosition(func(pos Position) bool {
+		first = append(first, pos)
+		return len(first) < 2
+	})
+	if !slices.Equal(first, want[:2]) {
+		t.Errorf("EachFreePosition stopping after two visited %v", first)
+	}
+
+	// A large enough buffer is reused, whatever it held before
+	buf := make([]Position, 10)
+	got := base.FreePositionsInto(buf)
+	if !slices.Equal(got, want) {
+		t.Errorf("FreePositionsInto() = %v, want %v", got, want)
+	}
+	if &got[0] != &buf[0] {
+		t.Error("FreePositionsInto() allocated despite a large enough buffer")
+	}
+}
+
+func BenchmarkGetFreePositions(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	b.ReportAllocs()
+	for b.Loop() {
+		base.GetFreePositions()
+	}
+}
+
+func BenchmarkFreePositionsInto(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	var buf []Position
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = base.FreePositionsInto(buf)
+	}
+}
+
+func BenchmarkEachFreePosition(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	b.ReportAllocs()
+	for b.Loop() {
+		count := 0
+		base.EachFreePosition(func(Position) bool {
+			count++
+			return true
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package types
+
+import "fmt"
+
+// operationKind identifies the edit an operation records
+type operationKind int
+
+const (
+	operationPlace operationKind = iota
+	operationRemove
+	operationMove
+)
+
+// operation is one successful PlaceItem, RemoveItem or MoveItem call. Moves
+// keep both poses so they can be replayed in either direction.
+type operation struct {
+	kind                     operationKind
+	item                     *Item
+	fromPosition, toPosition Position
+	fromRotation, toRotation int
+}
+
+// history holds the undo and redo stacks of a base
+type history struct {
+	undo, redo []operation
+
+	// replaying suppresses recording while Undo and Redo apply operations
+	replaying bool
+}
+
+// EnableHistory starts recording successful placements, removals and moves
+// so they can be reverted with Undo and reapplied with Redo. Failed
+// operations record nothing. History is off by default, is not copied by
+// Clone, and calling EnableHistory again keeps the existing history.
+func (b *Base) EnableHistory() {
+	if b.history == nil {
+		b.history = &history{}
+	}
+}
+
+// record pushes a completed operation onto the undo stack. A new edit
+// invalidates everything that could have been redone.
+func (b *Base) record(op operation) {
+	if b.history == nil || b.history.replaying {
+		return
+	}
+	b.history.undo = append(b.history.undo, op)
+	b.history.redo = nil
+}
+
+// Undo reverts the most recent recorded operation. It returns an error if
+// history is not enabled, there is nothing to undo, or the inverse
+// operation no longer fits because the base was changed behind the
+// history's back; in that case the operation stays on the undo stack.
+func (b *Base) Undo() error {
+	if b.history == nil {
+		return fmt.Errorf("history is not enabled")
+	}
+	if len(b.history.undo) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+
+	op := b.history.undo[len(b.history.undo)-1]
+	if err := b.replay(op, true); err != nil {
+		return err
+	}
+
+	b.history.undo = b.history.undo[:len(b.history.undo)-1]
+	b.history.redo = append(b.history.redo, op)
+	return nil
+}
+
+// Redo reapplies the most recently undone operation. It fails under the
+// same conditions as Undo.
+func (b *Base) Redo() error {
+	if b.history == nil {
+		return fmt.Errorf("history is not enabled")
+	}
+	if len(b.history.redo) == 0 {
+		return fmt.Errorf("nothing to redo")
+	}
+
+	op := b.history.redo[len(b.history.redo)-1]
+	if err := b.replay(op, false); err != nil {
+		return err
+	}
+
+	b.history.redo = b.history.redo[:len(b.history.redo)-1]
+	b.history.undo = append(b.history.undo, op)
+	return nil
+}
+
+// replay applies the operation, or its inverse when inverse is set, without
+// recording it
+func (b *Base) replay(op operation, inverse bool) error {
+	b.history.replaying = true
+	defer func() { b.history.replaying = false }()
+
+	place := op.kind == operationPlace
+	switch op.kind {
+	case operationPlace, operationRemove:
+		if place == inverse {
+			return b.RemoveItem(op.item.ID)
+		}
+		return b.PlaceItem(op.item)
+	case operationMove:
+		if inverse {
+			return b.MoveItem(op.item.ID, op.fromPosition, op.fromRotation)
+		}
+		return b.MoveItem(op.item.ID, op.toPosition, op.toRotation)
+	}
+	return fmt.Errorf("unknown operation %d", op.kind)
+}
@@ -0,0 +1,110 @@
+package types
+
+import "testing"
+
+func TestUndoRedo(t *testing.T) {
+	barrelAt := Position{X: 1, Z: 1}
+	movedTo := Position{X: 3, Z: 2}
+
+	tests := []struct {
+		name string
+		edit func(t *testing.T, base *Base)
+		// after reports whether the edit took effect
+		after func(base *Base) bool
+	}{
+		{
+			"place",
+			func(t *testing.T, base *Base) { place(t, base, "bench", StructureNameWorkbench, Position{X: 0, Z: 3}) },
+			func(base *Base) bool {
+				_, ok := base.Items["bench"]
+				return ok && base.IsPositionOccupied(Position{X: 1, Z: 3})
+			},
+		},
+		{
+			"remove",
+			func(t *testing.T, base *Base) {
+				if err := base.RemoveItem("barrel"); err != nil {
+					t.Fatalf("RemoveItem: %v", err)
+				}
+			},
+			func(base *Base) bool {
+				_, ok := base.Items["barrel"]
+				return !ok && !base.IsPositionOccupied(barrelAt)
+			},
+		},
+		{
+			"move",
+			func(t *testing.T, base *Base) {
+				if err := base.MoveItem("barrel", movedTo, 0); err != nil {
+					t.Fatalf("MoveItem: %v", err)
+				}
+			},
+			func(base *Base) bool {
+				return base.Items["barrel"].Position == movedTo &&
+					base.IsPositionOccupied(movedTo) && !base.IsPositionOccupied(barrelAt)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(4, 1, 4)
+			place(t, base, "barrel", StructureNameWoodenBarrel, barrelAt)
+			base.EnableHistory()
+
+			tt.edit(t, base)
+			if !tt.after(base) {
+				t.Fatal("edit did not take effect")
+			}
+
+			if err := base.Undo(); err != nil {
+				t.Fatalf("Undo: %v", err)
+			}
+			if tt.after(base) || len(base.Items) != 1 || base.Items["barrel"].Position != barrelAt ||
+				!base.IsPositionOccupied(barrelAt) {
+				t.Error("Undo did not restore the base")
+			}
+			if err := base.Undo(); err == nil {
+				t.Error("Undo past the first recorded edit succeeded")
+			}
+
+			if err := base.Redo(); err != nil {
+				t.Fatalf("Redo: %v", err)
+			}
+			if !tt.after(base) {
+				t.Error("Redo did not reapply the edit")
+			}
+			if err := base.Redo(); err == nil {
+				t.Error("Redo with nothing undone succeeded")
+			}
+		})
+	}
+}
+
+func TestHistoryRecordsOnlySuccessfulEdits(t *testing.T) {
+	base := NewBase(4, 1, 4)
+	if err := base.Undo(); err == nil {
+		t.Error("Undo succeeded without history enabled")
+	}
+
+	base.EnableHistory()
+	place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 0, Z: 0})
+
+	// A failed placement records nothing
+	blocked := NewItem("blocked", StructureNameWoodenBarrel)
+	if err := base.PlaceItem(blocked); err == nil {
+		t.Fatal("placed an item on an occupied cell")
+	}
+	if err := base.Undo(); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if len(base.Items) != 0 {
+		t.Errorf("Undo left %d items, want the barrel's placement undone", len(base.Items))
+	}
+
+	// A new edit clears the redo stack
+	place(t, base, "other", StructureNameWoodenBarrel, Position{X: 2, Z: 2})
+	if err := base.Redo(); err == nil {
+		t.Error("Redo succeeded after a new edit")
+	}
+}
@@ -0,0 +1,57 @@
+package types
+
+import "math"
+
+// IsLightSource reports whether the item's structure belongs to the Light
+// category
+func (i Item) IsLightSource() bool {
+	def, err := i.Type.Definition()
+	return err == nil && def.Category == StructureCategoryLight
+}
+
+// WorkCells returns the free cells where Pals stand to use the base's
+// structures: every free cell sharing a face with a movable item that is
+// not itself a light source. Cells are returned sorted by position.
+func (b *Base) WorkCells() []Position {
+	seen := make(map[Position]bool)
+	var cells []Position
+	for _, item := range b.Items {
+		if item.IsFixed() || item.IsLightSource() {
+			continue
+		}
+		for _, pos := range b.AdjacentFreePositions(item) {
+			if !seen[pos] {
+				seen[pos] = true
+				cells = append(cells, pos)
+			}
+		}
+	}
+
+	sortPositions(cells)
+	return cells
+}
+
+// DarkCells returns the work cells (see WorkCells) that lie farther than
+// radius, by Euclidean distance, from every cell of every light source.
+// Cells are returned sorted by position.
+func (b *Base) DarkCells(radius float64) []Position {
+	var lit []Position
+	for _, item := range b.Items {
+		if item.IsLightSource() {
+			lit = append(lit, item.GetOccupiedPositions()...)
+		}
+	}
+
+	var dark []Position
+	for _, cell := range b.WorkCells() {
+		nearest := math.Inf(1)
+		for _, light := range lit {
+			nearest = math.Min(nearest, cell.Distance(light))
+		}
+		if nearest > radius {
+			dark = append(dark, cell)
+		}
+	}
+
+	return dark
+}
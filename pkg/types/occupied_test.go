@@ -0,0 +1,111 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestForEachOccupied(t *testing.T) {
+	tests := []struct {
+		name     string
+		item     StructureName
+		rotation int
+		want     int
+	}{
+		{"single cell", StructureNameWoodenBarrel, 0, 1},
+		{"wide", StructureNameWorkbench, 0, 2},
+		{"wide turned", StructureNameWorkbench, 90, 2},
+		{"tall", StructureNameOuterWall, 180, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := NewItem("item", tt.item)
+			item.Position, item.Rotation = Position{X: 3, Y: 1, Z: 2}, tt.rotation
+
+			var got []Position
+			item.ForEachOccupied(func(pos Position) { got = append(got, pos) })
+			if len(got) != tt.want {
+				t.Errorf("visited %d cells, want %d", len(got), tt.want)
+			}
+			if want := item.GetOccupiedPositions(); !reflect.DeepEqual(got, want) {
+				t.Errorf("ForEachOccupied visited %v, GetOccupiedPositions returns %v", got, want)
+			}
+		})
+	}
+}
+
+// canPlaceBase returns a base with clearance, a reservation and a few items
+// for placement checks to scan, and a workbench to check against it
+func canPlaceBase(tb testing.TB) (*Base, *Item) {
+	base := NewBase(12, 2, 12)
+	base.ClearanceCells[ItemTypeWorkbench] = 1
+	base.Reserve(Position{X: 8, Z: 8})
+	for i, pos := range []Position{{X: 0, Z: 0}, {X: 5, Z: 9}, {X: 10, Z: 2}} {
+		item := NewItem(string(rune('a'+i)), StructureNameWoodenBarrel)
+		item.Position = pos
+		if err := base.PlaceItem(item); err != nil {
+			tb.Fatalf("placing %s: %v", item.ID, err)
+		}
+	}
+	bench := NewItem("bench", StructureNameWorkbench)
+	bench.Position = Position{X: 4, Z: 4}
+	return base, bench
+}
+
+func TestCanPlaceItemDoesNotAllocate(t *testing.T) {
+	base, bench := canPlaceBase(t)
+	tests := []struct {
+		name string
+		pos  Position
+		want bool
+	}{
+		{"free", Position{X: 4, Z: 4}, true},
+		{"occupied", Position{X: 0, Z: 0}, false},
+		{"reserved", Position{X: 7, Z: 8}, false},
+		{"clearance blocked", Position{X: 4, Z: 8}, false},
+		{"outside the base", Position{X: 11, Z: 4}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bench.Position = tt.pos
+			if got := base.CanPlaceItem(bench); got != tt.want {
+				t.Fatalf("CanPlaceItem() = %v, want %v", got, tt.want)
+			}
+			if allocs := testing.AllocsPerRun(100, func() { base.CanPlaceItem(bench) }); allocs != 0 {
+				t.Errorf("CanPlaceItem allocated %v times per call", allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkCanPlaceItem(b *testing.B) {
+	base, bench := canPlaceBase(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		base.CanPlaceItem(bench)
+	}
+}
+
+// BenchmarkCanPlaceItemSlices checks the same placement through the
+// allocating GetOccupiedPositions and ClearanceBorder, as CanPlaceItem did
+// before ForEachOccupied, for comparison with BenchmarkCanPlaceItem
+func BenchmarkCanPlaceItemSlices(b *testing.B) {
+	base, bench := canPlaceBase(b)
+	canPlace := func() bool {
+		for _, pos := range bench.GetOccupiedPositions() {
+			if base.IsPositionOccupied(pos) || base.reserved[pos] {
+				return false
+			}
+		}
+		for _, pos := range base.ClearanceBorder(bench) {
+			if base.IsPositionValid(pos) && base.IsPositionOccupied(pos) {
+				return false
+			}
+		}
+		return true
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		canPlace()
+	}
+}
@@ -0,0 +1,70 @@
+package types
+
+// defensiveWallNames lists the wall structures that count towards enclosing
+// the base, in addition to anything in StructureCategoryDefense
+var defensiveWallNames = map[StructureName]bool{
+	StructureNameStoneDefensiveWall:  true,
+	StructureNameMetalDefensiveWall:  true,
+	StructureNameWoodenDefensiveWall: true,
+	StructureNameGlassWallAndDoor:    true,
+	StructureNameGlassFence:          true,
+	StructureNameOuterWall:           true,
+}
+
+// IsDefensive reports whether the item is a wall or defensive structure
+func (i Item) IsDefensive() bool {
+	def, err := i.Type.Definition()
+	if err != nil {
+		return false
+	}
+	return def.Category == StructureCategoryDefense || defensiveWallNames[def.Name]
+}
+
+// PerimeterCells returns the cells on the outer boundary of the base at
+// level y, walking the X edges first and then the remaining Z edges
+func (b *Base) PerimeterCells(y int) []Position {
+	if y < 0 || y >= b.Height || b.Width == 0 || b.Depth == 0 {
+		return nil
+	}
+
+	var cells []Position
+	for x := 0; x < b.Width; x++ {
+		cells = append(cells, Position{X: x, Y: y, Z: 0})
+		if b.Depth > 1 {
+			cells = append(cells, Position{X: x, Y: y, Z: b.Depth - 1})
+		}
+	}
+	for z := 1; z < b.Depth-1; z++ {
+		cells = append(cells, Position{X: 0, Y: y, Z: z})
+		if b.Width > 1 {
+			cells = append(cells, Position{X: b.Width - 1, Y: y, Z: z})
+		}
+	}
+
+	return cells
+}
+
+// PerimeterGaps returns the boundary cells at level y that are not covered
+// by a defensive structure
+func (b *Base) PerimeterGaps(y int) []Position {
+	covered := make(map[Position]bool)
+	for _, item := range b.Items {
+		if !item.IsDefensive() {
+			continue
+		}
+		for _, pos := range item.GetOccupiedPositions() {
+			if pos.Y == y {
+				covered[pos] = true
+			}
+		}
+	}
+
+	var gaps []Position
+	for _, pos := range b.PerimeterCells(y) {
+		if !covered[pos] {
+			gaps = append(gaps, pos)
+		}
+	}
+
+	return gaps
+}
@@ -0,0 +1,41 @@
+package types
+
+import "testing"
+
+func TestPerimeterCells(t *testing.T) {
+	tests := []struct {
+		name                 string
+		width, height, depth int
+		y                    int
+		want                 int
+	}{
+		{"square", 4, 1, 4, 0, 12},
+		{"strip", 5, 1, 1, 0, 5},
+		{"single cell", 1, 1, 1, 0, 1},
+		{"level above", 3, 2, 3, 1, 8},
+		{"level out of range", 3, 1, 3, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(tt.width, tt.height, tt.depth)
+			if got := len(base.PerimeterCells(tt.y)); got != tt.want {
+				t.Errorf("got %d perimeter cells, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerimeterGaps(t *testing.T) {
+	base := NewBase(3, 2, 3)
+	place(t, base, "wall", StructureNameOuterWall, Position{X: 0, Z: 0})
+	place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 1, Z: 0})
+
+	// The 2-high wall covers its cell on both levels; the barrel is not
+	// defensive
+	for y, want := range []int{7, 7} {
+		if got := len(base.PerimeterGaps(y)); got != want {
+			t.Errorf("level %d: got %d gaps, want %d", y, got, want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package types
+
+// neighborOffsets are the 6 axis-aligned directions used for connectivity
+var neighborOffsets = []Position{
+	{X: 0, Y: 1, Z: 0},
+	{X: 0, Y: -1, Z: 0},
+	{X: -1, Y: 0, Z: 0},
+	{X: 1, Y: 0, Z: 0},
+	{X: 0, Y: 0, Z: -1},
+	{X: 0, Y: 0, Z: 1},
+}
+
+// ConnectedFreeRegions groups the free positions of the base into regions
+// connected through face-adjacent free cells, using a breadth-first flood
+// fill. Regions are returned in the order their first cell is encountered
+// when scanning the grid.
+func (b *Base) ConnectedFreeRegions() [][]Position {
+	return b.connectedRegions(func(pos Position) bool {
+		return !b.IsPositionOccupied(pos)
+	})
+}
+
+// ConnectedTraversableRegions groups the traversable positions of the base
+// (see IsTraversable) into regions a Pal can walk between, in the same
+// order as ConnectedFreeRegions. Unlike free regions, rooms joined by a
+// door form a single region.
+func (b *Base) ConnectedTraversableRegions() [][]Position {
+	return b.connectedRegions(b.IsTraversable)
+}
+
+// connectedRegions flood fills the cells open reports true for into
+// face-connected regions, in grid scan order
+func (b *Base) connectedRegions(open func(Position) bool) [][]Position {
+	visited := make(map[Position]bool)
+	var regions [][]Position
+
+	for x := 0; x < b.Width; x++ {
+		for y := 0; y < b.Height; y++ {
+			for z := 0; z < b.Depth; z++ {
+				start := Position{X: x, Y: y, Z: z}
+				if visited[start] || !open(start) {
+					continue
+				}
+
+				visited[start] = true
+				region := []Position{start}
+				for i := 0; i < len(region); i++ {
+					current := region[i]
+					for _, offset := range neighborOffsets {
+						next := Position{
+							X: current.X + offset.X,
+							Y: current.Y + offset.Y,
+							Z: current.Z + offset.Z,
+						}
+						if visited[next] || !open(next) {
+							continue
+						}
+						visited[next] = true
+						region = append(region, next)
+					}
+				}
+
+				regions = append(regions, region)
+			}
+		}
+	}
+
+	return regions
+}
+
+// AdjacentFreePositions returns the free cells that share a face with the
+// item's footprint, in footprint scan order. These are the cells a Pal can
+// stand on to reach the item.
+func (b *Base) AdjacentFreePositions(item *Item) []Position {
+	footprint := make(map[Position]bool)
+	for _, pos := range item.GetOccupiedPositions() {
+		footprint[pos] = true
+	}
+
+	seen := make(map[Position]bool)
+	var adjacent []Position
+	for _, pos := range item.GetOccupiedPositions() {
+		for _, offset := range neighborOffsets {
+			next := Position{
+				X: pos.X + offset.X,
+				Y: pos.Y + offset.Y,
+				Z: pos.Z + offset.Z,
+			}
+			if footprint[next] || seen[next] || b.IsPositionOccupied(next) {
+				continue
+			}
+			seen[next] = true
+			adjacent = append(adjacent, next)
+		}
+	}
+
+	return adjacent
+}
@@ -0,0 +1,88 @@
+package types
+
+import "testing"
+
+func TestConnectedFreeRegions(t *testing.T) {
+	tests := []struct {
+		name    string
+		walls   []Position
+		regions int
+	}{
+		{"empty", nil, 1},
+		{"contiguous", []Position{{X: 0}, {X: 1}, {X: 2}}, 1},
+		{"split by a wall", []Position{{X: 2, Z: 0}, {X: 2, Z: 1}, {X: 2, Z: 2}, {X: 2, Z: 3}, {X: 2, Z: 4}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(5, 1, 5)
+			for i, pos := range tt.walls {
+				place(t, base, string(rune('a'+i)), StructureNameWoodenBarrel, pos)
+			}
+
+			regions := base.ConnectedFreeRegions()
+			if len(regions) != tt.regions {
+				t.Fatalf("got %d regions, want %d", len(regions), tt.regions)
+			}
+			cells := 0
+			for _, region := range regions {
+				cells += len(region)
+			}
+			if want := 25 - len(tt.walls); cells != want {
+				t.Errorf("regions hold %d cells, want %d", cells, want)
+			}
+		})
+	}
+}
+
+func TestIsTraversable(t *testing.T) {
+	base := NewBase(5, 2, 5)
+	place(t, base, "door", StructureNameGlassWallAndDoor, Position{X: 1, Z: 1})
+	place(t, base, "wall", StructureNameOuterWall, Position{X: 2, Z: 1})
+	base.SetBuildable(Position{X: 3, Z: 3}, false)
+	base.Reserve(Position{X: 4, Z: 4})
+
+	tests := []struct {
+		name string
+		pos  Position
+		want bool
+	}{
+		{"free cell", Position{X: 0, Z: 0}, true},
+		{"door cell", Position{X: 1, Z: 1}, true},
+		{"door upper cell", Position{X: 1, Y: 1, Z: 1}, true},
+		{"wall cell", Position{X: 2, Z: 1}, false},
+		{"non-buildable cell", Position{X: 3, Z: 3}, false},
+		{"reserved walkway", Position{X: 4, Z: 4}, true},
+		{"outside the base", Position{X: 5, Z: 0}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.IsTraversable(tt.pos); got != tt.want {
+				t.Errorf("IsTraversable(%s) = %v, want %v", tt.pos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectedTraversableRegionsJoinsDoors(t *testing.T) {
+	base := NewBase(5, 1, 3)
+	for z := range 3 {
+		name := StructureNameWoodenBarrel
+		if z == 1 {
+			name = StructureNameGlassWallAndDoor
+		}
+		item := NewItem(string(rune('a'+z)), name)
+		item.Bounds.Height = 1
+		item.Position = Position{X: 2, Z: z}
+		if err := base.PlaceItem(item); err != nil {
+			t.Fatalf("placing %s: %v", item.ID, err)
+		}
+	}
+
+	if free := base.ConnectedFreeRegions(); len(free) != 2 {
+		t.Errorf("got %d free regions, want the 2 rooms", len(free))
+	}
+	if walkable := base.ConnectedTraversableRegions(); len(walkable) != 1 || len(walkable[0]) != 13 {
+		t.Errorf("got traversable regions %v, want one of 13 cells joined by the door", walkable)
+	}
+}
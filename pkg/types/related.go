@@ -0,0 +1,40 @@
+package types
+
+import "math"
+
+// relatedItemTypes lists the item types that work together and benefit
+// from being placed close to each other
+var relatedItemTypes = map[ItemType][]ItemType{
+	ItemTypeFoodBox:        {ItemTypeFoodPlot, ItemTypeCookingPot},
+	ItemTypeFoodPlot:       {ItemTypeFoodBox, ItemTypeCookingPot},
+	ItemTypePowerGenerator: {ItemTypeAccumulator, ItemTypeWorkbench},
+	ItemTypeWorkbench:      {ItemTypePowerGenerator, ItemTypeStorage},
+	ItemTypeStorage:        {ItemTypeWorkbench, ItemTypeFurnace},
+}
+
+// RelatedItemTypes returns item types that are related to the given type
+func RelatedItemTypes(itemType ItemType) map[ItemType]bool {
+	related := make(map[ItemType]bool)
+	for _, relatedType := range relatedItemTypes[itemType] {
+		related[relatedType] = true
+	}
+	return related
+}
+
+// RelatedNeighborDistances returns, per item ID, the Euclidean distance to
+// the nearest item of a related type, or +Inf when there is none. Large
+// values point at items placed far from the items they work with.
+func (b *Base) RelatedNeighborDistances() map[string]float64 {
+	distances := make(map[string]float64, len(b.Items))
+	for id, item := range b.Items {
+		related := RelatedItemTypes(item.Type)
+		nearest := math.Inf(1)
+		for _, other := range b.Items {
+			if other.ID != item.ID && related[other.Type] {
+				nearest = math.Min(nearest, item.Position.Distance(other.Position))
+			}
+		}
+		distances[id] = nearest
+	}
+	return distances
+}
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRelatedNeighborDistances(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage *Position
+		want    float64
+	}{
+		{"isolated", nil, math.Inf(1)},
+		{"far", &Position{X: 18, Z: 18}, math.Sqrt(18*18 + 18*18)},
+		{"clustered", &Position{X: 2}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(20, 2, 20)
+			place(t, base, "workbench", StructureNameWorkbench, Position{})
+			// A bed is nearby but unrelated
+			place(t, base, "bed", StructureNamePalBed, Position{Z: 1})
+			if tt.storage != nil {
+				place(t, base, "storage", StructureNameStorage, *tt.storage)
+			}
+
+			if got := base.RelatedNeighborDistances()["workbench"]; got != tt.want {
+				t.Errorf("workbench distance = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Relocation moves an item to a new position
+type Relocation struct {
+	ItemID string
+	NewPos Position
+}
+
+// MinimalRelocationPlan computes a small set of moves that resolves every
+// overlap between the base's items, for example after importing a layout.
+// Fixed items come first, then the rest from highest to lowest priority
+// (then by ID): each
+// item keeps its position if it still fits among the items kept so far,
+// clear of reserved cells and with the clearance its type needs, and is
+// otherwise moved to the nearest free position by Manhattan distance.
+// High-priority items are therefore moved least. The base itself is not
+// modified. An error is returned if some item cannot be fit anywhere.
+func (b *Base) MinimalRelocationPlan() ([]Relocation, error) {
+	items := make([]*Item, 0, len(b.Items))
+	for _, item := range b.Items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].IsFixed() != items[j].IsFixed() {
+			return items[i].IsFixed()
+		}
+		if items[i].Priority != items[j].Priority {
+			return items[i].Priority > items[j].Priority
+		}
+		return items[i].ID < items[j].ID
+	})
+
+	// Replay the items into an empty base with the same usable area,
+	// walkway reservations and clearance rules
+	scratch := NewBase(b.Width, b.Height, b.Depth)
+	for itemType, clearance := range b.ClearanceCells {
+		scratch.ClearanceCells[itemType] = clearance
+	}
+	for pos := range b.unbuildable {
+		scratch.unbuildable[pos] = true
+	}
+	for pos := range b.reserved {
+		scratch.reserved[pos] = true
+	}
+
+	var plan []Relocation
+	for _, item := range items {
+		candidate := *item
+		if scratch.CanPlaceItem(&candidate) {
+			scratch.PlaceItem(&candidate)
+			continue
+		}
+
+		if item.IsFixed() {
+			return nil, fmt.Errorf("fixed item %s overlaps another fixed item", item.ID)
+		}
+
+		best, found := Position{}, false
+		bestDistance := 0
+		for _, pos := range scratch.GetFreePositions() {
+			candidate.Position = pos
+			if !scratch.CanPlaceItem(&candidate) {
+				continue
+			}
+			distance := pos.ManhattanDistance(item.Position)
+			if !found || distance < bestDistance {
+				best, bestDistance, found = pos, distance, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no free position to relocate item %s", item.ID)
+		}
+
+		candidate.Position = best
+		scratch.PlaceItem(&candidate)
+		plan = append(plan, Relocation{ItemID: item.ID, NewPos: best})
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,115 @@
+package types
+
+import "testing"
+
+// overlappingBase returns a base whose items were imported on top of each
+// other, bypassing PlaceItem
+func overlappingBase(items ...*Item) *Base {
+	base := NewBase(6, 1, 6)
+	for _, item := range items {
+		base.Items[item.ID] = item
+	}
+	return base
+}
+
+// applyPlan places copies of the base's items, moved as the plan says, into
+// a fresh base with the same settings, failing if any overlap remains
+func applyPlan(t *testing.T, base *Base, plan []Relocation) *Base {
+	t.Helper()
+	moved := make(map[string]Position)
+	for _, relocation := range plan {
+		moved[relocation.ItemID] = relocation.NewPos
+	}
+
+	result := NewBase(base.Width, base.Height, base.Depth)
+	for pos := range base.reserved {
+		result.Reserve(pos)
+	}
+	for _, item := range base.SortedItems() {
+		copied := *item
+		if pos, ok := moved[item.ID]; ok {
+			copied.Position = pos
+		}
+		if err := result.PlaceItem(&copied); err != nil {
+			t.Fatalf("after relocation: %v", err)
+		}
+	}
+	return result
+}
+
+func TestMinimalRelocationPlan(t *testing.T) {
+	item := func(id string, priority int, pos Position) *Item {
+		item := NewItem(id, StructureNameWoodenBarrel)
+		item.Priority = priority
+		item.Position = pos
+		return item
+	}
+
+	tests := []struct {
+		name  string
+		items []*Item
+		moved []string
+	}{
+		{"no overlap", []*Item{item("a", 1, Position{}), item("b", 1, Position{X: 1})}, nil},
+		{"low priority moves", []*Item{item("high", 9, Position{X: 2}), item("low", 1, Position{X: 2})}, []string{"low"}},
+		{"ties move the later ID", []*Item{item("a", 5, Position{X: 3}), item("b", 5, Position{X: 3})}, []string{"b"}},
+		{
+			"three on one cell",
+			[]*Item{item("mid", 5, Position{Z: 3}), item("top", 9, Position{Z: 3}), item("low", 1, Position{Z: 3})},
+			[]string{"low", "mid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := overlappingBase(tt.items...)
+			plan, err := base.MinimalRelocationPlan()
+			if err != nil {
+				t.Fatalf("MinimalRelocationPlan: %v", err)
+			}
+
+			applyPlan(t, base, plan)
+			moved := make(map[string]bool)
+			for _, relocation := range plan {
+				moved[relocation.ItemID] = true
+			}
+			if len(moved) != len(tt.moved) {
+				t.Errorf("moved %d items, want %v", len(moved), tt.moved)
+			}
+			for _, id := range tt.moved {
+				if !moved[id] {
+					t.Errorf("%s was not moved", id)
+				}
+			}
+		})
+	}
+}
+
+func TestMinimalRelocationPlanKeepsReservations(t *testing.T) {
+	a := NewItem("a", StructureNameWoodenBarrel)
+	b := NewItem("b", StructureNameWoodenBarrel)
+	base := overlappingBase(a, b)
+	// Reserve every cell next to the origin so b has to go further out
+	base.Reserve(Position{X: 1})
+	base.Reserve(Position{Z: 1})
+
+	plan, err := base.MinimalRelocationPlan()
+	if err != nil {
+		t.Fatalf("MinimalRelocationPlan: %v", err)
+	}
+	for _, relocation := range plan {
+		if base.IsReserved(relocation.NewPos) {
+			t.Errorf("%s moved onto reserved cell %s", relocation.ItemID, relocation.NewPos)
+		}
+	}
+	applyPlan(t, base, plan)
+}
+
+func TestMinimalRelocationPlanFull(t *testing.T) {
+	base := NewBase(1, 1, 1)
+	base.Items["a"] = NewItem("a", StructureNameWoodenBarrel)
+	base.Items["b"] = NewItem("b", StructureNameWoodenBarrel)
+	if _, err := base.MinimalRelocationPlan(); err == nil {
+		t.Error("expected an error when there is no room to relocate")
+	}
+}
@@ -0,0 +1,79 @@
+package types
+
+import "strings"
+
+// renderSymbols maps structures to their glyph in RenderLayer. Structures
+// not listed are drawn as 'X'.
+var renderSymbols = map[StructureName]byte{
+	StructureNamePalbox:         'P',
+	StructureNamePalBed:         'B',
+	StructureNameFoodBox:        'F',
+	StructureNameFoodPlot:       'G',
+	StructureNamePowerGenerator: 'E',
+	StructureNameAccumulator:    'A',
+	StructureNameWorkbench:      'W',
+	StructureNameStorage:        'S',
+}
+
+// rotationGlyphs marks the anchor cell of a multi-cell item with the
+// direction its rotation turns it to face, with Z increasing down the page
+var rotationGlyphs = map[int]byte{
+	0:   '>',
+	90:  'v',
+	180: '<',
+	270: '^',
+}
+
+// RenderLayer draws layer y of the base as ASCII art, one row per Z and one
+// column per X. Each item cell shows the item's symbol (see renderSymbols),
+// following its rotated footprint. Items covering more than one cell in the
+// X/Z plane show their orientation on their anchor cell as one of > v < ^
+// for 0, 90, 180 and 270 degrees. Free cells are '.', reserved walkway
+// cells '+' and unbuildable cells '#'.
+func (b *Base) RenderLayer(y int) string {
+	glyphs := make(map[Position]byte)
+	for _, item := range b.Items {
+		symbol := byte('X')
+		if name, err := item.Type.StructureName(); err == nil {
+			if s, ok := renderSymbols[name]; ok {
+				symbol = s
+			}
+		}
+		for _, pos := range item.GetOccupiedPositions() {
+			glyphs[pos] = symbol
+		}
+
+		bounds := item.EffectiveBounds()
+		if bounds.Width*bounds.Depth > 1 {
+			anchor := Position{X: item.Position.X, Y: y, Z: item.Position.Z}
+			glyph, ok := rotationGlyphs[((item.Rotation%360)+360)%360]
+			if _, covered := glyphs[anchor]; covered && ok {
+				glyphs[anchor] = glyph
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for z := 0; z < b.Depth; z++ {
+		for x := 0; x < b.Width; x++ {
+			pos := Position{X: x, Y: y, Z: z}
+			glyph, occupied := glyphs[pos]
+			switch {
+			case occupied:
+				sb.WriteByte(glyph)
+			case b.unbuildable[pos]:
+				sb.WriteByte('#')
+			case b.reserved[pos]:
+				sb.WriteByte('+')
+			default:
+				sb.WriteByte('.')
+			}
+			if x < b.Width-1 {
+				sb.WriteByte(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+package types
+
+import "testing"
+
+func TestRenderLayerRotation(t *testing.T) {
+	tests := []struct {
+		name     string
+		rotation int
+		want     string
+	}{
+		{"unrotated", 0, "" +
+			". . .\n" +
+			". > W\n" +
+			". . .\n"},
+		{"rotated 90", 90, "" +
+			". . .\n" +
+			". v .\n" +
+			". W .\n"},
+		{"rotated 180", 180, "" +
+			". . .\n" +
+			". < W\n" +
+			". . .\n"},
+		{"rotated 270", 270, "" +
+			". . .\n" +
+			". ^ .\n" +
+			". W .\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(3, 1, 3)
+			bench := NewItem("bench", StructureNameWorkbench)
+			bench.Position = Position{X: 1, Z: 1}
+			bench.Rotation = tt.rotation
+			if err := base.PlaceItem(bench); err != nil {
+				t.Fatalf("PlaceItem: %v", err)
+			}
+			if got := base.RenderLayer(0); got != tt.want {
+				t.Errorf("RenderLayer(0) =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderLayerCells(t *testing.T) {
+	base := NewBase(4, 2, 2)
+	place(t, base, "bed", StructureNamePalBed, Position{X: 0, Z: 0})
+	place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 1, Z: 0})
+	place(t, base, "upper", StructureNamePalBed, Position{X: 2, Y: 1, Z: 0})
+	base.SetBuildable(Position{X: 3, Z: 1}, false)
+	base.Reserve(Position{X: 0, Z: 1})
+
+	want := "" +
+		"B X . .\n" +
+		"+ . . #\n"
+	if got := base.RenderLayer(0); got != want {
+		t.Errorf("RenderLayer(0) =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"math"
+	"sort"
+)
+
+// StorageOutOfRange returns the storage structures the item retrieval
+// machine cannot pull from: those whose closest cell lies farther than
+// radius from the machine's closest cell. Other retrieval machines are not
+// storage and never listed. The result is sorted by ID.
+func (b *Base) StorageOutOfRange(machine *Item, radius float64) []*Item {
+	var out []*Item
+	for _, item := range b.ItemsByCategory(StructureCategoryStorage) {
+		if item.ID == machine.ID || isRetrievalMachine(item) {
+			continue
+		}
+		if footprintDistance(machine, item) > radius {
+			out = append(out, item)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// isRetrievalMachine reports whether the item is an item retrieval machine
+func isRetrievalMachine(item *Item) bool {
+	name, err := item.Type.StructureName()
+	return err == nil && name == StructureNameItemRetrievalMachine
+}
+
+// footprintDistance returns the Euclidean distance between the closest
+// cells of two items
+func footprintDistance(a, b *Item) float64 {
+	nearest := math.Inf(1)
+	for _, cell := range a.GetOccupiedPositions() {
+		for _, other := range b.GetOccupiedPositions() {
+			nearest = math.Min(nearest, cell.Distance(other))
+		}
+	}
+	return nearest
+}
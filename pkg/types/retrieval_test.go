@@ -0,0 +1,39 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStorageOutOfRange(t *testing.T) {
+	base := NewBase(20, 2, 4)
+	machine := place(t, base, "machine", StructureNameItemRetrievalMachine, Position{X: 0, Z: 0})
+	place(t, base, "other_machine", StructureNameItemRetrievalMachine, Position{X: 19, Z: 0})
+	place(t, base, "near", StructureNameWoodenBarrel, Position{X: 3, Z: 0})
+	place(t, base, "edge", StructureNameStorage, Position{X: 5, Z: 0})
+	place(t, base, "far_b", StructureNameWoodenBarrel, Position{X: 12, Z: 3})
+	place(t, base, "far_a", StructureNameStorage, Position{X: 15, Z: 0})
+	place(t, base, "bed", StructureNamePalBed, Position{X: 18, Z: 3})
+
+	tests := []struct {
+		name   string
+		radius float64
+		want   []string
+	}{
+		{"zero radius", 0, []string{"edge", "far_a", "far_b", "near"}},
+		{"boundary is inside", 5, []string{"far_a", "far_b"}},
+		{"just short of the boundary", 4.9, []string{"edge", "far_a", "far_b"}},
+		{"everything", 30, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range base.StorageOutOfRange(machine, tt.radius) {
+				got = append(got, item.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("StorageOutOfRange(%v) = %v, want %v", tt.radius, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"math"
+	"sort"
+)
+
+// spatialBucketSize is the edge length, in cells, of a spatial index bucket
+const spatialBucketSize = 4
+
+// spatialIndex buckets items by the uniform grid cell their Position falls
+// in, so radius queries only visit nearby buckets
+type spatialIndex struct {
+	buckets map[Position][]*Item
+
+	// extent bounds the footprint of every item ever inserted, at any
+	// rotation, so region queries know how far before a region an
+	// overlapping item's Position can lie. It never shrinks.
+	extent BoundingBox
+}
+
+// newSpatialIndex creates an empty spatial index
+func newSpatialIndex() *spatialIndex {
+	return &spatialIndex{buckets: make(map[Position][]*Item)}
+}
+
+// bucketOf returns the bucket coordinates containing a position
+func bucketOf(pos Position) Position {
+	return Position{
+		X: floorDiv(pos.X, spatialBucketSize),
+		Y: floorDiv(pos.Y, spatialBucketSize),
+		Z: floorDiv(pos.Z, spatialBucketSize),
+	}
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+// insert adds an item under its current position
+func (si *spatialIndex) insert(item *Item) {
+	key := bucketOf(item.Position)
+	si.buckets[key] = append(si.buckets[key], item)
+
+	horizontal := max(item.Bounds.Width, item.Bounds.Depth)
+	si.extent.Width = max(si.extent.Width, horizontal)
+	si.extent.Height = max(si.extent.Height, item.Bounds.Height)
+	si.extent.Depth = max(si.extent.Depth, horizontal)
+}
+
+// remove deletes an item that was indexed at the given position
+func (si *spatialIndex) remove(item *Item, pos Position) {
+	key := bucketOf(pos)
+	bucket := si.buckets[key]
+	for i, candidate := range bucket {
+		if candidate == item {
+			bucket = append(bucket[:i], bucket[i+1:]...)
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(si.buckets, key)
+	} else {
+		si.buckets[key] = bucket
+	}
+}
+
+// ItemsNear returns the items whose Position lies within radius (Euclidean)
+// of pos, ordered by ID. The lookup only visits the index buckets that
+// overlap the query sphere. Items added to Items directly rather than
+// through PlaceItem are not indexed.
+func (b *Base) ItemsNear(pos Position, radius float64) []*Item {
+	if radius < 0 {
+		return nil
+	}
+
+	reach := int(math.Ceil(radius))
+	low := bucketOf(Position{X: pos.X - reach, Y: pos.Y - reach, Z: pos.Z - reach})
+	high := bucketOf(Position{X: pos.X + reach, Y: pos.Y + reach, Z: pos.Z + reach})
+
+	var near []*Item
+	for x := low.X; x <= high.X; x++ {
+		for y := low.Y; y <= high.Y; y++ {
+			for z := low.Z; z <= high.Z; z++ {
+				for _, item := range b.index.buckets[Position{X: x, Y: y, Z: z}] {
+					if item.Position.Distance(pos) <= radius {
+						near = append(near, item)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(near, func(i, j int) bool {
+		return near[i].ID < near[j].ID
+	})
+
+	return near
+}
+
+// ItemsInRegion returns the items whose footprint overlaps the box between
+// low and high, both corners inclusive, ordered by ID. The corners may be
+// given in any order. The index only knows item anchors, so the lookup
+// visits the buckets of the region widened back by the largest footprint
+// placed and tests their items with Intersects. As with ItemsNear, items
+// added to Items directly rather than through PlaceItem are not indexed.
+func (b *Base) ItemsInRegion(low, high Position) []*Item {
+	region := Item{
+		Position: Position{X: min(low.X, high.X), Y: min(low.Y, high.Y), Z: min(low.Z, high.Z)},
+		Bounds: BoundingBox{
+			Width:  abs(high.X-low.X) + 1,
+			Height: abs(high.Y-low.Y) + 1,
+			Depth:  abs(high.Z-low.Z) + 1,
+		},
+	}
+
+	// An overlapping item is anchored at most one extent less one before
+	// the region, since footprints grow from the anchor, and anchors lie
+	// inside the base
+	extent := b.index.extent
+	first := bucketOf(Position{
+		X: max(region.Position.X-max(extent.Width-1, 0), 0),
+		Y: max(region.Position.Y-max(extent.Height-1, 0), 0),
+		Z: max(region.Position.Z-max(extent.Depth-1, 0), 0),
+	})
+	last := bucketOf(Position{
+		X: min(region.Position.X+region.Bounds.Width-1, b.Width-1),
+		Y: min(region.Position.Y+region.Bounds.Height-1, b.Height-1),
+		Z: min(region.Position.Z+region.Bounds.Depth-1, b.Depth-1),
+	})
+
+	var inside []*Item
+	for x := first.X; x <= last.X; x++ {
+		for y := first.Y; y <= last.Y; y++ {
+			for z := first.Z; z <= last.Z; z++ {
+				for _, item := range b.index.buckets[Position{X: x, Y: y, Z: z}] {
+					if item.Intersects(region) {
+						inside = append(inside, item)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(inside, func(i, j int) bool {
+		return inside[i].ID < inside[j].ID
+	})
+
+	return inside
+}
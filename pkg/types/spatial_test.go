@@ -0,0 +1,203 @@
+package types
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestItemsNear(t *testing.T) {
+	base := NewBase(12, 2, 12)
+	place(t, base, "origin", StructureNameWoodenBarrel, Position{X: 0, Z: 0})
+	place(t, base, "adjacent", StructureNameWoodenBarrel, Position{X: 1, Z: 0})
+	place(t, base, "diagonal", StructureNameWoodenBarrel, Position{X: 3, Z: 4})
+	place(t, base, "next_bucket", StructureNameWoodenBarrel, Position{X: 5, Z: 0})
+	place(t, base, "far", StructureNameWoodenBarrel, Position{X: 11, Y: 1, Z: 11})
+
+	tests := []struct {
+		name   string
+		pos    Position
+		radius float64
+		want   []string
+	}{
+		{"negative radius", Position{}, -1, nil},
+		{"zero radius", Position{}, 0, []string{"origin"}},
+		{"unit radius", Position{}, 1, []string{"adjacent", "origin"}},
+		{"boundary is inclusive", Position{}, 5, []string{"adjacent", "diagonal", "next_bucket", "origin"}},
+		{"across buckets", Position{X: 4, Z: 0}, 1, []string{"next_bucket"}},
+		{"empty area", Position{X: 8, Z: 8}, 2, nil},
+		{"whole base", Position{X: 6, Y: 1, Z: 6}, 20, []string{"adjacent", "diagonal", "far", "next_bucket", "origin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range base.ItemsNear(tt.pos, tt.radius) {
+				got = append(got, item.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ItemsNear(%s, %v) = %v, want %v", tt.pos, tt.radius, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemsNearFollowsMovesAndRemovals(t *testing.T) {
+	base := NewBase(12, 1, 12)
+	place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 1, Z: 1})
+
+	if err := base.MoveItem("barrel", Position{X: 10, Z: 10}, 0); err != nil {
+		t.Fatalf("MoveItem: %v", err)
+	}
+	if near := base.ItemsNear(Position{X: 1, Z: 1}, 2); len(near) != 0 {
+		t.Errorf("moved item still found at its old position: %v", near)
+	}
+	if near := base.ItemsNear(Position{X: 10, Z: 10}, 0); len(near) != 1 {
+		t.Errorf("moved item not found at its new position")
+	}
+
+	if err := base.RemoveItem("barrel"); err != nil {
+		t.Fatalf("RemoveItem: %v", err)
+	}
+	if near := base.ItemsNear(Position{X: 10, Z: 10}, 2); len(near) != 0 {
+		t.Errorf("removed item still found: %v", near)
+	}
+}
+
+func TestItemsNearMatchesLinearScan(t *testing.T) {
+	base, rng := randomBase(t, 200)
+	for i := 0; i < 200; i++ {
+		pos := Position{X: rng.Intn(base.Width), Y: rng.Intn(base.Height), Z: rng.Intn(base.Depth)}
+		radius := rng.Float64() * 8
+
+		var want []string
+		for _, item := range base.SortedItems() {
+			if item.Position.Distance(pos) <= radius {
+				want = append(want, item.ID)
+			}
+		}
+		var got []string
+		for _, item := range base.ItemsNear(pos, radius) {
+			got = append(got, item.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("ItemsNear(%s, %v) = %v, a linear scan finds %v", pos, radius, got, want)
+		}
+	}
+}
+
+// randomBase fills a 20x16x20 base with up to n barrels at seeded random
+// positions
+func randomBase(tb testing.TB, n int) (*Base, *rand.Rand) {
+	tb.Helper()
+	base := NewBase(20, 16, 20)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < n; i++ {
+		item := NewItem(fmt.Sprintf("barrel_%d", i), StructureNameWoodenBarrel)
+		item.Position = Position{X: rng.Intn(base.Width), Y: rng.Intn(base.Height), Z: rng.Intn(base.Depth)}
+		if base.CanPlaceItem(item) {
+			if err := base.PlaceItem(item); err != nil {
+				tb.Fatalf("placing %s: %v", item.ID, err)
+			}
+		}
+	}
+	return base, rng
+}
+
+func BenchmarkItemsNear(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	pos := Position{X: 10, Y: 8, Z: 10}
+	b.ReportAllocs()
+	for b.Loop() {
+		base.ItemsNear(pos, 3)
+	}
+}
+
+// BenchmarkItemsNearLinearScan is the scan over every item that ItemsNear
+// replaces, for comparison
+func BenchmarkItemsNearLinearScan(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	pos := Position{X: 10, Y: 8, Z: 10}
+	b.ReportAllocs()
+	for b.Loop() {
+		var near []*Item
+		for _, item := range base.SortedItems() {
+			if item.Position.Distance(pos) <= 3 {
+				near = append(near, item)
+			}
+		}
+		_ = near
+	}
+}
+
+func TestItemsInRegion(t *testing.T) {
+	base := NewBase(12, 4, 12)
+	place(t, base, "inside", StructureNameWoodenBarrel, Position{X: 5, Z: 5})
+	place(t, base, "palbox", StructureNamePalbox, Position{X: 2, Z: 2})
+	bench := NewItem("bench", StructureNameWorkbench)
+	bench.Position, bench.Rotation = Position{X: 8, Z: 3}, 90
+	if err := base.PlaceItem(bench); err != nil {
+		t.Fatalf("placing bench: %v", err)
+	}
+	place(t, base, "outside", StructureNameWoodenBarrel, Position{X: 11, Z: 11})
+	place(t, base, "above", StructureNameWoodenBarrel, Position{X: 5, Y: 3, Z: 5})
+
+	tests := []struct {
+		name      string
+		low, high Position
+		want      []string
+	}{
+		{"fully inside", Position{X: 4, Z: 4}, Position{X: 6, Z: 6}, []string{"inside"}},
+		{"partially overlapping from before", Position{X: 3, Z: 3}, Position{X: 6, Z: 6}, []string{"inside", "palbox"}},
+		{"rotated item's far cell", Position{X: 8, Z: 4}, Position{X: 8, Z: 4}, []string{"bench"}},
+		{"beside the rotated item", Position{X: 9, Z: 3}, Position{X: 9, Z: 4}, nil},
+		{"fully outside", Position{X: 9, Z: 8}, Position{X: 10, Z: 10}, nil},
+		{"corners in any order", Position{X: 6, Y: 3, Z: 6}, Position{X: 4, Z: 4}, []string{"above", "inside"}},
+		{"reaching past the base", Position{X: 10, Z: 10}, Position{X: 20, Z: 20}, []string{"outside"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range base.ItemsInRegion(tt.low, tt.high) {
+				got = append(got, item.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ItemsInRegion(%s, %s) = %v, want %v", tt.low, tt.high, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemsInRegionMatchesLinearScan(t *testing.T) {
+	base, rng := randomBase(t, 200)
+	for i := 0; i < 200; i++ {
+		low := Position{X: rng.Intn(base.Width), Y: rng.Intn(base.Height), Z: rng.Intn(base.Depth)}
+		high := Position{X: low.X + rng.Intn(6), Y: low.Y + rng.Intn(6), Z: low.Z + rng.Intn(6)}
+		region := Item{Position: low, Bounds: BoundingBox{Width: high.X - low.X + 1, Height: high.Y - low.Y + 1, Depth: high.Z - low.Z + 1}}
+
+		var want []string
+		for _, item := range base.SortedItems() {
+			if item.Intersects(region) {
+				want = append(want, item.ID)
+			}
+		}
+		slices.Sort(want)
+		var got []string
+		for _, item := range base.ItemsInRegion(low, high) {
+			got = append(got, item.ID)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("ItemsInRegion(%s, %s) = %v, a linear scan finds %v", low, high, got, want)
+		}
+	}
+}
+
+func BenchmarkItemsInRegion(b *testing.B) {
+	base, _ := randomBase(b, 400)
+	low, high := Position{X: 8, Y: 6, Z: 8}, Position{X: 11, Y: 9, Z: 11}
+	b.ReportAllocs()
+	for b.Loop() {
+		base.ItemsInRegion(low, high)
+	}
+}
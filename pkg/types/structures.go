@@ -0,0 +1,622 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+// StructureCategory enumerates the high level categories used by
+// Palworld.gg to group structures.  These values map directly to
+// the options in the "Structure Type" dropdown on the website and
+// should not be renamed without also updating any corresponding
+// constants.
+//
+// The names follow the site’s capitalization and spacing to ease
+// serialization and comparison.  See
+// https://palworld.gg/structures for the authoritative list.
+type StructureCategory string
+
+const (
+	StructureCategoryFood           StructureCategory = "Food"
+	StructureCategoryFoundation     StructureCategory = "Foundation"
+	StructureCategoryDefense        StructureCategory = "Defense"
+	StructureCategoryInfrastructure StructureCategory = "Infrastructure"
+	StructureCategoryStorage        StructureCategory = "Storage"
+	StructureCategoryPals           StructureCategory = "Pals"
+	StructureCategoryLight          StructureCategory = "Light"
+	StructureCategoryProduction     StructureCategory = "Production"
+	StructureCategoryFurniture      StructureCategory = "Furniture"
+	StructureCategoryOther          StructureCategory = "Other"
+)
+
+// StructureName identifies a specific placeable item within a
+// StructureCategory.  Items keep their ItemType, which is resolved to
+// a StructureName through ItemType.StructureName, allowing items to be
+// grouped by category without conflating the category and the
+// specific item.  When adding new structures, prefer using the
+// canonical name from Palworld.gg and group it with the appropriate
+// category in StructureDefinitions.
+type StructureName string
+
+const (
+	// Food structures
+	StructureNameCampfire         StructureName = "campfire"
+	StructureNameCookingPot       StructureName = "cooking_pot"
+	StructureNameColdFoodBox      StructureName = "cold_food_box"
+	StructureNameElectricKitchen  StructureName = "electric_kitchen"
+	StructureNameBerryPlantation  StructureName = "berry_plantation"
+	StructureNameCarrotPlantation StructureName = "carrot_plantation"
+
+	// Foundation/defense structures
+	StructureNameStoneDefensiveWall  StructureName = "stone_defensive_wall"
+	StructureNameMetalDefensiveWall  StructureName = "metal_defensive_wall"
+	StructureNameWoodenDefensiveWall StructureName = "wooden_defensive_wall"
+	StructureNameGlassWallAndDoor    StructureName = "glass_wall_and_door"
+	StructureNameGlassFence          StructureName = "glass_fence"
+	StructureNameGlassSlantedRoof    StructureName = "glass_slanted_roof"
+
+	// Product/production structures
+	StructureNameProductionAssemblyLineII     StructureName = "production_assembly_line_ii"
+	StructureNameAdvancedCivilizationWorkshop StructureName = "advanced_civilization_workshop"
+	StructureNameGoldCoinAssemblyLine         StructureName = "gold_coin_assembly_line"
+
+	// Light sources
+	StructureNameJapanesePaperLantern StructureName = "japanese_paper_lantern"
+	StructureNameWallTorch            StructureName = "wall_torch"
+	StructureNameStandingTorch        StructureName = "standing_torch"
+	StructureNameLamp                 StructureName = "lamp"
+	StructureNameCeilingLamp          StructureName = "ceiling_lamp"
+
+	// Furniture
+	StructureNameRedMetalBarrel        StructureName = "red_metal_barrel"
+	StructureNameBlueMetalBarrel       StructureName = "blue_metal_barrel"
+	StructureNameGreenMetalBarrel      StructureName = "green_metal_barrel"
+	StructureNameAntiqueBathtub        StructureName = "antique_bathtub"
+	StructureNameFreePalAllianceBanner StructureName = "free_pal_alliance_banner"
+
+	// Storage
+	StructureNameWoodenBarrel         StructureName = "wooden_barrel"
+	StructureNameItemRetrievalMachine StructureName = "item_retrieval_machine"
+
+	// Pals-related structures
+	StructureNameMonitoringStand     StructureName = "monitoring_stand"
+	StructureNamePalboxControlDevice StructureName = "palbox_control_device"
+	StructureNamePalBed              StructureName = "pal_bed"
+	StructureNamePalSphereWorkbench  StructureName = "pal_sphere_workbench"
+	StructureNamePalbox              StructureName = "palbox"
+
+	// Other existing structures from the original code
+	StructureNameFoodBox                   StructureName = "food_box"
+	StructureNameFoodPlot                  StructureName = "food_plot"
+	StructureNamePowerGenerator            StructureName = "power_generator"
+	StructureNameAccumulator               StructureName = "accumulator"
+	StructureNameOuterWall                 StructureName = "outer_wall"
+	StructureNameWorkbench                 StructureName = "workbench"
+	StructureNameStorage                   StructureName = "storage"
+	StructureNameFurnace                   StructureName = "furnace"
+	StructureNameMedievalMedicineWorkbench StructureName = "medieval_medicine_workbench"
+	StructureNameElectricMedicineWorkbench StructureName = "electric_medicine_workbench"
+	StructureNameAdvancedMedicineWorkbench StructureName = "advanced_medicine_workbench"
+	StructureNameBreedingFarm              StructureName = "breeding_farm"
+	StructureNameIncubator                 StructureName = "incubator"
+)
+
+// StructureDefinition captures metadata for a structure, including
+// its canonical name, high-level category, human-readable description,
+// build work (abstract work units), material costs (by material name),
+// the footprint and placement priority items get by default, and the
+// technology level that unlocks it.
+//
+// Use canonical names from Palworld.gg for both name and category fields.
+// The built-in table records build work but no material costs; those come
+// from a table loaded with LoadStructureDefinitions, and without one build
+// plans track work only.
+type StructureDefinition struct {
+	Name            StructureName     `json:"name"`
+	Category        StructureCategory `json:"category"`
+	Description     string            `json:"description,omitempty"`
+	BuildWork       int               `json:"build_work"`
+	MaterialCost    map[string]int    `json:"material_cost,omitempty"`
+	DefaultBounds   BoundingBox       `json:"default_bounds"`
+	DefaultPriority int               `json:"default_priority"`
+
+	// TechLevel is the technology level at which players unlock the
+	// structure. Zero means it is always available.
+	TechLevel int `json:"tech_level,omitempty"`
+
+	// Passable structures, such as doors, occupy their cells for placement
+	// but do not block movement
+	Passable bool `json:"passable,omitempty"`
+
+	// AccessSide is the face Pals use the structure from, which must stay
+	// open; SideNone means any side will do
+	AccessSide Side `json:"access_side,omitempty"`
+
+	// Exposure is whether the structure needs open sky or a roof over it
+	Exposure Exposure `json:"exposure,omitempty"`
+
+	// DependsOn lists structures that must be built before this one is of
+	// any use, such as the power generator an accumulator stores power
+	// from. Greedy placement places them first.
+	DependsOn []StructureName `json:"depends_on,omitempty"`
+}
+
+// StructureDefinitions maps each StructureName to its StructureDefinition.
+// When adding new structures, append new entries here.
+var StructureDefinitions = map[StructureName]StructureDefinition{
+	// Food
+	StructureNameCampfire: {
+		Name:            StructureNameCampfire,
+		Category:        StructureCategoryFood,
+		BuildWork:       50,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 70,
+		TechLevel:       1,
+	},
+	StructureNameCookingPot: {
+		Name:            StructureNameCookingPot,
+		Category:        StructureCategoryFood,
+		BuildWork:       300,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 70,
+		TechLevel:       17,
+		AccessSide:      SideFront,
+	},
+	StructureNameColdFoodBox: {
+		Name:            StructureNameColdFoodBox,
+		Category:        StructureCategoryFood,
+		BuildWork:       600,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 80,
+		TechLevel:       24,
+	},
+	StructureNameElectricKitchen: {
+		Name:            StructureNameElectricKitchen,
+		Category:        StructureCategoryFood,
+		BuildWork:       2000,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 1, Depth: 1},
+		DefaultPriority: 70,
+		TechLevel:       40,
+	},
+	StructureNameBerryPlantation: {
+		Name:            StructureNameBerryPlantation,
+		Category:        StructureCategoryFood,
+		BuildWork:       200,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 1, Depth: 2},
+		DefaultPriority: 75,
+		TechLevel:       5,
+		Exposure:        ExposureOutdoors,
+	},
+	StructureNameCarrotPlantation: {
+		Name:            StructureNameCarrotPlantation,
+		Category:        StructureCategoryFood,
+		BuildWork:       400,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 1, Depth: 2},
+		DefaultPriority: 75,
+		TechLevel:       27,
+		Exposure:        ExposureOutdoors,
+	},
+
+	// Foundation/Defense
+	StructureNameStoneDefensiveWall: {
+		Name:            StructureNameStoneDefensiveWall,
+		Category:        StructureCategoryFoundation,
+		BuildWork:       300,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 40,
+		TechLevel:       23,
+	},
+	StructureNameMetalDefensiveWall: {
+		Name:            StructureNameMetalDefensiveWall,
+		Category:        StructureCategoryFoundation,
+		BuildWork:       600,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 40,
+		TechLevel:       40,
+	},
+	StructureNameWoodenDefensiveWall: {
+		Name:            StructureNameWoodenDefensiveWall,
+		Category:        StructureCategoryFoundation,
+		BuildWork:       100,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 40,
+		TechLevel:       12,
+	},
+	StructureNameGlassWallAndDoor: {
+		Name:            StructureNameGlassWallAndDoor,
+		Category:        StructureCategoryFoundation,
+		BuildWork:       400,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 40,
+		TechLevel:       45,
+		Passable:        true,
+	},
+	StructureNameGlassFence: {
+		Name:            StructureNameGlassFence,
+		Category:        StructureCategoryFoundation,
+		BuildWork:       200,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 35,
+		TechLevel:       45,
+	},
+	StructureNameGlassSlantedRoof: {
+		Name:            StructureNameGlassSlantedRoof,
+		Category:        StructureCategoryFoundation,
+		BuildWork:       200,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 30,
+		TechLevel:       45,
+	},
+
+	// Product/production
+	StructureNameProductionAssemblyLineII: {
+		Name:            StructureNameProductionAssemblyLineII,
+		Category:        StructureCategoryProduction,
+		BuildWork:       3000,
+		DefaultBounds:   BoundingBox{Width: 3, Height: 2, Depth: 2},
+		DefaultPriority: 60,
+		TechLevel:       48,
+	},
+	StructureNameAdvancedCivilizationWorkshop: {
+		Name:            StructureNameAdvancedCivilizationWorkshop,
+		Category:        StructureCategoryProduction,
+		BuildWork:       2500,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 2, Depth: 2},
+		DefaultPriority: 60,
+		TechLevel:       38,
+	},
+	StructureNameGoldCoinAssemblyLine: {
+		Name:            StructureNameGoldCoinAssemblyLine,
+		Category:        StructureCategoryProduction,
+		BuildWork:       2000,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 2, Depth: 2},
+		DefaultPriority: 55,
+		TechLevel:       32,
+	},
+
+	// Light sources
+	StructureNameJapanesePaperLantern: {
+		Name:            StructureNameJapanesePaperLantern,
+		Category:        StructureCategoryLight,
+		BuildWork:       50,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 20,
+		TechLevel:       9,
+	},
+	StructureNameWallTorch: {
+		Name:            StructureNameWallTorch,
+		Category:        StructureCategoryLight,
+		BuildWork:       20,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 20,
+		TechLevel:       4,
+	},
+	StructureNameStandingTorch: {
+		Name:            StructureNameStandingTorch,
+		Category:        StructureCategoryLight,
+		BuildWork:       30,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 20,
+		TechLevel:       4,
+	},
+	StructureNameLamp: {
+		Name:            StructureNameLamp,
+		Category:        StructureCategoryLight,
+		BuildWork:       300,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 25,
+		TechLevel:       29,
+	},
+	StructureNameCeilingLamp: {
+		Name:            StructureNameCeilingLamp,
+		Category:        StructureCategoryLight,
+		BuildWork:       300,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 25,
+		TechLevel:       29,
+	},
+
+	// Furniture
+	StructureNameRedMetalBarrel: {
+		Name:            StructureNameRedMetalBarrel,
+		Category:        StructureCategoryFurniture,
+		BuildWork:       100,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 20,
+		TechLevel:       30,
+	},
+	StructureNameBlueMetalBarrel: {
+		Name:            StructureNameBlueMetalBarrel,
+		Category:        StructureCategoryFurniture,
+		BuildWork:       100,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 20,
+		TechLevel:       30,
+	},
+	StructureNameGreenMetalBarrel: {
+		Name:            StructureNameGreenMetalBarrel,
+		Category:        StructureCategoryFurniture,
+		BuildWork:       100,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 20,
+		TechLevel:       30,
+	},
+	StructureNameAntiqueBathtub: {
+		Name:            StructureNameAntiqueBathtub,
+		Category:        StructureCategoryFurniture,
+		BuildWork:       300,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 1, Depth: 1},
+		DefaultPriority: 20,
+		TechLevel:       25,
+	},
+	StructureNameFreePalAllianceBanner: {
+		Name:            StructureNameFreePalAllianceBanner,
+		Category:        StructureCategoryFurniture,
+		BuildWork:       100,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 15,
+		TechLevel:       12,
+	},
+
+	// Storage
+	StructureNameWoodenBarrel: {
+		Name:            StructureNameWoodenBarrel,
+		Category:        StructureCategoryStorage,
+		BuildWork:       50,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 65,
+		TechLevel:       2,
+	},
+	StructureNameItemRetrievalMachine: {
+		Name:            StructureNameItemRetrievalMachine,
+		Category:        StructureCategoryStorage,
+		BuildWork:       1500,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 65,
+		TechLevel:       50,
+	},
+
+	// Pals
+	StructureNameMonitoringStand: {
+		Name:            StructureNameMonitoringStand,
+		Category:        StructureCategoryPals,
+		BuildWork:       100,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 50,
+		TechLevel:       10,
+	},
+	StructureNamePalboxControlDevice: {
+		Name:            StructureNamePalboxControlDevice,
+		Category:        StructureCategoryPals,
+		BuildWork:       500,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 50,
+		TechLevel:       1,
+	},
+	StructureNamePalBed: {
+		Name:            StructureNamePalBed,
+		Category:        StructureCategoryPals,
+		BuildWork:       50,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 90,
+		TechLevel:       2,
+	},
+	StructureNamePalSphereWorkbench: {
+		Name:            StructureNamePalSphereWorkbench,
+		Category:        StructureCategoryPals,
+		BuildWork:       150,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 60,
+		TechLevel:       16,
+		AccessSide:      SideFront,
+	},
+	StructureNamePalbox: {
+		Name:            StructureNamePalbox,
+		Category:        StructureCategoryPals,
+		BuildWork:       100,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 2, Depth: 2},
+		DefaultPriority: 100,
+		TechLevel:       1,
+	},
+
+	// Other miscellaneous items from original code
+	StructureNameFoodBox: {
+		Name:            StructureNameFoodBox,
+		Category:        StructureCategoryFood,
+		BuildWork:       50,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 80,
+		TechLevel:       4,
+	},
+	StructureNameFoodPlot: {
+		Name:            StructureNameFoodPlot,
+		Category:        StructureCategoryFood,
+		BuildWork:       200,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 75,
+		TechLevel:       5,
+		Exposure:        ExposureOutdoors,
+	},
+	StructureNamePowerGenerator: {
+		Name:            StructureNamePowerGenerator,
+		Category:        StructureCategoryInfrastructure,
+		BuildWork:       800,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 85,
+		TechLevel:       33,
+	},
+	StructureNameAccumulator: {
+		Name:            StructureNameAccumulator,
+		Category:        StructureCategoryInfrastructure,
+		BuildWork:       600,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 80,
+		TechLevel:       35,
+		DependsOn:       []StructureName{StructureNamePowerGenerator},
+	},
+	StructureNameOuterWall: {
+		Name:            StructureNameOuterWall,
+		Category:        StructureCategoryFoundation,
+		BuildWork:       300,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 40,
+		TechLevel:       10,
+	},
+	StructureNameWorkbench: {
+		Name:            StructureNameWorkbench,
+		Category:        StructureCategoryProduction,
+		BuildWork:       50,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 1, Depth: 1},
+		DefaultPriority: 70,
+		TechLevel:       1,
+		AccessSide:      SideFront,
+	},
+	StructureNameStorage: {
+		Name:            StructureNameStorage,
+		Category:        StructureCategoryStorage,
+		BuildWork:       50,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 2, Depth: 1},
+		DefaultPriority: 65,
+		TechLevel:       2,
+	},
+	StructureNameFurnace: {
+		Name:            StructureNameFurnace,
+		Category:        StructureCategoryProduction,
+		BuildWork:       400,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 60,
+		TechLevel:       10,
+	},
+	StructureNameMedievalMedicineWorkbench: {
+		Name:            StructureNameMedievalMedicineWorkbench,
+		Category:        StructureCategoryProduction,
+		BuildWork:       300,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 55,
+		TechLevel:       11,
+		AccessSide:      SideFront,
+	},
+	StructureNameElectricMedicineWorkbench: {
+		Name:            StructureNameElectricMedicineWorkbench,
+		Category:        StructureCategoryProduction,
+		BuildWork:       1200,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 55,
+		TechLevel:       38,
+		AccessSide:      SideFront,
+	},
+	StructureNameAdvancedMedicineWorkbench: {
+		Name:            StructureNameAdvancedMedicineWorkbench,
+		Category:        StructureCategoryProduction,
+		BuildWork:       2500,
+		DefaultBounds:   BoundingBox{Width: 2, Height: 1, Depth: 1},
+		DefaultPriority: 55,
+		TechLevel:       50,
+		AccessSide:      SideFront,
+	},
+	StructureNameBreedingFarm: {
+		Name:            StructureNameBreedingFarm,
+		Category:        StructureCategoryPals,
+		BuildWork:       500,
+		DefaultBounds:   BoundingBox{Width: 3, Height: 1, Depth: 3},
+		DefaultPriority: 50,
+		TechLevel:       19,
+	},
+	StructureNameIncubator: {
+		Name:            StructureNameIncubator,
+		Category:        StructureCategoryPals,
+		BuildWork:       300,
+		DefaultBounds:   BoundingBox{Width: 1, Height: 1, Depth: 1},
+		DefaultPriority: 50,
+		TechLevel:       7,
+		DependsOn:       []StructureName{StructureNameBreedingFarm},
+	},
+}
+
+// legacyItemTypeNames translates the original ItemType values to their
+// canonical Palworld.gg structure names.  Item types whose string value
+// already matches a StructureName do not need an entry here.
+var legacyItemTypeNames = map[ItemType]StructureName{
+	ItemTypePalbox:             StructureNamePalbox,
+	ItemTypePalBed:             StructureNamePalBed,
+	ItemTypeFoodBox:            StructureNameFoodBox,
+	ItemTypeFoodPlot:           StructureNameFoodPlot,
+	ItemTypePowerGenerator:     StructureNamePowerGenerator,
+	ItemTypeAccumulator:        StructureNameAccumulator,
+	ItemTypeOuterWall:          StructureNameOuterWall,
+	ItemTypeWorkbench:          StructureNameWorkbench,
+	ItemTypeStorage:            StructureNameStorage,
+	ItemTypeFurnace:            StructureNameFurnace,
+	ItemTypeCookingPot:         StructureNameCookingPot,
+	ItemTypeMedicineWorkbench:  StructureNameMedievalMedicineWorkbench,
+	ItemTypeBreedingFarm:       StructureNameBreedingFarm,
+	ItemTypeIncubator:          StructureNameIncubator,
+	ItemTypePalSphereWorkbench: StructureNamePalSphereWorkbench,
+}
+
+// StructureName resolves the item type to its canonical structure name.
+// Legacy item types are translated through legacyItemTypeNames; any other
+// value must match a key of StructureDefinitions directly.
+func (t ItemType) StructureName() (StructureName, error) {
+	if name, ok := legacyItemTypeNames[t]; ok {
+		return name, nil
+	}
+	if _, ok := StructureDefinitions[StructureName(t)]; ok {
+		return StructureName(t), nil
+	}
+	return "", fmt.Errorf("item type %q has no matching structure definition", t)
+}
+
+// Definition returns the structure definition for the item type
+func (t ItemType) Definition() (StructureDefinition, error) {
+	name, err := t.StructureName()
+	if err != nil {
+		return StructureDefinition{}, err
+	}
+	return StructureDefinitions[name], nil
+}
+
+// NewItem creates an item of the named structure with its bounds and
+// priority taken from StructureDefinitions. Unknown names get a single-cell
+// footprint and zero priority.
+func NewItem(id string, name StructureName) *Item {
+	item := &Item{
+		ID:     id,
+		Type:   ItemType(name),
+		Bounds: BoundingBox{Width: 1, Height: 1, Depth: 1},
+	}
+
+	if def, ok := StructureDefinitions[name]; ok {
+		item.Bounds = def.DefaultBounds
+		item.Priority = def.DefaultPriority
+	}
+
+	return item
+}
+
+// ItemsByName returns the placed items whose type resolves to the named
+// structure, sorted by ID
+func (b *Base) ItemsByName(name StructureName) []*Item {
+	var items []*Item
+	for _, item := range b.Items {
+		if itemName, err := item.Type.StructureName(); err == nil && itemName == name {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
+
+// ItemsByCategory returns the placed items whose structure belongs to the
+// category, sorted by ID. Items without a structure definition are never
+// returned.
+func (b *Base) ItemsByCategory(category StructureCategory) []*Item {
+	var items []*Item
+	for _, item := range b.Items {
+		if def, err := item.Type.Definition(); err == nil && def.Category == category {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
@@ -0,0 +1,114 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewItemDefaults(t *testing.T) {
+	tests := []struct {
+		name     StructureName
+		bounds   BoundingBox
+		priority int
+	}{
+		{StructureNamePalbox, BoundingBox{Width: 2, Height: 2, Depth: 2}, 100},
+		{StructureNameWorkbench, BoundingBox{Width: 2, Height: 1, Depth: 1}, 70},
+		{StructureNameFurnace, BoundingBox{Width: 1, Height: 1, Depth: 1}, 60},
+		{"no_such_structure", BoundingBox{Width: 1, Height: 1, Depth: 1}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.name), func(t *testing.T) {
+			item := NewItem("item", tt.name)
+			if item.Type != ItemType(tt.name) {
+				t.Errorf("Type = %q, want %q", item.Type, tt.name)
+			}
+			if item.Bounds != tt.bounds || item.Priority != tt.priority {
+				t.Errorf("got bounds %+v and priority %d, want %+v and %d", item.Bounds, item.Priority, tt.bounds, tt.priority)
+			}
+		})
+	}
+}
+
+func TestItemTypeStructureName(t *testing.T) {
+	tests := []struct {
+		itemType ItemType
+		want     StructureName
+		wantErr  bool
+	}{
+		{ItemTypeMedicineWorkbench, StructureNameMedievalMedicineWorkbench, false},
+		{ItemType(StructureNameFurnace), StructureNameFurnace, false},
+		{"no_such_structure", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.itemType), func(t *testing.T) {
+			got, err := tt.itemType.StructureName()
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("StructureName() = %q, %v, want %q and error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// itemsLookupBase holds a legacy-typed medicine workbench, two workbenches
+// added out of ID order, a storage box, a tree and an item of an unknown
+// type
+func itemsLookupBase(t *testing.T) *Base {
+	base := NewBase(10, 2, 10)
+	place(t, base, "wb_b", StructureNameWorkbench, Position{X: 0, Z: 0})
+	place(t, base, "wb_a", StructureNameWorkbench, Position{X: 0, Z: 2})
+	place(t, base, "box", StructureNameStorage, Position{X: 4, Z: 0})
+	for _, item := range []*Item{
+		{ID: "medicine", Type: ItemTypeMedicineWorkbench, Position: Position{X: 6, Z: 0}},
+		{ID: "tree", Type: ItemTypeTree, Position: Position{X: 8, Z: 0}},
+		{ID: "shrine", Type: ItemType("shrine"), Position: Position{X: 8, Z: 8}},
+	} {
+		item.Bounds = BoundingBox{Width: 1, Height: 1, Depth: 1}
+		if err := base.PlaceItem(item); err != nil {
+			t.Fatalf("placing %s: %v", item.ID, err)
+		}
+	}
+	return base
+}
+
+func TestItemsByName(t *testing.T) {
+	base := itemsLookupBase(t)
+	tests := []struct {
+		name StructureName
+		want []string
+	}{
+		{StructureNameWorkbench, []string{"wb_a", "wb_b"}},
+		{StructureNameMedievalMedicineWorkbench, []string{"medicine"}},
+		{StructureNameStorage, []string{"box"}},
+		{StructureNamePalbox, nil},
+		{"shrine", nil},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.name), func(t *testing.T) {
+			if got := idsOf(base.ItemsByName(tt.name)); !slices.Equal(got, tt.want) {
+				t.Errorf("ItemsByName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemsByCategory(t *testing.T) {
+	base := itemsLookupBase(t)
+	tests := []struct {
+		category StructureCategory
+		want     []string
+	}{
+		{StructureCategoryProduction, []string{"medicine", "wb_a", "wb_b"}},
+		{StructureCategoryStorage, []string{"box"}},
+		{StructureCategoryFood, nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.category), func(t *testing.T) {
+			if got := idsOf(base.ItemsByCategory(tt.category)); !slices.Equal(got, tt.want) {
+				t.Errorf("ItemsByCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
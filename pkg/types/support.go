@@ -0,0 +1,124 @@
+package types
+
+import (
+	"math"
+	"sort"
+)
+
+// SupportRatios sets how many Pal beds one support structure can sustain,
+// keyed by the structure's name
+type SupportRatios map[StructureName]int
+
+// DefaultSupportRatios feeds a workforce with one food plot per 4 beds, one
+// food box per 8 and one cooking pot per 16
+var DefaultSupportRatios = SupportRatios{
+	StructureNameFoodPlot:   4,
+	StructureNameFoodBox:    8,
+	StructureNameCookingPot: 16,
+}
+
+// supportSubstitutes lists structures that do the job of a support
+// structure, so that for example a cold food box counts as a food box
+var supportSubstitutes = map[StructureName]StructureName{
+	StructureNameBerryPlantation: StructureNameFoodPlot,
+	StructureNameColdFoodBox:     StructureNameFoodBox,
+	StructureNameElectricKitchen: StructureNameCookingPot,
+}
+
+// PlanSupport recommends how many of each support structure palBeds beds
+// need, using DefaultSupportRatios
+func PlanSupport(palBeds int) map[StructureName]int {
+	return DefaultSupportRatios.Plan(palBeds)
+}
+
+// Plan recommends how many of each support structure palBeds beds need,
+// rounding up so that every bed is covered. Ratios of zero or less are
+// skipped.
+func (r SupportRatios) Plan(palBeds int) map[StructureName]int {
+	plan := make(map[StructureName]int, len(r))
+	for name, beds := range r {
+		if beds <= 0 {
+			continue
+		}
+		plan[name] = int(math.Ceil(float64(max(palBeds, 0)) / float64(beds)))
+	}
+	return plan
+}
+
+// SupportShortfall reports a support structure the items provide too few of
+// for their beds
+type SupportShortfall struct {
+	Structure StructureName
+	Have      int
+	Need      int
+}
+
+// CheckSupport compares the support structures among items with what their
+// Pal beds need under the ratios, and returns the shortfalls sorted by
+// structure name. Substitutes such as cold food boxes count towards the
+// structure they stand in for. A balanced item list returns nil.
+func (r SupportRatios) CheckSupport(items []*Item) []SupportShortfall {
+	have := make(map[StructureName]int)
+	for _, item := range items {
+		name, err := item.Type.StructureName()
+		if err != nil {
+			continue
+		}
+		if substitute, ok := supportSubstitutes[name]; ok {
+			name = substitute
+		}
+		have[name]++
+	}
+
+	var shortfalls []SupportShortfall
+	for name, need := range r.Plan(have[StructureNamePalBed]) {
+		if have[name] < need {
+			shortfalls = append(shortfalls, SupportShortfall{Structure: name, Have: have[name], Need: need})
+		}
+	}
+	sort.Slice(shortfalls, func(i, j int) bool {
+		return shortfalls[i].Structure < shortfalls[j].Structure
+	})
+
+	return shortfalls
+}
+
+// HasSupportBelow reports whether the item rests on something solid at its
+// current position: the ground when its bottom layer is at y=0, or else a
+// Foundation structure in every cell directly beneath its footprint.
+func (b *Base) HasSupportBelow(item *Item) bool {
+	if item.Position.Y <= 0 {
+		return true
+	}
+
+	// Most candidates float over empty cells, which the grid rules out
+	// without looking at any items
+	var below []Position
+	for _, pos := range item.GetOccupiedPositions() {
+		if pos.Y != item.Position.Y {
+			continue
+		}
+		cell := Position{X: pos.X, Y: pos.Y - 1, Z: pos.Z}
+		if !b.IsPositionValid(cell) || !b.grid.get(b.cellIndex(cell)) {
+			return false
+		}
+		below = append(below, cell)
+	}
+
+	foundation := make(map[Position]bool)
+	for _, other := range b.ItemsByCategory(StructureCategoryFoundation) {
+		if other.ID == item.ID {
+			continue
+		}
+		for _, pos := range other.GetOccupiedPositions() {
+			foundation[pos] = true
+		}
+	}
+
+	for _, cell := range below {
+		if !foundation[cell] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"testing"
+)
+
+func TestPlanSupport(t *testing.T) {
+	tests := []struct {
+		name string
+		beds int
+		want map[StructureName]int
+	}{
+		{"32 beds", 32, map[StructureName]int{StructureNameFoodPlot: 8, StructureNameFoodBox: 4, StructureNameCookingPot: 2}},
+		{"partial groups round up", 5, map[StructureName]int{StructureNameFoodPlot: 2, StructureNameFoodBox: 1, StructureNameCookingPot: 1}},
+		{"no beds", 0, map[StructureName]int{StructureNameFoodPlot: 0, StructureNameFoodBox: 0, StructureNameCookingPot: 0}},
+		{"negative beds", -3, map[StructureName]int{StructureNameFoodPlot: 0, StructureNameFoodBox: 0, StructureNameCookingPot: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlanSupport(tt.beds); !maps.Equal(got, tt.want) {
+				t.Errorf("PlanSupport(%d) = %v, want %v", tt.beds, got, tt.want)
+			}
+		})
+	}
+
+	// Ratios of zero or less are skipped
+	ratios := SupportRatios{StructureNameFoodPlot: 2, StructureNameFoodBox: 0}
+	if got, want := ratios.Plan(4), map[StructureName]int{StructureNameFoodPlot: 2}; !maps.Equal(got, want) {
+		t.Errorf("Plan(4) = %v, want %v", got, want)
+	}
+}
+
+func TestCheckSupport(t *testing.T) {
+	items := func(counts map[StructureName]int) []*Item {
+		var list []*Item
+		for name, n := range counts {
+			for i := 0; i < n; i++ {
+				list = append(list, NewItem(fmt.Sprintf("%s_%d", name, i), name))
+			}
+		}
+		return list
+	}
+
+	tests := []struct {
+		name   string
+		counts map[StructureName]int
+		want   []SupportShortfall
+	}{
+		{"balanced", map[StructureName]int{StructureNamePalBed: 32, StructureNameFoodPlot: 8, StructureNameFoodBox: 4, StructureNameCookingPot: 2}, nil},
+		{"substitutes count", map[StructureName]int{StructureNamePalBed: 8, StructureNameBerryPlantation: 2, StructureNameColdFoodBox: 1, StructureNameElectricKitchen: 1}, nil},
+		{"under-provisioned", map[StructureName]int{StructureNamePalBed: 32, StructureNameFoodPlot: 4, StructureNameFoodBox: 4}, []SupportShortfall{
+			{Structure: StructureNameCookingPot, Have: 0, Need: 2},
+			{Structure: StructureNameFoodPlot, Have: 4, Need: 8},
+		}},
+		{"no beds", map[StructureName]int{StructureNameWorkbench: 1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultSupportRatios.CheckSupport(items(tt.counts)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckSupport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasSupportBelow(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, base *Base)
+		item  StructureName
+		pos   Position
+		want  bool
+	}{
+		{"bed on the ground", nil, StructureNamePalBed, Position{X: 2, Z: 2}, true},
+		{"bed floating", nil, StructureNamePalBed, Position{X: 2, Y: 2, Z: 2}, false},
+		{"bed on a foundation", func(t *testing.T, base *Base) {
+			place(t, base, "floor", StructureNameGlassFence, Position{X: 2, Z: 2})
+		}, StructureNamePalBed, Position{X: 2, Y: 1, Z: 2}, true},
+		{"bed on a barrel", func(t *testing.T, base *Base) {
+			place(t, base, "barrel", StructureNameWoodenBarrel, Position{X: 2, Z: 2})
+		}, StructureNamePalBed, Position{X: 2, Y: 1, Z: 2}, false},
+		{"bench half on a foundation", func(t *testing.T, base *Base) {
+			place(t, base, "floor", StructureNameGlassFence, Position{X: 2, Z: 2})
+		}, StructureNameWorkbench, Position{X: 2, Y: 1, Z: 2}, false},
+		{"bench wholly on foundations", func(t *testing.T, base *Base) {
+			place(t, base, "floor_a", StructureNameGlassFence, Position{X: 2, Z: 2})
+			place(t, base, "floor_b", StructureNameGlassFence, Position{X: 3, Z: 2})
+		}, StructureNameWorkbench, Position{X: 2, Y: 1, Z: 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(6, 4, 6)
+			if tt.setup != nil {
+				tt.setup(t, base)
+			}
+			item := NewItem("item", tt.item)
+			item.Position = tt.pos
+			if got := base.HasSupportBelow(item); got != tt.want {
+				t.Errorf("HasSupportBelow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package types
+
+// Axis selects the coordinate a mirror flips. AxisX mirrors about the plane
+// through the middle of the base's X extent, AxisZ about the middle of its
+// Z extent.
+type Axis int
+
+const (
+	AxisX Axis = iota
+	AxisZ
+)
+
+// mirrorCell returns the cell opposite pos across the axis
+func (b *Base) mirrorCell(pos Position, axis Axis) Position {
+	if axis == AxisZ {
+		pos.Z = b.Depth - 1 - pos.Z
+	} else {
+		pos.X = b.Width - 1 - pos.X
+	}
+	return pos
+}
+
+// MirroredPosition returns the anchor position at which a copy of the item
+// would occupy exactly the mirror image of the item's cells across the axis
+func (b *Base) MirroredPosition(item *Item, axis Axis) Position {
+	pos := item.Position
+	bounds := item.EffectiveBounds()
+	if axis == AxisZ {
+		pos.Z = b.Depth - item.Position.Z - bounds.Depth
+	} else {
+		pos.X = b.Width - item.Position.X - bounds.Width
+	}
+	return pos
+}
+
+// MirrorX returns a clone of the base mirrored across its central X plane.
+// Items, unbuildable cells and the occupancy grid are all flipped, so the
+// mirrored layout is valid whenever the original is.
+func (b *Base) MirrorX() *Base {
+	return b.mirror(AxisX)
+}
+
+// MirrorZ returns a clone of the base mirrored across its central Z plane
+func (b *Base) MirrorZ() *Base {
+	return b.mirror(AxisZ)
+}
+
+// mirror builds the mirrored clone for MirrorX and MirrorZ
+func (b *Base) mirror(axis Axis) *Base {
+	mirrored := NewBase(b.Width, b.Height, b.Depth)
+
+	for itemType, clearance := range b.ClearanceCells {
+		mirrored.ClearanceCells[itemType] = clearance
+	}
+	for pos := range b.unbuildable {
+		mirrored.unbuildable[b.mirrorCell(pos, axis)] = true
+	}
+	for pos := range b.passable {
+		mirrored.passable[b.mirrorCell(pos, axis)] = true
+	}
+	for pos := range b.reserved {
+		mirrored.reserved[b.mirrorCell(pos, axis)] = true
+	}
+
+	for id, item := range b.Items {
+		mirroredItem := *item
+		mirroredItem.Position = b.MirroredPosition(item, axis)
+		mirrored.Items[id] = &mirroredItem
+		mirrored.index.insert(&mirroredItem)
+	}
+
+	for x := 0; x < b.Width; x++ {
+		for y := 0; y < b.Height; y++ {
+			for z := 0; z < b.Depth; z++ {
+				pos := Position{X: x, Y: y, Z: z}
+				mirrored.grid.set(mirrored.cellIndex(b.mirrorCell(pos, axis)), b.grid.get(b.cellIndex(pos)))
+			}
+		}
+	}
+
+	return mirrored
+}
+
+// SymmetryScore measures how well the layout mirrors about the axis, in
+// [0,1]. Each cell occupied by a movable item counts as matched when its
+// mirrored cell is occupied by an item of the same structure category (or
+// the same type, for types without a definition). Fixed terrain is ignored.
+// An empty layout scores 0.
+func (b *Base) SymmetryScore(axis Axis) float64 {
+	occupants := make(map[Position]*Item)
+	for _, item := range b.Items {
+		if item.IsFixed() {
+			continue
+		}
+		for _, pos := range item.GetOccupiedPositions() {
+			occupants[pos] = item
+		}
+	}
+
+	if len(occupants) == 0 {
+		return 0.0
+	}
+
+	matched := 0
+	for pos, item := range occupants {
+		other, ok := occupants[b.mirrorCell(pos, axis)]
+		if ok && symmetryClass(other) == symmetryClass(item) {
+			matched++
+		}
+	}
+
+	return float64(matched) / float64(len(occupants))
+}
+
+// symmetryClass returns what two items must share to count as mirror
+// images: their structure category, or their type if it has no definition
+func symmetryClass(item *Item) string {
+	if def, err := item.Type.Definition(); err == nil {
+		return string(def.Category)
+	}
+	return string(item.Type)
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSymmetryScore(t *testing.T) {
+	type placement struct {
+		id   string
+		name StructureName
+		pos  Position
+	}
+	tests := []struct {
+		name  string
+		items []placement
+		axis  Axis
+		want  float64
+	}{
+		{"empty", nil, AxisX, 0},
+		{"mirrored across X", []placement{
+			{"bench_l", StructureNameWorkbench, Position{X: 0, Z: 1}},
+			{"bench_r", StructureNameWorkbench, Position{X: 4, Z: 1}},
+			{"bed_l", StructureNamePalBed, Position{X: 1, Z: 4}},
+			{"bed_r", StructureNamePalBed, Position{X: 4, Z: 4}},
+		}, AxisX, 1},
+		{"centered item mirrors itself", []placement{
+			{"palbox", StructureNamePalbox, Position{X: 2, Z: 2}},
+		}, AxisX, 1},
+		{"mirrored across X but not Z", []placement{
+			{"bed_l", StructureNamePalBed, Position{X: 0, Z: 0}},
+			{"bed_r", StructureNamePalBed, Position{X: 5, Z: 0}},
+		}, AxisZ, 0},
+		{"mirror image of another category", []placement{
+			{"bed", StructureNamePalBed, Position{X: 0, Z: 0}},
+			{"barrel", StructureNameWoodenBarrel, Position{X: 5, Z: 0}},
+		}, AxisX, 0},
+		{"one unmatched of three", []placement{
+			{"bed_l", StructureNamePalBed, Position{X: 0, Z: 0}},
+			{"bed_r", StructureNamePalBed, Position{X: 5, Z: 0}},
+			{"bed_extra", StructureNamePalBed, Position{X: 1, Z: 3}},
+		}, AxisX, 2.0 / 3.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(6, 2, 6)
+			for _, p := range tt.items {
+				place(t, base, p.id, p.name, p.pos)
+			}
+			if got := base.SymmetryScore(tt.axis); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("SymmetryScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMirrorX(t *testing.T) {
+	base := NewBase(6, 1, 4)
+	place(t, base, "bench", StructureNameWorkbench, Position{X: 0, Z: 1})
+	base.SetBuildable(Position{X: 5, Z: 3}, false)
+
+	mirrored := base.MirrorX()
+	if got, want := mirrored.Items["bench"].Position, (Position{X: 4, Z: 1}); got != want {
+		t.Errorf("mirrored bench at %s, want %s", got, want)
+	}
+	if base.Items["bench"].Position != (Position{X: 0, Z: 1}) {
+		t.Error("MirrorX moved the original item")
+	}
+	for _, pos := range []Position{{X: 4, Z: 1}, {X: 5, Z: 1}} {
+		if !mirrored.IsPositionOccupied(pos) {
+			t.Errorf("mirrored cell %s is free", pos)
+		}
+	}
+	if mirrored.IsPositionOccupied(Position{X: 0, Z: 1}) {
+		t.Error("the original cell is still occupied in the mirror")
+	}
+	if mirrored.IsBuildable(Position{X: 0, Z: 3}) || !mirrored.IsBuildable(Position{X: 5, Z: 3}) {
+		t.Error("unbuildable cell was not mirrored")
+	}
+
+	// A layout plus its mirror image is symmetric
+	place(t, base, "bench_mirror", StructureNameWorkbench, Position{X: 4, Z: 1})
+	if got := base.SymmetryScore(AxisX); got != 1 {
+		t.Errorf("SymmetryScore() of a layout and its mirror = %v, want 1", got)
+	}
+}
@@ -0,0 +1,51 @@
+package types
+
+import "sort"
+
+// techFallbacks maps a structure to the lower-tier structure that does the
+// same job, for players who have not unlocked it yet
+var techFallbacks = map[StructureName]StructureName{
+	StructureNameElectricKitchen:           StructureNameCookingPot,
+	StructureNameCookingPot:                StructureNameCampfire,
+	StructureNameColdFoodBox:               StructureNameFoodBox,
+	StructureNameCarrotPlantation:          StructureNameBerryPlantation,
+	StructureNameMetalDefensiveWall:        StructureNameStoneDefensiveWall,
+	StructureNameStoneDefensiveWall:        StructureNameWoodenDefensiveWall,
+	StructureNameAdvancedMedicineWorkbench: StructureNameElectricMedicineWorkbench,
+	StructureNameElectricMedicineWorkbench: StructureNameMedievalMedicineWorkbench,
+	StructureNameCeilingLamp:               StructureNameLamp,
+	StructureNameLamp:                      StructureNameJapanesePaperLantern,
+	StructureNameJapanesePaperLantern:      StructureNameStandingTorch,
+	StructureNameItemRetrievalMachine:      StructureNameStorage,
+}
+
+// AvailableStructures returns the names of the structures unlocked at
+// maxTech, sorted by name
+func AvailableStructures(maxTech int) []StructureName {
+	var names []StructureName
+	for name, def := range StructureDefinitions {
+		if def.TechLevel <= maxTech {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// SubstituteForTech returns the structure to build in place of name at
+// maxTech: name itself when it is unlocked, otherwise the first unlocked
+// structure down its chain of lower-tier fallbacks (an electric kitchen
+// falls back to a cooking pot, then a campfire). It reports false when
+// nothing in the chain is unlocked. Names without a definition are left
+// as they are.
+func SubstituteForTech(name StructureName, maxTech int) (StructureName, bool) {
+	for {
+		def, ok := StructureDefinitions[name]
+		if !ok || def.TechLevel <= maxTech {
+			return name, true
+		}
+		if name, ok = techFallbacks[name]; !ok {
+			return "", false
+		}
+	}
+}
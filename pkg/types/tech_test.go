@@ -0,0 +1,59 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAvailableStructures(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxTech         int
+		campfire        bool
+		electricKitchen bool
+	}{
+		{"nothing unlocked", 0, false, false},
+		{"low tech", 5, true, false},
+		{"just below the kitchen", 39, true, false},
+		{"kitchen unlocked", 40, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			available := AvailableStructures(tt.maxTech)
+			if got := slices.Contains(available, StructureNameCampfire); got != tt.campfire {
+				t.Errorf("campfire available = %v, want %v", got, tt.campfire)
+			}
+			if got := slices.Contains(available, StructureNameElectricKitchen); got != tt.electricKitchen {
+				t.Errorf("electric kitchen available = %v, want %v", got, tt.electricKitchen)
+			}
+			if !slices.IsSorted(available) {
+				t.Errorf("AvailableStructures(%d) is not sorted: %v", tt.maxTech, available)
+			}
+		})
+	}
+}
+
+func TestSubstituteForTech(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   StructureName
+		maxTech int
+		want    StructureName
+		ok      bool
+	}{
+		{"unlocked is kept", StructureNameElectricKitchen, 40, StructureNameElectricKitchen, true},
+		{"one step down", StructureNameElectricKitchen, 17, StructureNameCookingPot, true},
+		{"two steps down", StructureNameElectricKitchen, 5, StructureNameCampfire, true},
+		{"nothing in the chain", StructureNameElectricKitchen, 0, "", false},
+		{"no fallback", StructureNamePalbox, 0, "", false},
+		{"unknown name", StructureName("mystery"), 1, StructureName("mystery"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SubstituteForTech(tt.input, tt.maxTech)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("SubstituteForTech(%s, %d) = %q, %v, want %q, %v", tt.input, tt.maxTech, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package types
+
+import "fmt"
+
+// Slot is a free region of a template reserved for certain item types
+type Slot struct {
+	Name  string
+	Min   Position // inclusive corner of the region
+	Max   Position // inclusive opposite corner of the region
+	Types []ItemType
+}
+
+// Accepts reports whether items of the given type belong in this slot
+func (s Slot) Accepts(itemType ItemType) bool {
+	for _, t := range s.Types {
+		if t == itemType {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether the item's whole footprint lies inside the slot
+func (s Slot) Contains(item *Item) bool {
+	for _, pos := range item.GetOccupiedPositions() {
+		if pos.X < s.Min.X || pos.X > s.Max.X ||
+			pos.Y < s.Min.Y || pos.Y > s.Max.Y ||
+			pos.Z < s.Min.Z || pos.Z > s.Max.Z {
+			return false
+		}
+	}
+	return true
+}
+
+// Template is a partial layout: fixed items that never move plus slots
+// that restrict where items of the tagged types may be placed
+type Template struct {
+	Name  string
+	Fixed []*Item
+	Slots []Slot
+}
+
+// Apply places the template's fixed items into the base
+func (t *Template) Apply(base *Base) error {
+	for _, item := range t.Fixed {
+		fixed := *item
+		if err := base.PlaceItem(&fixed); err != nil {
+			return fmt.Errorf("template %s: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the item may be placed at its current position.
+// Items whose type is tagged by one or more slots must lie entirely inside
+// one of them; other items are unrestricted.
+func (t *Template) Allows(item *Item) bool {
+	restricted := false
+	for _, slot := range t.Slots {
+		if !slot.Accepts(item.Type) {
+			continue
+		}
+		if slot.Contains(item) {
+			return true
+		}
+		restricted = true
+	}
+	return !restricted
+}
@@ -0,0 +1,48 @@
+package types
+
+import "testing"
+
+func TestTemplateAllows(t *testing.T) {
+	template := &Template{Slots: []Slot{
+		{Name: "west", Min: Position{}, Max: Position{X: 2, Z: 2}, Types: []ItemType{ItemTypeFoodPlot}},
+		{Name: "east", Min: Position{X: 6}, Max: Position{X: 8, Z: 2}, Types: []ItemType{ItemTypeFoodPlot}},
+	}}
+
+	tests := []struct {
+		name     string
+		itemType ItemType
+		pos      Position
+		want     bool
+	}{
+		{"inside the first slot", ItemTypeFoodPlot, Position{X: 1, Z: 1}, true},
+		{"inside the second slot", ItemTypeFoodPlot, Position{X: 7, Z: 2}, true},
+		{"between the slots", ItemTypeFoodPlot, Position{X: 4}, false},
+		{"untagged type anywhere", ItemTypeWorkbench, Position{X: 4}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &Item{ID: "item", Type: tt.itemType, Position: tt.pos, Bounds: BoundingBox{Width: 1, Height: 1, Depth: 1}}
+			if got := template.Allows(item); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateApplyCopiesFixedItems(t *testing.T) {
+	wall := NewItem("wall", StructureNameWoodenBarrel)
+	wall.Position = Position{X: 1}
+	template := &Template{Name: "t", Fixed: []*Item{wall}}
+
+	base := NewBase(3, 1, 3)
+	if err := template.Apply(base); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if base.Items["wall"] == wall {
+		t.Error("Apply placed the template's own item rather than a copy")
+	}
+	if err := template.Apply(base); err == nil {
+		t.Error("applying the template over itself succeeded")
+	}
+}
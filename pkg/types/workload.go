@@ -0,0 +1,137 @@
+package types
+
+import "sort"
+
+// WorkingRange is how many steps a Pal walks from its bed to work. Beds and
+// workstations farther apart than this do not serve each other in
+// WorkloadBalance.
+const WorkingRange = 12
+
+// workstationCategories are the categories whose structures Pals work at:
+// crafting stations, and the plots, kitchens and food boxes they farm, cook
+// at and stock. They are listed in sorted order.
+var workstationCategories = []StructureCategory{
+	StructureCategoryFood,
+	StructureCategoryProduction,
+}
+
+// CategoryWorkload summarizes the workstations of one category
+type CategoryWorkload struct {
+	Category StructureCategory
+	Stations int
+
+	// Beds counts the Pal beds within WorkingRange of at least one of the
+	// category's stations
+	Beds int
+}
+
+// WorkloadReport is a rough analysis of how well Pal beds and workstations
+// are matched, from the walking distances between them
+type WorkloadReport struct {
+	// Categories has an entry for every workstation category present in
+	// the base, sorted by category
+	Categories []CategoryWorkload
+
+	// UnservedStations are the workstations no bed lies within
+	// WorkingRange of, and IdleBeds the beds no workstation lies within
+	// WorkingRange of. Both are sorted by ID.
+	UnservedStations []*Item
+	IdleBeds         []*Item
+}
+
+// WorkloadBalance measures, for each Pal bed, which workstations a Pal can
+// walk to within WorkingRange steps, from a traversable cell beside the bed
+// to one beside the station. Stations nobody sleeps near and beds far from
+// any work are flagged, and every workstation category reports how many
+// beds it reaches.
+func (b *Base) WorkloadBalance() WorkloadReport {
+	var report WorkloadReport
+	var stations []*Item
+	for _, category := range workstationCategories {
+		members := b.ItemsByCategory(category)
+		if len(members) > 0 {
+			report.Categories = append(report.Categories, CategoryWorkload{Category: category, Stations: len(members)})
+			stations = append(stations, members...)
+		}
+	}
+	beds := b.ItemsByName(StructureNamePalBed)
+
+	// Map each cell beside a station to the stations it serves
+	besideStation := make(map[Position][]*Item)
+	for _, station := range stations {
+		for _, pos := range b.AdjacentFreePositions(station) {
+			besideStation[pos] = append(besideStation[pos], station)
+		}
+	}
+
+	served := make(map[string]bool)
+	bedsByCategory := make(map[StructureCategory]int)
+	for _, bed := range beds {
+		reached := make(map[string]bool)
+		categories := make(map[StructureCategory]bool)
+		for _, pos := range b.walkWithin(b.AdjacentFreePositions(bed), WorkingRange) {
+			for _, station := range besideStation[pos] {
+				if reached[station.ID] {
+					continue
+				}
+				reached[station.ID] = true
+				served[station.ID] = true
+				if def, err := station.Type.Definition(); err == nil {
+					categories[def.Category] = true
+				}
+			}
+		}
+
+		if len(reached) == 0 {
+			report.IdleBeds = append(report.IdleBeds, bed)
+		}
+		for category := range categories {
+			bedsByCategory[category]++
+		}
+	}
+
+	for i := range report.Categories {
+		report.Categories[i].Beds = bedsByCategory[report.Categories[i].Category]
+	}
+
+	for _, station := range stations {
+		if !served[station.ID] {
+			report.UnservedStations = append(report.UnservedStations, station)
+		}
+	}
+	sort.Slice(report.UnservedStations, func(i, j int) bool {
+		return report.UnservedStations[i].ID < report.UnservedStations[j].ID
+	})
+
+	return report
+}
+
+// walkWithin returns the traversable cells a Pal starting on any of the
+// traversable starts reaches in at most steps moves, starts included
+func (b *Base) walkWithin(starts []Position, steps int) []Position {
+	distance := make(map[Position]int)
+	var reached []Position
+	for _, start := range starts {
+		if _, seen := distance[start]; !seen && b.IsTraversable(start) {
+			distance[start] = 0
+			reached = append(reached, start)
+		}
+	}
+
+	for i := 0; i < len(reached); i++ {
+		current := reached[i]
+		if distance[current] == steps {
+			continue
+		}
+		for _, offset := range neighborOffsets {
+			next := Position{X: current.X + offset.X, Y: current.Y + offset.Y, Z: current.Z + offset.Z}
+			if _, seen := distance[next]; seen || !b.IsTraversable(next) {
+				continue
+			}
+			distance[next] = distance[current] + 1
+			reached = append(reached, next)
+		}
+	}
+
+	return reached
+}
@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWorkloadBalance(t *testing.T) {
+	ids := func(items []*Item) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name       string
+		layout     func(t *testing.T, base *Base)
+		categories []CategoryWorkload
+		unserved   []string
+		idle       []string
+	}{
+		{"empty", func(*testing.T, *Base) {}, nil, nil, nil},
+		{
+			"beds far from all stations are idle",
+			func(t *testing.T, base *Base) {
+				place(t, base, "bench", StructureNameWorkbench, Position{X: 0, Z: 2})
+				place(t, base, "bed_a", StructureNamePalBed, Position{X: 30, Z: 1})
+				place(t, base, "bed_b", StructureNamePalBed, Position{X: 38, Z: 3})
+			},
+			[]CategoryWorkload{{Category: StructureCategoryProduction, Stations: 1, Beds: 0}},
+			[]string{"bench"},
+			[]string{"bed_a", "bed_b"},
+		},
+		{
+			"walled off from a close station",
+			func(t *testing.T, base *Base) {
+				place(t, base, "bench", StructureNameWorkbench, Position{X: 0, Z: 2})
+				for z := range 5 {
+					place(t, base, fmt.Sprintf("wall_%d", z), StructureNameWoodenBarrel, Position{X: 2, Z: z})
+				}
+				place(t, base, "bed", StructureNamePalBed, Position{X: 3, Z: 2})
+			},
+			[]CategoryWorkload{{Category: StructureCategoryProduction, Stations: 1, Beds: 0}},
+			[]string{"bench"},
+			[]string{"bed"},
+		},
+		{
+			"mixed",
+			func(t *testing.T, base *Base) {
+				place(t, base, "bench", StructureNameWorkbench, Position{X: 0, Z: 2})
+				place(t, base, "box", StructureNameFoodBox, Position{X: 5, Z: 2})
+				place(t, base, "furnace", StructureNameFurnace, Position{X: 20, Z: 2})
+				place(t, base, "bed_near", StructureNamePalBed, Position{X: 3, Z: 2})
+				place(t, base, "bed_far", StructureNamePalBed, Position{X: 38, Z: 2})
+			},
+			[]CategoryWorkload{
+				{Category: StructureCategoryFood, Stations: 1, Beds: 1},
+				{Category: StructureCategoryProduction, Stations: 2, Beds: 1},
+			},
+			[]string{"furnace"},
+			[]string{"bed_far"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := NewBase(40, 1, 5)
+			tt.layout(t, base)
+			report := base.WorkloadBalance()
+
+			if !reflect.DeepEqual(report.Categories, tt.categories) {
+				t.Errorf("Categories = %+v, want %+v", report.Categories, tt.categories)
+			}
+			if got := ids(report.UnservedStations); !reflect.DeepEqual(got, tt.unserved) {
+				t.Errorf("UnservedStations = %v, want %v", got, tt.unserved)
+			}
+			if got := ids(report.IdleBeds); !reflect.DeepEqual(got, tt.idle) {
+				t.Errorf("IdleBeds = %v, want %v", got, tt.idle)
+			}
+		})
+	}
+}